@@ -0,0 +1,599 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerState is the state of a CircuitBreaker.
+type BreakerState int
+
+const (
+	// BreakerClosed lets all calls pass.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects all calls with ErrCircuitOpen.
+	BreakerOpen
+	// BreakerHalfOpen lets a limited number of probe calls pass, to find out whether the database recovered.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// breakerBuckets is the number of buckets the rolling window is divided into.
+const breakerBuckets = 10
+
+// minBreakerWindow is the shortest rolling window, each bucket spans at least a millisecond.
+const minBreakerWindow = breakerBuckets * time.Millisecond
+
+// BreakerParams holds the parameters of the CircuitBreaker.
+type BreakerParams struct {
+	// Window is the duration of the rolling window, the error rate is calculated for, defaults to 10s.
+	// Shorter windows than 10ms are raised to 10ms.
+	Window time.Duration
+	// MinRequests is the minimum number of calls within the window, before the breaker trips, defaults to 20.
+	MinRequests int64
+	// ErrorRate is the rate of failed calls within the window, at which the breaker trips, defaults to 0.5.
+	ErrorRate float64
+	// SlowCall is the duration, after which a call counts as failed, even if it succeeded, 0 disables it.
+	SlowCall time.Duration
+	// OpenTimeout is the time the breaker stays open, before it lets probe calls pass, defaults to 30s.
+	OpenTimeout time.Duration
+	// Probes is the number of successful probe calls needed to close the breaker again, defaults to 3.
+	Probes int64
+	// IsFailure decides, whether an error counts as failure, defaults to retryable errors and timeouts.
+	// Errors like mongo.ErrNoDocuments or duplicate keys do not indicate a degraded database.
+	IsFailure func(err error) bool
+	// OnStateChange is called on each transition of the state.
+	OnStateChange func(from BreakerState, to BreakerState)
+}
+
+// BreakerMetrics is a snapshot of the CircuitBreaker.
+type BreakerMetrics struct {
+	State BreakerState
+	// Requests and Failures are the calls within the rolling window.
+	Requests int64
+	Failures int64
+	// Rejected is the total number of calls rejected with ErrCircuitOpen.
+	Rejected int64
+	// Trips is the total number of times the breaker opened.
+	Trips int64
+}
+
+type breakerBucket struct {
+	start    time.Time
+	requests int64
+	failures int64
+}
+
+// CircuitBreaker trips after the error rate or the latency of the calls exceeded a threshold and rejects all calls,
+// until probe calls succeeded after the OpenTimeout. It is safe for concurrent use.
+type CircuitBreaker struct {
+	params    BreakerParams
+	mu        sync.Mutex
+	state     BreakerState
+	buckets   [breakerBuckets]breakerBucket
+	openedAt  time.Time
+	probing   int64
+	succeeded int64
+	rejected  int64
+	trips     int64
+}
+
+// NewCircuitBreaker creates a new CircuitBreaker, which is initially closed.
+func NewCircuitBreaker(params BreakerParams) *CircuitBreaker {
+	if params.Window <= 0 {
+		params.Window = 10 * time.Second
+	}
+	params.Window = max(params.Window, minBreakerWindow)
+
+	if params.MinRequests <= 0 {
+		params.MinRequests = 20
+	}
+
+	if params.ErrorRate <= 0 || params.ErrorRate > 1 {
+		params.ErrorRate = 0.5
+	}
+
+	if params.OpenTimeout <= 0 {
+		params.OpenTimeout = 30 * time.Second
+	}
+
+	if params.Probes <= 0 {
+		params.Probes = 3
+	}
+
+	if params.IsFailure == nil {
+		params.IsFailure = func(err error) bool {
+			return IsRetryableError(err) || mongo.IsTimeout(err)
+		}
+	}
+
+	return &CircuitBreaker{params: params}
+}
+
+// State returns the current state of the breaker.
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// Metrics returns a snapshot of the state and the counters of the breaker.
+func (b *CircuitBreaker) Metrics() BreakerMetrics {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	m := BreakerMetrics{State: b.state, Rejected: b.rejected, Trips: b.trips}
+	m.Requests, m.Failures = b.totals(time.Now())
+
+	return m
+}
+
+// Execute calls fn if the breaker allows it and records the result, otherwise ErrCircuitOpen is returned.
+func (b *CircuitBreaker) Execute(fn func() error) error {
+	probe, err := b.allow()
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	err = fn()
+	b.record(probe, err, time.Since(start))
+
+	return err
+}
+
+// allow decides whether a call may pass, probe is set for the calls passed in half-open state.
+func (b *CircuitBreaker) allow() (probe bool, err error) {
+	b.mu.Lock()
+
+	var from BreakerState
+	changed := false
+	if b.state == BreakerOpen && time.Since(b.openedAt) >= b.params.OpenTimeout {
+		from, changed = b.transition(BreakerHalfOpen)
+	}
+
+	switch {
+	case b.state == BreakerClosed:
+	case b.state == BreakerHalfOpen && b.probing+b.succeeded < b.params.Probes:
+		b.probing++
+		probe = true
+	default:
+		b.rejected++
+		err = ErrCircuitOpen
+	}
+
+	b.mu.Unlock()
+
+	if changed {
+		b.notify(from, BreakerHalfOpen)
+	}
+
+	return probe, err
+}
+
+// record records the result of a call.
+func (b *CircuitBreaker) record(probe bool, err error, d time.Duration) {
+	failed := (err != nil && b.params.IsFailure(err)) || (b.params.SlowCall > 0 && d >= b.params.SlowCall)
+	now := time.Now()
+
+	b.mu.Lock()
+
+	from, to := b.state, b.state
+	changed := false
+
+	switch {
+	case probe && b.state == BreakerHalfOpen:
+		b.probing--
+		if failed {
+			to = BreakerOpen
+		} else if b.succeeded++; b.succeeded >= b.params.Probes {
+			to = BreakerClosed
+		}
+		if to != from {
+			from, changed = b.transition(to)
+		}
+	case b.state == BreakerClosed:
+		bucket := b.bucket(now)
+		bucket.requests++
+		if failed {
+			bucket.failures++
+		}
+
+		requests, failures := b.totals(now)
+		if requests >= b.params.MinRequests && float64(failures) >= b.params.ErrorRate*float64(requests) {
+			to = BreakerOpen
+			from, changed = b.transition(to)
+		}
+	}
+
+	b.mu.Unlock()
+
+	if changed {
+		b.notify(from, to)
+	}
+}
+
+// transition sets the new state, it must be called while holding the mutex.
+func (b *CircuitBreaker) transition(to BreakerState) (from BreakerState, changed bool) {
+	from = b.state
+	b.state = to
+	b.probing, b.succeeded = 0, 0
+
+	switch to {
+	case BreakerOpen:
+		b.openedAt = time.Now()
+		b.trips++
+	case BreakerClosed:
+		b.buckets = [breakerBuckets]breakerBucket{}
+	}
+
+	return from, from != to
+}
+
+func (b *CircuitBreaker) notify(from BreakerState, to BreakerState) {
+	if b.params.OnStateChange != nil {
+		b.params.OnStateChange(from, to)
+	}
+}
+
+// bucket returns the bucket of the rolling window for the given time, an outdated bucket is reset.
+func (b *CircuitBreaker) bucket(now time.Time) *breakerBucket {
+	size := b.params.Window / breakerBuckets
+	start := now.Truncate(size)
+
+	bucket := &b.buckets[(start.UnixNano()/int64(size))%breakerBuckets]
+	if !bucket.start.Equal(start) {
+		*bucket = breakerBucket{start: start}
+	}
+
+	return bucket
+}
+
+// totals sums up the calls within the rolling window.
+func (b *CircuitBreaker) totals(now time.Time) (requests int64, failures int64) {
+	for _, bucket := range b.buckets {
+		if now.Sub(bucket.start) < b.params.Window {
+			requests += bucket.requests
+			failures += bucket.failures
+		}
+	}
+
+	return requests, failures
+}
+
+// BreakerConnector is a Connector decorator guarding the operations with a CircuitBreaker, while the breaker is open,
+// the operations fail fast with ErrCircuitOpen. The breaker is shared by the connectors derived by
+// WithContext, WithCollection and WithReadPreference.
+type BreakerConnector struct {
+	Connector
+	breaker *CircuitBreaker
+}
+
+// NewBreakerConnector wraps conn into a BreakerConnector using the given breaker.
+func NewBreakerConnector(conn Connector, breaker *CircuitBreaker) *BreakerConnector {
+	return &BreakerConnector{Connector: conn, breaker: breaker}
+}
+
+// Breaker returns the circuit breaker of the connector.
+func (c *BreakerConnector) Breaker() *CircuitBreaker {
+	return c.breaker
+}
+
+// guard calls op if the breaker allows it and records the result.
+func guard[T any](c *BreakerConnector, op func() (T, error)) (T, error) {
+	probe, err := c.breaker.allow()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	start := time.Now()
+	res, err := op()
+	c.breaker.record(probe, err, time.Since(start))
+
+	return res, err
+}
+
+// singleResult guards operations returning a SingleResult.
+func (c *BreakerConnector) singleResult(op func() *mongo.SingleResult) *mongo.SingleResult {
+	res, err := guard(c, func() (*mongo.SingleResult, error) {
+		res := op()
+		if res == nil {
+			return nil, nil
+		}
+		return res, res.Err()
+	})
+
+	if errors.Is(err, ErrCircuitOpen) {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+
+	return res
+}
+
+// Context returns the context of the wrapped connector.
+func (c *BreakerConnector) Context() context.Context {
+	return contextOf(c.Connector)
+}
+
+// WithContext returns a copy of the connector with the specified context.
+func (c *BreakerConnector) WithContext(ctx context.Context) Connector {
+	return &BreakerConnector{Connector: c.Connector.WithContext(ctx), breaker: c.breaker}
+}
+
+// WithCollection returns a copy of the connector with the specified collection.
+func (c *BreakerConnector) WithCollection(coll string, opts ...options.Lister[options.CollectionOptions]) Connector {
+	return &BreakerConnector{Connector: c.Connector.WithCollection(coll, opts...), breaker: c.breaker}
+}
+
+// WithReadPreference returns a copy of the connector with the specified read preference.
+func (c *BreakerConnector) WithReadPreference(rp *readpref.ReadPref) Connector {
+	return &BreakerConnector{Connector: c.Connector.WithReadPreference(rp), breaker: c.breaker}
+}
+
+// WithCausalConsistency executes fn inside a causally consistent session, the connector passed to fn guards single operations.
+func (c *BreakerConnector) WithCausalConsistency(fn func(conn Connector) error) error {
+	return c.Connector.WithCausalConsistency(func(conn Connector) error {
+		return fn(&BreakerConnector{Connector: conn, breaker: c.breaker})
+	})
+}
+
+// CreateCollection guards CreateCollection of the wrapped connector.
+func (c *BreakerConnector) CreateCollection(name string, opts ...options.Lister[options.CreateCollectionOptions]) error {
+	_, err := guard(c, func() (struct{}, error) {
+		return struct{}{}, c.Connector.CreateCollection(name, opts...)
+	})
+	return err
+}
+
+// ListCollections guards ListCollections of the wrapped connector.
+func (c *BreakerConnector) ListCollections(filter interface{}, opts ...options.Lister[options.ListCollectionsOptions]) ([]mongo.CollectionSpecification, error) {
+	return guard(c, func() ([]mongo.CollectionSpecification, error) {
+		return c.Connector.ListCollections(filter, opts...)
+	})
+}
+
+// Find guards Find of the wrapped connector.
+func (c *BreakerConnector) Find(filter interface{}, opts ...options.Lister[options.FindOptions]) (*mongo.Cursor, error) {
+	return guard(c, func() (*mongo.Cursor, error) {
+		return c.Connector.Find(filter, opts...)
+	})
+}
+
+// FindOne guards FindOne of the wrapped connector.
+func (c *BreakerConnector) FindOne(filter interface{}, opts ...options.Lister[options.FindOneOptions]) *mongo.SingleResult {
+	return c.singleResult(func() *mongo.SingleResult {
+		return c.Connector.FindOne(filter, opts...)
+	})
+}
+
+// Count guards Count of the wrapped connector.
+func (c *BreakerConnector) Count(filter interface{}, opts ...options.Lister[options.CountOptions]) (int64, error) {
+	return guard(c, func() (int64, error) {
+		return c.Connector.Count(filter, opts...)
+	})
+}
+
+// Distinct guards Distinct of the wrapped connector.
+func (c *BreakerConnector) Distinct(fieldName string, filter interface{}, opts ...options.Lister[options.DistinctOptions]) (*mongo.DistinctResult, error) {
+	return guard(c, func() (*mongo.DistinctResult, error) {
+		return c.Connector.Distinct(fieldName, filter, opts...)
+	})
+}
+
+// FindOneAndDelete guards FindOneAndDelete of the wrapped connector.
+func (c *BreakerConnector) FindOneAndDelete(filter interface{}, opts ...options.Lister[options.FindOneAndDeleteOptions]) *mongo.SingleResult {
+	return c.singleResult(func() *mongo.SingleResult {
+		return c.Connector.FindOneAndDelete(filter, opts...)
+	})
+}
+
+// FindOneAndReplace guards FindOneAndReplace of the wrapped connector.
+func (c *BreakerConnector) FindOneAndReplace(filter interface{}, replacement interface{}, opts ...options.Lister[options.FindOneAndReplaceOptions]) *mongo.SingleResult {
+	return c.singleResult(func() *mongo.SingleResult {
+		return c.Connector.FindOneAndReplace(filter, replacement, opts...)
+	})
+}
+
+// FindOneAndUpdate guards FindOneAndUpdate of the wrapped connector.
+func (c *BreakerConnector) FindOneAndUpdate(filter interface{}, update interface{}, opts ...options.Lister[options.FindOneAndUpdateOptions]) *mongo.SingleResult {
+	return c.singleResult(func() *mongo.SingleResult {
+		return c.Connector.FindOneAndUpdate(filter, update, opts...)
+	})
+}
+
+// UpdateOne guards UpdateOne of the wrapped connector.
+func (c *BreakerConnector) UpdateOne(filter interface{}, update interface{}, opts ...options.Lister[options.UpdateOneOptions]) (*mongo.UpdateResult, error) {
+	return guard(c, func() (*mongo.UpdateResult, error) {
+		return c.Connector.UpdateOne(filter, update, opts...)
+	})
+}
+
+// UpdateMany guards UpdateMany of the wrapped connector.
+func (c *BreakerConnector) UpdateMany(filter interface{}, update interface{}, opts ...options.Lister[options.UpdateManyOptions]) (*mongo.UpdateResult, error) {
+	return guard(c, func() (*mongo.UpdateResult, error) {
+		return c.Connector.UpdateMany(filter, update, opts...)
+	})
+}
+
+// UpdateById guards UpdateById of the wrapped connector.
+func (c *BreakerConnector) UpdateById(id interface{}, update interface{}, opts ...options.Lister[options.UpdateOneOptions]) (*mongo.UpdateResult, error) {
+	return guard(c, func() (*mongo.UpdateResult, error) {
+		return c.Connector.UpdateById(id, update, opts...)
+	})
+}
+
+// ReplaceOne guards ReplaceOne of the wrapped connector.
+func (c *BreakerConnector) ReplaceOne(filter interface{}, update interface{}, opts ...options.Lister[options.ReplaceOptions]) (*mongo.UpdateResult, error) {
+	return guard(c, func() (*mongo.UpdateResult, error) {
+		return c.Connector.ReplaceOne(filter, update, opts...)
+	})
+}
+
+// InsertOne guards InsertOne of the wrapped connector.
+func (c *BreakerConnector) InsertOne(document interface{}, opts ...options.Lister[options.InsertOneOptions]) (*mongo.InsertOneResult, error) {
+	return guard(c, func() (*mongo.InsertOneResult, error) {
+		return c.Connector.InsertOne(document, opts...)
+	})
+}
+
+// InsertMany guards InsertMany of the wrapped connector.
+func (c *BreakerConnector) InsertMany(document []interface{}, opts ...options.Lister[options.InsertManyOptions]) (*mongo.InsertManyResult, error) {
+	return guard(c, func() (*mongo.InsertManyResult, error) {
+		return c.Connector.InsertMany(document, opts...)
+	})
+}
+
+// DeleteOne guards DeleteOne of the wrapped connector.
+func (c *BreakerConnector) DeleteOne(filter interface{}, opts ...options.Lister[options.DeleteOneOptions]) (*mongo.DeleteResult, error) {
+	return guard(c, func() (*mongo.DeleteResult, error) {
+		return c.Connector.DeleteOne(filter, opts...)
+	})
+}
+
+// DeleteMany guards DeleteMany of the wrapped connector.
+func (c *BreakerConnector) DeleteMany(filter interface{}, opts ...options.Lister[options.DeleteManyOptions]) (*mongo.DeleteResult, error) {
+	return guard(c, func() (*mongo.DeleteResult, error) {
+		return c.Connector.DeleteMany(filter, opts...)
+	})
+}
+
+// Aggregate guards Aggregate of the wrapped connector.
+func (c *BreakerConnector) Aggregate(pipeline interface{}, opts ...options.Lister[options.AggregateOptions]) (*mongo.Cursor, error) {
+	return guard(c, func() (*mongo.Cursor, error) {
+		return c.Connector.Aggregate(pipeline, opts...)
+	})
+}
+
+// CreateIndex guards CreateIndex of the wrapped connector.
+func (c *BreakerConnector) CreateIndex(model mongo.IndexModel, opts ...options.Lister[options.CreateIndexesOptions]) (string, error) {
+	return guard(c, func() (string, error) {
+		return c.Connector.CreateIndex(model, opts...)
+	})
+}
+
+// CreateSearchIndex guards CreateSearchIndex of the wrapped connector.
+func (c *BreakerConnector) CreateSearchIndex(model mongo.SearchIndexModel, opts ...options.Lister[options.CreateSearchIndexesOptions]) (string, error) {
+	return guard(c, func() (string, error) {
+		return c.Connector.CreateSearchIndex(model, opts...)
+	})
+}
+
+// CreateView guards CreateView of the wrapped connector.
+func (c *BreakerConnector) CreateView(name string, source string, pipeline interface{}, opts ...options.Lister[options.CreateViewOptions]) error {
+	_, err := guard(c, func() (struct{}, error) {
+		return struct{}{}, c.Connector.CreateView(name, source, pipeline, opts...)
+	})
+	return err
+}
+
+// Drop guards Drop of the wrapped connector.
+func (c *BreakerConnector) Drop() error {
+	_, err := guard(c, func() (struct{}, error) {
+		return struct{}{}, c.Connector.Drop()
+	})
+	return err
+}
+
+// SetValidator guards SetValidator of the wrapped connector.
+func (c *BreakerConnector) SetValidator(validator interface{}, level string, action string) error {
+	_, err := guard(c, func() (struct{}, error) {
+		return struct{}{}, c.Connector.SetValidator(validator, level, action)
+	})
+	return err
+}
+
+// RunCommand guards RunCommand of the wrapped connector.
+func (c *BreakerConnector) RunCommand(cmd interface{}, opts ...options.Lister[options.RunCmdOptions]) *mongo.SingleResult {
+	return c.singleResult(func() *mongo.SingleResult {
+		return c.Connector.RunCommand(cmd, opts...)
+	})
+}
+
+// WithTransaction guards the whole transaction, the operations inside the transaction are not guarded separately.
+func (c *BreakerConnector) WithTransaction(fn func(conn Connector) error, opts ...options.Lister[options.TransactionOptions]) error {
+	_, err := guard(c, func() (struct{}, error) {
+		return struct{}{}, c.Connector.WithTransaction(fn, opts...)
+	})
+	return err
+}
+
+// Watch guards Watch of the wrapped connector.
+func (c *BreakerConnector) Watch(pipeline interface{}, opts ...options.Lister[options.ChangeStreamOptions]) (*mongo.ChangeStream, error) {
+	return guard(c, func() (*mongo.ChangeStream, error) {
+		return c.Connector.Watch(pipeline, opts...)
+	})
+}
+
+// WatchDatabase guards WatchDatabase of the wrapped connector.
+func (c *BreakerConnector) WatchDatabase(pipeline interface{}, opts ...options.Lister[options.ChangeStreamOptions]) (*mongo.ChangeStream, error) {
+	return guard(c, func() (*mongo.ChangeStream, error) {
+		return c.Connector.WatchDatabase(pipeline, opts...)
+	})
+}
+
+// WatchDeployment guards WatchDeployment of the wrapped connector.
+func (c *BreakerConnector) WatchDeployment(pipeline interface{}, opts ...options.Lister[options.ChangeStreamOptions]) (*mongo.ChangeStream, error) {
+	return guard(c, func() (*mongo.ChangeStream, error) {
+		return c.Connector.WatchDeployment(pipeline, opts...)
+	})
+}
+
+// GetNextSeq guards GetNextSeq of the wrapped connector.
+func (c *BreakerConnector) GetNextSeq(name string, opts ...*SeqOptions) (int64, error) {
+	return guard(c, func() (int64, error) {
+		return c.Connector.GetNextSeq(name, opts...)
+	})
+}
+
+// GetNextSeqRange guards GetNextSeqRange of the wrapped connector.
+func (c *BreakerConnector) GetNextSeqRange(name string, n int64, opts ...*SeqOptions) (int64, error) {
+	return guard(c, func() (int64, error) {
+		return c.Connector.GetNextSeqRange(name, n, opts...)
+	})
+}
+
+// CurrentSeq guards CurrentSeq of the wrapped connector.
+func (c *BreakerConnector) CurrentSeq(name string, opts ...*SeqOptions) (int64, error) {
+	return guard(c, func() (int64, error) {
+		return c.Connector.CurrentSeq(name, opts...)
+	})
+}
+
+// ResetSeq guards ResetSeq of the wrapped connector.
+func (c *BreakerConnector) ResetSeq(name string, opts ...*SeqOptions) error {
+	_, err := guard(c, func() (struct{}, error) {
+		return struct{}{}, c.Connector.ResetSeq(name, opts...)
+	})
+	return err
+}
+
+// SetSeq guards SetSeq of the wrapped connector.
+func (c *BreakerConnector) SetSeq(name string, value int64, opts ...*SeqOptions) error {
+	_, err := guard(c, func() (struct{}, error) {
+		return struct{}{}, c.Connector.SetSeq(name, value, opts...)
+	})
+	return err
+}
@@ -0,0 +1,212 @@
+package mongodb_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mbretter/go-mongodb/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type transition struct {
+	from mongodb.BreakerState
+	to   mongodb.BreakerState
+}
+
+func newTestBreaker(transitions *[]transition) *mongodb.CircuitBreaker {
+	return mongodb.NewCircuitBreaker(mongodb.BreakerParams{
+		MinRequests: 4,
+		OpenTimeout: 10 * time.Millisecond,
+		Probes:      2,
+		OnStateChange: func(from mongodb.BreakerState, to mongodb.BreakerState) {
+			*transitions = append(*transitions, transition{from, to})
+		},
+	})
+}
+
+func succeed() error {
+	return nil
+}
+
+func fail() error {
+	return errWriteConflict
+}
+
+func TestCircuitBreaker_Trip(t *testing.T) {
+	var transitions []transition
+	b := newTestBreaker(&transitions)
+
+	assert.Nil(t, b.Execute(succeed))
+	assert.Nil(t, b.Execute(succeed))
+	assert.Equal(t, errWriteConflict, b.Execute(fail))
+	assert.Equal(t, mongodb.BreakerClosed, b.State())
+	assert.Equal(t, errWriteConflict, b.Execute(fail))
+	assert.Equal(t, mongodb.BreakerOpen, b.State())
+
+	err := b.Execute(func() error {
+		t.Fatal("open breaker must not call")
+		return nil
+	})
+
+	assert.True(t, errors.Is(err, mongodb.ErrCircuitOpen))
+	assert.Equal(t, mongodb.BreakerMetrics{
+		State:    mongodb.BreakerOpen,
+		Requests: 4,
+		Failures: 2,
+		Rejected: 1,
+		Trips:    1,
+	}, b.Metrics())
+	assert.Equal(t, []transition{{mongodb.BreakerClosed, mongodb.BreakerOpen}}, transitions)
+}
+
+func TestCircuitBreaker_ShortWindow(t *testing.T) {
+	b := mongodb.NewCircuitBreaker(mongodb.BreakerParams{Window: 5, MinRequests: 1})
+
+	assert.Equal(t, errWriteConflict, b.Execute(fail))
+	assert.Equal(t, mongodb.BreakerOpen, b.State())
+}
+
+func TestCircuitBreaker_IgnoredErrors(t *testing.T) {
+	var transitions []transition
+	b := newTestBreaker(&transitions)
+
+	for range 10 {
+		assert.Equal(t, mongo.ErrNoDocuments, b.Execute(func() error { return mongo.ErrNoDocuments }))
+	}
+
+	assert.Equal(t, mongodb.BreakerClosed, b.State())
+	assert.Equal(t, int64(0), b.Metrics().Failures)
+}
+
+func TestCircuitBreaker_SlowCalls(t *testing.T) {
+	b := mongodb.NewCircuitBreaker(mongodb.BreakerParams{MinRequests: 2, SlowCall: time.Millisecond})
+
+	for range 2 {
+		assert.Nil(t, b.Execute(func() error {
+			time.Sleep(2 * time.Millisecond)
+			return nil
+		}))
+	}
+
+	assert.Equal(t, mongodb.BreakerOpen, b.State())
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	var transitions []transition
+	b := newTestBreaker(&transitions)
+
+	for range 4 {
+		_ = b.Execute(fail)
+	}
+	time.Sleep(15 * time.Millisecond)
+
+	// only the configured number of probes may pass concurrently
+	assert.Nil(t, b.Execute(func() error {
+		assert.Equal(t, mongodb.BreakerHalfOpen, b.State())
+		assert.Nil(t, b.Execute(succeed))
+		assert.True(t, errors.Is(b.Execute(succeed), mongodb.ErrCircuitOpen))
+		return nil
+	}))
+
+	assert.Equal(t, mongodb.BreakerClosed, b.State())
+	assert.Equal(t, []transition{
+		{mongodb.BreakerClosed, mongodb.BreakerOpen},
+		{mongodb.BreakerOpen, mongodb.BreakerHalfOpen},
+		{mongodb.BreakerHalfOpen, mongodb.BreakerClosed},
+	}, transitions)
+	assert.Equal(t, int64(0), b.Metrics().Requests)
+}
+
+func TestCircuitBreaker_HalfOpenFailed(t *testing.T) {
+	var transitions []transition
+	b := newTestBreaker(&transitions)
+
+	for range 4 {
+		_ = b.Execute(fail)
+	}
+	time.Sleep(15 * time.Millisecond)
+
+	assert.Equal(t, errWriteConflict, b.Execute(fail))
+	assert.Equal(t, mongodb.BreakerOpen, b.State())
+	assert.Equal(t, int64(2), b.Metrics().Trips)
+}
+
+func TestBreakerConnector(t *testing.T) {
+	breaker := mongodb.NewCircuitBreaker(mongodb.BreakerParams{MinRequests: 1})
+
+	conn := NewConnectorMock(t)
+	conn.EXPECT().WithCollection("Users").Return(conn)
+	conn.EXPECT().FindOne(bson.D{{"_id", "foo"}}).
+		Return(mongo.NewSingleResultFromDocument(bson.D{}, errWriteConflict, nil)).Once()
+
+	breakerConn := mongodb.NewBreakerConnector(conn, breaker).WithCollection("Users")
+
+	assert.Equal(t, errWriteConflict, breakerConn.FindOne(bson.D{{"_id", "foo"}}).Err())
+	assert.Equal(t, mongodb.ErrCircuitOpen, breakerConn.FindOne(bson.D{{"_id", "foo"}}).Err())
+
+	_, err := breakerConn.InsertOne(bson.D{{"_id", "foo"}})
+	assert.Equal(t, mongodb.ErrCircuitOpen, err)
+	assert.Equal(t, int64(2), breaker.Metrics().Rejected)
+}
+
+func TestBreakerConnector_Open(t *testing.T) {
+	breaker := mongodb.NewCircuitBreaker(mongodb.BreakerParams{MinRequests: 1})
+	_ = breaker.Execute(func() error { return errWriteConflict })
+
+	breakerConn := mongodb.NewBreakerConnector(NewConnectorMock(t), breaker)
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"CreateView", func() error {
+			return breakerConn.CreateView("UsersView", "Users", mongo.Pipeline{})
+		}},
+		{"CreateSearchIndex", func() error {
+			_, err := breakerConn.CreateSearchIndex(mongo.SearchIndexModel{})
+			return err
+		}},
+		{"Drop", func() error {
+			return breakerConn.Drop()
+		}},
+		{"SetValidator", func() error {
+			return breakerConn.SetValidator(bson.M{}, "strict", "error")
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, mongodb.ErrCircuitOpen, test.call())
+		})
+	}
+
+	assert.Equal(t, int64(len(tests)), breaker.Metrics().Rejected)
+}
+
+func TestBreakerState_String(t *testing.T) {
+	assert.Equal(t, "closed", mongodb.BreakerClosed.String())
+	assert.Equal(t, "open", mongodb.BreakerOpen.String())
+	assert.Equal(t, "half-open", mongodb.BreakerHalfOpen.String())
+}
+
+func TestBreakerConnector_WithCausalConsistency(t *testing.T) {
+	breaker := mongodb.NewCircuitBreaker(mongodb.BreakerParams{MinRequests: 1})
+
+	conn := NewConnectorMock(t)
+	conn.EXPECT().WithCausalConsistency(mock.Anything).RunAndReturn(func(fn func(conn mongodb.Connector) error) error {
+		return fn(conn)
+	}).Once()
+	conn.EXPECT().Count(bson.D{}).Return(0, errWriteConflict).Once()
+
+	err := mongodb.NewBreakerConnector(conn, breaker).WithCausalConsistency(func(sess mongodb.Connector) error {
+		_, err := sess.Count(bson.D{})
+		return err
+	})
+
+	assert.Equal(t, errWriteConflict, err)
+	assert.Equal(t, mongodb.BreakerOpen, breaker.State())
+}
@@ -0,0 +1,296 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// WatchScope defines the level on which a ChangeStreamConsumer watches for changes.
+type WatchScope int
+
+const (
+	// ScopeCollection watches the collection of the connector.
+	ScopeCollection WatchScope = iota
+	// ScopeDatabase watches all collections of the database.
+	ScopeDatabase
+	// ScopeDeployment watches all databases of the deployment.
+	ScopeDeployment
+)
+
+// ChangeNamespace holds the database and collection a change event belongs to.
+type ChangeNamespace struct {
+	Database   string `bson:"db"`
+	Collection string `bson:"coll"`
+}
+
+// UpdateDescription describes the fields changed by an update operation.
+type UpdateDescription struct {
+	UpdatedFields   bson.M   `bson:"updatedFields"`
+	RemovedFields   []string `bson:"removedFields"`
+	TruncatedArrays []bson.M `bson:"truncatedArrays,omitempty"`
+}
+
+// ChangeEvent is a decoded change stream event, the full document is decoded into T.
+type ChangeEvent[T any] struct {
+	ResumeToken       bson.Raw           `bson:"_id"`
+	OperationType     string             `bson:"operationType"`
+	Namespace         ChangeNamespace    `bson:"ns"`
+	DocumentKey       bson.M             `bson:"documentKey"`
+	FullDocument      *T                 `bson:"fullDocument"`
+	UpdateDescription *UpdateDescription `bson:"updateDescription"`
+	ClusterTime       bson.Timestamp     `bson:"clusterTime"`
+	WallTime          time.Time          `bson:"wallTime"`
+}
+
+// ResumeTokenStore persists the resume tokens of change stream consumers by their name.
+// LoadResumeToken returns nil, if no token has been stored yet.
+type ResumeTokenStore interface {
+	LoadResumeToken(ctx context.Context, name string) (bson.Raw, error)
+	SaveResumeToken(ctx context.Context, name string, token bson.Raw) error
+}
+
+// CollectionResumeTokenStore is a ResumeTokenStore storing the tokens into a collection.
+type CollectionResumeTokenStore struct {
+	conn Connector
+}
+
+// resumeTokenRecord is the document stored by the CollectionResumeTokenStore.
+type resumeTokenRecord struct {
+	Name      string    `bson:"_id"`
+	Token     bson.Raw  `bson:"Token"`
+	UpdatedAt time.Time `bson:"UpdatedAt"`
+}
+
+// NewCollectionResumeTokenStore creates a ResumeTokenStore using the given collection, defaults to "ResumeTokens".
+// The _id of the stored documents is the name of the consumer.
+func NewCollectionResumeTokenStore(conn Connector, collection string) *CollectionResumeTokenStore {
+	if len(collection) == 0 {
+		collection = "ResumeTokens"
+	}
+
+	return &CollectionResumeTokenStore{conn: conn.WithCollection(collection)}
+}
+
+// LoadResumeToken returns the stored resume token of the named consumer, or nil if there is none.
+func (s *CollectionResumeTokenStore) LoadResumeToken(ctx context.Context, name string) (bson.Raw, error) {
+	var rec resumeTokenRecord
+
+	err := s.conn.WithContext(ctx).FindOne(bson.D{{"_id", name}}).Decode(&rec)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rec.Token, nil
+}
+
+// SaveResumeToken stores the resume token of the named consumer.
+func (s *CollectionResumeTokenStore) SaveResumeToken(ctx context.Context, name string, token bson.Raw) error {
+	_, err := s.conn.WithContext(ctx).UpdateOne(
+		bson.D{{"_id", name}},
+		bson.D{{"$set", bson.D{{"Token", token}, {"UpdatedAt", time.Now().UTC()}}}},
+		options.UpdateOne().SetUpsert(true))
+
+	return err
+}
+
+// ChangeStreamParams holds the parameters of a ChangeStreamConsumer.
+type ChangeStreamParams struct {
+	// Name identifies the consumer in the resume token store.
+	Name string
+	// Scope defines whether the collection of the connector, the database or the deployment is watched.
+	Scope WatchScope
+	// Pipeline filters or transforms the events, defaults to an empty pipeline.
+	Pipeline interface{}
+	// FullDocument e.g. options.UpdateLookup, to get the full document of update events.
+	FullDocument options.FullDocument
+	// Store persists the resume tokens, if nil, the stream resumes in memory only.
+	Store ResumeTokenStore
+	// MinBackoff and MaxBackoff bound the exponential backoff on restarts, default to 1s and 1m.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// OnError is called with each error causing a restart of the stream and the backoff before the restart.
+	OnError func(err error, backoff time.Duration)
+}
+
+// ChangeStreamConsumer consumes a change stream by decoding the events and passing them to a handler.
+// After the handler succeeded, the resume token of the event is persisted, so the consumer continues
+// after the last handled event when restarted. Transient errors restart the stream using exponential backoff.
+type ChangeStreamConsumer[T any] struct {
+	conn    Connector
+	params  ChangeStreamParams
+	handler func(event ChangeEvent[T]) error
+	token   bson.Raw
+}
+
+// fatalError marks errors, which stop the consumer instead of restarting the stream.
+type fatalError struct {
+	err error
+}
+
+func (e fatalError) Error() string {
+	return e.err.Error()
+}
+
+func (e fatalError) Unwrap() error {
+	return e.err
+}
+
+// NewChangeStreamConsumer creates a new consumer passing the events to handler.
+func NewChangeStreamConsumer[T any](conn Connector, params ChangeStreamParams, handler func(event ChangeEvent[T]) error) *ChangeStreamConsumer[T] {
+	if params.Pipeline == nil {
+		params.Pipeline = mongo.Pipeline{}
+	}
+
+	if params.MinBackoff <= 0 {
+		params.MinBackoff = time.Second
+	}
+
+	if params.MaxBackoff < params.MinBackoff {
+		params.MaxBackoff = max(time.Minute, params.MinBackoff)
+	}
+
+	return &ChangeStreamConsumer[T]{
+		conn:    conn,
+		params:  params,
+		handler: handler,
+	}
+}
+
+// Run consumes the change stream until the context is done, which is a graceful stop and returns nil.
+// An error is returned if the handler fails, an event can not be decoded, or the stream can not be resumed,
+// e.g. if the resume token is not in the oplog anymore. Only transient errors, like network errors, timeouts and
+// elections restart the stream, any other error, e.g. an authorization error or an invalid pipeline, is returned.
+func (c *ChangeStreamConsumer[T]) Run(ctx context.Context) error {
+	conn := c.conn.WithContext(ctx)
+	backoff := time.Duration(0)
+
+	for {
+		handled, err := c.consume(ctx, conn)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		var fatal fatalError
+		if errors.As(err, &fatal) {
+			return fatal.err
+		}
+
+		if handled > 0 {
+			backoff = 0
+		}
+		backoff = nextBackoff(backoff, c.params.MinBackoff, c.params.MaxBackoff)
+
+		if err != nil && c.params.OnError != nil {
+			c.params.OnError(err, backoff)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// consume opens the stream and handles the events until the stream fails, it returns the number of handled events.
+func (c *ChangeStreamConsumer[T]) consume(ctx context.Context, conn Connector) (handled int, err error) {
+	if c.token == nil && c.params.Store != nil {
+		c.token, err = c.params.Store.LoadResumeToken(ctx, c.params.Name)
+		if err != nil {
+			return 0, classifyStreamError(err)
+		}
+	}
+
+	opts := options.ChangeStream()
+	if len(c.params.FullDocument) > 0 {
+		opts.SetFullDocument(c.params.FullDocument)
+	}
+	if c.token != nil {
+		opts.SetStartAfter(c.token)
+	}
+
+	var stream *mongo.ChangeStream
+	switch c.params.Scope {
+	case ScopeDatabase:
+		stream, err = conn.WatchDatabase(c.params.Pipeline, opts)
+	case ScopeDeployment:
+		stream, err = conn.WatchDeployment(c.params.Pipeline, opts)
+	default:
+		stream, err = conn.Watch(c.params.Pipeline, opts)
+	}
+	if err != nil {
+		return 0, classifyStreamError(err)
+	}
+	defer func() {
+		_ = stream.Close(context.WithoutCancel(ctx))
+	}()
+
+	for stream.Next(ctx) {
+		var event ChangeEvent[T]
+		if err := stream.Decode(&event); err != nil {
+			return handled, fatalError{err}
+		}
+
+		if err := c.handler(event); err != nil {
+			return handled, fatalError{err}
+		}
+
+		c.token = stream.ResumeToken()
+		if c.params.Store != nil {
+			if err := c.params.Store.SaveResumeToken(ctx, c.params.Name, c.token); err != nil {
+				return handled, classifyStreamError(err)
+			}
+		}
+
+		handled++
+	}
+
+	return handled, classifyStreamError(stream.Err())
+}
+
+// classifyStreamError marks errors as fatal, if they can not be resolved by restarting the stream.
+// Network errors, timeouts, retryable errors and errors labeled ResumableChangeStreamError are transient,
+// except if the resume token is not in the oplog anymore.
+func classifyStreamError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var se mongo.ServerError
+	isServerError := errors.As(err, &se)
+	if isServerError && (se.HasErrorCode(286) || se.HasErrorCode(280)) { // ChangeStreamHistoryLost, ChangeStreamFatalError
+		return fatalError{err}
+	}
+
+	if IsNetworkError(err) || IsTimeout(err) || IsRetryableError(err) {
+		return err
+	}
+
+	if isServerError && se.HasErrorLabel("ResumableChangeStreamError") {
+		return err
+	}
+
+	return fatalError{err}
+}
+
+// nextBackoff doubles the current backoff bounded by lower and upper.
+func nextBackoff(cur time.Duration, lower time.Duration, upper time.Duration) time.Duration {
+	if cur < lower {
+		return lower
+	}
+
+	if cur > upper/2 {
+		return upper
+	}
+
+	return cur * 2
+}
@@ -0,0 +1,180 @@
+package mongodb_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mbretter/go-mongodb/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestChangeEvent_Decode(t *testing.T) {
+	oId, _ := bson.ObjectIDFromHex("66cc9ca8c042f7a732b7fc2a")
+
+	raw, _ := bson.Marshal(bson.D{
+		{"_id", bson.D{{"_data", "826"}}},
+		{"operationType", "update"},
+		{"ns", bson.D{{"db", "mydb"}, {"coll", "Users"}}},
+		{"documentKey", bson.D{{"_id", oId}}},
+		{"fullDocument", bson.D{{"_id", oId}, {"username", "foo"}}},
+		{"updateDescription", bson.D{{"updatedFields", bson.D{{"username", "foo"}}}, {"removedFields", bson.A{"email"}}}},
+	})
+
+	var event mongodb.ChangeEvent[User]
+	err := bson.Unmarshal(raw, &event)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "update", event.OperationType)
+	assert.Equal(t, mongodb.ChangeNamespace{Database: "mydb", Collection: "Users"}, event.Namespace)
+	assert.Equal(t, bson.M{"_id": oId}, event.DocumentKey)
+	assert.Equal(t, "foo", event.FullDocument.Username)
+	assert.Equal(t, bson.M{"username": "foo"}, event.UpdateDescription.UpdatedFields)
+	assert.Equal(t, []string{"email"}, event.UpdateDescription.RemovedFields)
+	assert.Equal(t, "826", event.ResumeToken.Lookup("_data").StringValue())
+}
+
+func TestCollectionResumeTokenStore_Load(t *testing.T) {
+	token, _ := bson.Marshal(bson.D{{"_data", "826"}})
+
+	conn := NewConnectorMock(t)
+	conn.EXPECT().WithCollection("ResumeTokens").Return(conn)
+	conn.EXPECT().WithContext(context.TODO()).Return(conn)
+	conn.EXPECT().FindOne(bson.D{{"_id", "users"}}).
+		Return(mongo.NewSingleResultFromDocument(bson.D{{"_id", "users"}, {"Token", bson.Raw(token)}}, nil, nil))
+
+	store := mongodb.NewCollectionResumeTokenStore(conn, "")
+	loaded, err := store.LoadResumeToken(context.TODO(), "users")
+
+	assert.Nil(t, err)
+	assert.Equal(t, bson.Raw(token), loaded)
+}
+
+func TestCollectionResumeTokenStore_LoadNotFound(t *testing.T) {
+	conn := NewConnectorMock(t)
+	conn.EXPECT().WithCollection("Tokens").Return(conn)
+	conn.EXPECT().WithContext(context.TODO()).Return(conn)
+	conn.EXPECT().FindOne(bson.D{{"_id", "users"}}).
+		Return(mongo.NewSingleResultFromDocument(bson.D{}, mongo.ErrNoDocuments, nil))
+
+	store := mongodb.NewCollectionResumeTokenStore(conn, "Tokens")
+	loaded, err := store.LoadResumeToken(context.TODO(), "users")
+
+	assert.Nil(t, err)
+	assert.Nil(t, loaded)
+}
+
+func TestCollectionResumeTokenStore_Save(t *testing.T) {
+	token, _ := bson.Marshal(bson.D{{"_data", "826"}})
+
+	conn := NewConnectorMock(t)
+	conn.EXPECT().WithCollection("ResumeTokens").Return(conn)
+	conn.EXPECT().WithContext(context.TODO()).Return(conn)
+	conn.EXPECT().UpdateOne(bson.D{{"_id", "users"}}, mock.Anything, mock.Anything).
+		Return(&mongo.UpdateResult{}, nil)
+
+	store := mongodb.NewCollectionResumeTokenStore(conn, "")
+
+	assert.Nil(t, store.SaveResumeToken(context.TODO(), "users", token))
+}
+
+func TestChangeStreamConsumer_RunFatal(t *testing.T) {
+	ctx := context.Background()
+
+	conn := NewConnectorMock(t)
+	conn.EXPECT().WithContext(ctx).Return(conn)
+	conn.EXPECT().Watch(mongo.Pipeline{}, mock.Anything).Return(nil, mongodb.ErrNoCollectionSet)
+
+	consumer := mongodb.NewChangeStreamConsumer(conn, mongodb.ChangeStreamParams{},
+		func(event mongodb.ChangeEvent[User]) error { return nil })
+
+	err := consumer.Run(ctx)
+	assert.True(t, errors.Is(err, mongodb.ErrNoCollectionSet))
+}
+
+func TestChangeStreamConsumer_RunPermanent(t *testing.T) {
+	ctx := context.Background()
+
+	watchErr := mongo.CommandError{Code: 13, Name: "Unauthorized"}
+
+	conn := NewConnectorMock(t)
+	conn.EXPECT().WithContext(ctx).Return(conn)
+	conn.EXPECT().WatchDatabase(mongo.Pipeline{}, mock.Anything).Return(nil, watchErr).Once()
+
+	consumer := mongodb.NewChangeStreamConsumer(conn, mongodb.ChangeStreamParams{
+		Scope: mongodb.ScopeDatabase,
+		OnError: func(err error, backoff time.Duration) {
+			t.Errorf("unexpected retry: %v", err)
+		},
+	}, func(event mongodb.ChangeEvent[User]) error { return nil })
+
+	err := consumer.Run(ctx)
+	assert.Equal(t, watchErr, err)
+}
+
+func TestChangeStreamConsumer_RunBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watchErr := mongo.CommandError{Code: 189, Name: "PrimarySteppedDown"}
+
+	conn := NewConnectorMock(t)
+	conn.EXPECT().WithContext(ctx).Return(conn)
+	conn.EXPECT().WatchDatabase(mongo.Pipeline{}, mock.Anything).Return(nil, watchErr)
+
+	var backoffs []time.Duration
+	consumer := mongodb.NewChangeStreamConsumer(conn, mongodb.ChangeStreamParams{
+		Scope:      mongodb.ScopeDatabase,
+		MinBackoff: time.Millisecond,
+		MaxBackoff: 4 * time.Millisecond,
+		OnError: func(err error, backoff time.Duration) {
+			assert.Equal(t, watchErr, err)
+			backoffs = append(backoffs, backoff)
+			if len(backoffs) == 5 {
+				cancel()
+			}
+		},
+	}, func(event mongodb.ChangeEvent[User]) error { return nil })
+
+	err := consumer.Run(ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond,
+		4 * time.Millisecond, 4 * time.Millisecond}, backoffs)
+}
+
+type failingTokenStore struct{}
+
+func (failingTokenStore) LoadResumeToken(context.Context, string) (bson.Raw, error) {
+	return nil, fmt.Errorf("load failed: %w", mongodb.ErrNetwork)
+}
+
+func (failingTokenStore) SaveResumeToken(context.Context, string, bson.Raw) error {
+	return nil
+}
+
+func TestChangeStreamConsumer_RunStoreError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conn := NewConnectorMock(t)
+	conn.EXPECT().WithContext(ctx).Return(conn)
+
+	consumer := mongodb.NewChangeStreamConsumer(conn, mongodb.ChangeStreamParams{
+		Name:       "users",
+		Scope:      mongodb.ScopeDeployment,
+		Store:      failingTokenStore{},
+		MinBackoff: time.Millisecond,
+		OnError: func(err error, backoff time.Duration) {
+			assert.True(t, errors.Is(err, mongodb.ErrNetwork))
+			cancel()
+		},
+	}, func(event mongodb.ChangeEvent[User]) error { return nil })
+
+	assert.Nil(t, consumer.Run(ctx))
+}
@@ -0,0 +1,228 @@
+// Command mongodb-migrate runs the JSON migrations of a directory against a database by using the migrate package.
+//
+// Usage:
+//
+//	mongodb-migrate [flags] up
+//	mongodb-migrate [flags] down [N]
+//	mongodb-migrate [flags] status
+//	mongodb-migrate [flags] create <name>
+//	mongodb-migrate [flags] force <version>
+//
+// Only JSON migrations are run, migrations created with -format go have to be registered by the application.
+//
+// The connection settings are read from the flags, or if not given, from the environment variables
+// MONGODB_URI, MONGODB_DB, MONGODB_MIGRATIONS_DIR and MONGODB_MIGRATIONS_COLLECTION.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mbretter/go-mongodb/v2"
+	"github.com/mbretter/go-mongodb/v2/migrate"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "mongodb-migrate: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+type config struct {
+	uri        string
+	database   string
+	dir        string
+	collection string
+	format     string
+	dryRun     bool
+}
+
+// run executes the command, the results are written to out, the usage and the flag errors to errOut.
+func run(args []string, out io.Writer, errOut io.Writer) (err error) {
+	var cfg config
+
+	fs := flag.NewFlagSet("mongodb-migrate", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+	// the uri might contain credentials, it is not used as default, which would be printed by the usage
+	fs.StringVar(&cfg.uri, "uri", "", "mongodb connection uri [MONGODB_URI]")
+	fs.StringVar(&cfg.database, "db", os.Getenv("MONGODB_DB"), "database name [MONGODB_DB]")
+	fs.StringVar(&cfg.dir, "dir", envDefault("MONGODB_MIGRATIONS_DIR", "migrations"), "migrations directory [MONGODB_MIGRATIONS_DIR]")
+	fs.StringVar(&cfg.collection, "collection", os.Getenv("MONGODB_MIGRATIONS_COLLECTION"), "collection tracking the applied migrations [MONGODB_MIGRATIONS_COLLECTION]")
+	fs.StringVar(&cfg.format, "format", migrate.FormatJson, "format of created migrations, json or go, go migrations have to be registered by the application, this command runs json migrations only")
+	fs.BoolVar(&cfg.dryRun, "dry-run", false, "print the migrations without executing them")
+	fs.Usage = func() {
+		fmt.Fprintln(errOut, "usage: mongodb-migrate [flags] up | down [N] | status | create <name> | force <version>")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if cfg.uri == "" {
+		cfg.uri = os.Getenv("MONGODB_URI")
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("missing command")
+	}
+
+	cmd, cmdArgs := fs.Arg(0), fs.Args()[1:]
+
+	if cmd == "create" {
+		if len(cmdArgs) != 1 {
+			return errors.New("create requires a name")
+		}
+
+		path, err := migrate.Scaffold(cfg.dir, cmdArgs[0], cfg.format, time.Now())
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "created %s\n", path)
+		if cfg.format == migrate.FormatGo {
+			fmt.Fprintln(out, "go migrations are not loaded by mongodb-migrate, register them with the migrator of the application")
+		}
+		return nil
+	}
+
+	migrator, conn, err := newMigrator(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, conn.Disconnect())
+	}()
+
+	switch cmd {
+	case "up":
+		done, err := migrator.Up()
+		printMigrations(out, "applied", cfg.dryRun, done)
+		return err
+	case "down":
+		n := 1
+		if len(cmdArgs) > 0 {
+			n, err = strconv.Atoi(cmdArgs[0])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid number of migrations: %s", cmdArgs[0])
+			}
+		}
+
+		done, err := migrator.Down(n)
+		printMigrations(out, "reverted", cfg.dryRun, done)
+		return err
+	case "status":
+		list, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+
+		printStatus(out, list)
+		return nil
+	case "force":
+		if len(cmdArgs) != 1 {
+			return errors.New("force requires a version")
+		}
+
+		version, err := strconv.ParseInt(cmdArgs[0], 10, 64)
+		if err != nil || version < 0 {
+			return fmt.Errorf("invalid version: %s", cmdArgs[0])
+		}
+
+		if err := migrator.Force(version); err != nil {
+			return err
+		}
+
+		if cfg.dryRun {
+			fmt.Fprintf(out, "would force version %d\n", version)
+		} else {
+			fmt.Fprintf(out, "forced version %d\n", version)
+		}
+		return nil
+	}
+
+	fs.Usage()
+	return fmt.Errorf("unknown command: %s", cmd)
+}
+
+// newMigrator connects to the database and registers the JSON migrations of the configured directory.
+// The returned connector has to be disconnected by the caller.
+func newMigrator(cfg config) (*migrate.Migrator, *mongodb.StdConnector, error) {
+	if len(cfg.uri) == 0 || len(cfg.database) == 0 {
+		return nil, nil, errors.New("uri and database are required")
+	}
+
+	migrations, err := migrate.LoadJsonMigrations(cfg.dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conn, err := mongodb.NewConnector(mongodb.NewParams{
+		Uri:      cfg.uri,
+		Database: cfg.database,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	migrator := migrate.NewMigrator(conn, migrate.NewParams{
+		Collection: cfg.collection,
+		DryRun:     cfg.dryRun,
+	})
+
+	if err := migrator.Register(migrations...); err != nil {
+		return nil, nil, errors.Join(err, conn.Disconnect())
+	}
+
+	return migrator, conn, nil
+}
+
+func printMigrations(out io.Writer, verb string, dryRun bool, migrations []migrate.Migration) {
+	if dryRun {
+		verb = "would be " + verb
+	}
+
+	if len(migrations) == 0 {
+		fmt.Fprintln(out, "no migrations "+verb)
+		return
+	}
+
+	for _, mig := range migrations {
+		fmt.Fprintf(out, "%s %d %s\n", verb, mig.Version, mig.Description)
+	}
+}
+
+func printStatus(out io.Writer, list []migrate.Status) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tSTATUS\tAPPLIED AT\tDESCRIPTION")
+
+	for _, s := range list {
+		state, appliedAt := "pending", ""
+		if s.Applied {
+			state, appliedAt = "applied", s.AppliedAt.Format(time.RFC3339)
+		}
+		if s.Missing {
+			state = "missing"
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", s.Version, state, appliedAt, s.Description)
+	}
+
+	_ = w.Flush()
+}
+
+func envDefault(name string, def string) string {
+	if v, ok := os.LookupEnv(name); ok && len(v) > 0 {
+		return v
+	}
+
+	return def
+}
@@ -16,6 +16,7 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
 )
 
 // StdConnector handles connections and interactions with the MongoDB client, database, and collections.
@@ -24,6 +25,7 @@ type StdConnector struct {
 	database   *mongo.Database
 	collection *mongo.Collection
 	context    context.Context
+	session    *mongo.Session
 }
 
 // Connector provides methods for database and collection operations.
@@ -33,6 +35,11 @@ type Connector interface {
 	NewGridfsBucket() (*mongo.GridFSBucket, error)
 	WithContext(context.Context) Connector
 	WithCollection(coll string, opts ...options.Lister[options.CollectionOptions]) Connector
+	WithReadPreference(rp *readpref.ReadPref) Connector
+	WithCausalConsistency(fn func(conn Connector) error) error
+	CreateCollection(name string, opts ...options.Lister[options.CreateCollectionOptions]) error
+	CreateView(name string, source string, pipeline interface{}, opts ...options.Lister[options.CreateViewOptions]) error
+	ListCollections(filter interface{}, opts ...options.Lister[options.ListCollectionsOptions]) (res []mongo.CollectionSpecification, err error)
 	Find(filter interface{}, opts ...options.Lister[options.FindOptions]) (*mongo.Cursor, error)
 	FindOne(filter interface{}, opts ...options.Lister[options.FindOneOptions]) *mongo.SingleResult
 	FetchAll(cur *mongo.Cursor, results interface{}) error
@@ -57,12 +64,30 @@ type Connector interface {
 	SearchIndexes() (*mongo.SearchIndexView, error)
 	CreateSearchIndex(model mongo.SearchIndexModel, opts ...options.Lister[options.CreateSearchIndexesOptions]) (string, error)
 	Drop() error
+	SetValidator(validator interface{}, level string, action string) error
+	RunCommand(cmd interface{}, opts ...options.Lister[options.RunCmdOptions]) *mongo.SingleResult
+	WithTransaction(fn func(conn Connector) error, opts ...options.Lister[options.TransactionOptions]) error
 	Watch(pipeline interface{}, opts ...options.Lister[options.ChangeStreamOptions]) (stream *mongo.ChangeStream, err error)
-	GetNextSeq(name string, opts ...string) (res int64, err error)
+	WatchDatabase(pipeline interface{}, opts ...options.Lister[options.ChangeStreamOptions]) (stream *mongo.ChangeStream, err error)
+	WatchDeployment(pipeline interface{}, opts ...options.Lister[options.ChangeStreamOptions]) (stream *mongo.ChangeStream, err error)
+	GetNextSeq(name string, opts ...*SeqOptions) (res int64, err error)
+	GetNextSeqRange(name string, n int64, opts ...*SeqOptions) (first int64, err error)
+	CurrentSeq(name string, opts ...*SeqOptions) (seq int64, err error)
+	SetSeq(name string, value int64, opts ...*SeqOptions) error
+	ResetSeq(name string, opts ...*SeqOptions) error
 }
 
 var ErrNoCollectionSet = errors.New("no collection set")
 
+// validation levels and actions used by SetValidator
+const (
+	ValidationLevelOff      = "off"
+	ValidationLevelStrict   = "strict"
+	ValidationLevelModerate = "moderate"
+	ValidationActionError   = "error"
+	ValidationActionWarn    = "warn"
+)
+
 // NewParams holds the parameters required to establish a new connection to a database.
 type NewParams struct {
 	Uri      string
@@ -95,6 +120,17 @@ func NewConnector(params NewParams) (*StdConnector, error) {
 	return &conn, nil
 }
 
+// Disconnect closes the connections of the client, the connector and all connectors derived from it
+// must not be used afterwards.
+func (conn *StdConnector) Disconnect() error {
+	return conn.client.Disconnect(conn.context)
+}
+
+// Context returns the context the operations of the StdConnector are executed with.
+func (conn *StdConnector) Context() context.Context {
+	return conn.context
+}
+
 // Database returns the current mongo.Database instance associated with the StdConnector.
 func (conn *StdConnector) Database() *mongo.Database {
 	return conn.database
@@ -111,9 +147,13 @@ func (conn *StdConnector) NewGridfsBucket() (*mongo.GridFSBucket, error) {
 }
 
 // WithContext returns a copy of the StdConnector with the specified context.
+// Inside of WithCausalConsistency, the causally consistent session is bound to the new context.
 func (conn *StdConnector) WithContext(ctx context.Context) Connector {
 	newConn := *conn
 	newConn.context = ctx
+	if conn.session != nil && mongo.SessionFromContext(ctx) == nil {
+		newConn.context = mongo.NewSessionContext(ctx, conn.session)
+	}
 	return &newConn
 }
 
@@ -124,6 +164,63 @@ func (conn *StdConnector) WithCollection(coll string, opts ...options.Lister[opt
 	return &newConn
 }
 
+// WithReadPreference returns a copy of StdConnector reading with the given read preference, e.g. readpref.SecondaryPreferred(),
+// the client is shared. Collections set afterwards by WithCollection inherit the read preference.
+func (conn *StdConnector) WithReadPreference(rp *readpref.ReadPref) Connector {
+	newConn := *conn
+	newConn.database = conn.client.Database(conn.database.Name(), options.Database().SetReadPreference(rp))
+	if conn.collection != nil {
+		newConn.collection = conn.collection.Clone(options.Collection().SetReadPreference(rp))
+	}
+	return &newConn
+}
+
+// WithCausalConsistency executes fn with a connector bound to a causally consistent session, the session is carried
+// across the copies made by WithCollection, WithContext and WithReadPreference. Reads done through the connector observe
+// the preceding writes, even if they are served by secondaries. For guarantees during failovers, majority read and
+// write concerns are required. The connector passed to fn must not be used concurrently.
+func (conn *StdConnector) WithCausalConsistency(fn func(conn Connector) error) error {
+	if conn.session != nil {
+		return fn(conn)
+	}
+
+	sess, err := conn.client.StartSession(options.Session().SetCausalConsistency(true))
+	if err != nil {
+		return err
+	}
+	defer sess.EndSession(context.WithoutCancel(conn.context))
+
+	newConn := *conn
+	newConn.session = sess
+	newConn.context = mongo.NewSessionContext(conn.context, sess)
+
+	return fn(&newConn)
+}
+
+// collections
+
+// CreateCollection explicitly creates a collection in the database, using the provided options.
+// Options are e.g. capped collections, time series, clustered indexes, collations or validators.
+// The collection is not set on the connector, use WithCollection for this.
+func (conn *StdConnector) CreateCollection(name string, opts ...options.Lister[options.CreateCollectionOptions]) error {
+	return conn.database.CreateCollection(conn.context, name, opts...)
+}
+
+// CreateView creates a read-only view named name, applying the aggregation pipeline to the source collection.
+func (conn *StdConnector) CreateView(name string, source string, pipeline interface{}, opts ...options.Lister[options.CreateViewOptions]) error {
+	return conn.database.CreateView(conn.context, name, source, pipeline, opts...)
+}
+
+// ListCollections returns the specifications of the collections and views in the database, matching the given filter.
+// The filter is applied to the listCollections output, e.g. bson.D{{"type", "view"}} or bson.D{{"name", "Users"}}.
+func (conn *StdConnector) ListCollections(filter interface{}, opts ...options.Lister[options.ListCollectionsOptions]) (res []mongo.CollectionSpecification, err error) {
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	return conn.database.ListCollectionSpecifications(conn.context, filter, opts...)
+}
+
 // read
 
 // Find executes a find query in the collection with the given filter and options.
@@ -367,60 +464,85 @@ func (conn *StdConnector) Drop() (err error) {
 	return conn.collection.Drop(conn.context)
 }
 
-// Watch starts a change stream against the collection of the StdConnector, based on the given pipeline and options.
-// It returns a pointer to a mongo.ChangeStream for iterating the changes, or an error if the collection is not set.
-func (conn *StdConnector) Watch(pipeline interface{}, opts ...options.Lister[options.ChangeStreamOptions]) (stream *mongo.ChangeStream, err error) {
+// SetValidator applies the validator to the current collection by using collMod, if the collection does not exist,
+// it will be created with the validator. An empty level or action keeps the servers default, which is strict/error.
+// The validator can be built from a struct using utils.JsonSchema: bson.M{"$jsonSchema": schema}.
+func (conn *StdConnector) SetValidator(validator interface{}, level string, action string) error {
 	if conn.collection == nil {
-		return nil, ErrNoCollectionSet
+		return ErrNoCollectionSet
 	}
 
-	return conn.collection.Watch(conn.context, pipeline, opts...)
+	return setValidator(conn, conn.collection.Name(), validator, level, action)
 }
 
-// GetNextSeq increments and retrieves the next sequence number for a given name within the specified collection.
-func (conn *StdConnector) GetNextSeq(name string, opts ...string) (seq int64, err error) {
-	if len(name) == 0 {
-		if conn.collection == nil {
-			return 0, ErrNoCollectionSet
-		}
-
-		name = conn.collection.Name()
+// setValidator runs collMod on the collection through conn and falls back to creating the collection,
+// if it does not exist.
+func setValidator(conn Connector, collection string, validator interface{}, level string, action string) error {
+	cmd := bson.D{{"collMod", collection}, {"validator", validator}}
+	if len(level) > 0 {
+		cmd = append(cmd, bson.E{"validationLevel", level})
 	}
+	if len(action) > 0 {
+		cmd = append(cmd, bson.E{"validationAction", action})
+	}
+
+	err := conn.RunCommand(cmd).Err()
 
-	seqCollection := "Sequences"
-	if len(opts) > 0 && len(opts[0]) > 0 {
-		seqCollection = opts[0]
+	var cmdErr mongo.CommandError
+	if !errors.As(err, &cmdErr) || cmdErr.Code != 26 { // NamespaceNotFound
+		return err
 	}
 
-	fieldName := "Current"
-	if len(opts) > 1 && len(opts[1]) > 0 {
-		fieldName = opts[1]
+	opts := options.CreateCollection().SetValidator(validator)
+	if len(level) > 0 {
+		opts.SetValidationLevel(level)
+	}
+	if len(action) > 0 {
+		opts.SetValidationAction(action)
 	}
 
-	res := conn.WithCollection(seqCollection).FindOneAndUpdate(
-		bson.D{{"_id", name}},
-		bson.D{{"$inc", bson.D{{fieldName, 1}}}},
-		options.FindOneAndUpdate().SetUpsert(true),
-		options.FindOneAndUpdate().SetReturnDocument(options.After),
-		options.FindOneAndUpdate().SetProjection(bson.D{{fieldName, 1}}))
+	return conn.CreateCollection(collection, opts)
+}
 
-	if res == nil {
-		return 0, nil
-	}
+// RunCommand executes the given command against the database.
+// The command must be ordered, e.g. a bson.D, the first element has to be the command name.
+func (conn *StdConnector) RunCommand(cmd interface{}, opts ...options.Lister[options.RunCmdOptions]) *mongo.SingleResult {
+	return conn.database.RunCommand(conn.context, cmd, opts...)
+}
 
-	var data bson.M
-	if err := res.Decode(&data); err != nil {
-		return 0, err
+// WithTransaction executes fn inside a transaction, the connector passed to fn is bound to the session of the transaction,
+// all operations done through it are committed or aborted together. The transaction is committed if fn returns nil,
+// otherwise it is aborted. Transient transaction errors are retried by the driver, so fn may be called multiple times.
+func (conn *StdConnector) WithTransaction(fn func(conn Connector) error, opts ...options.Lister[options.TransactionOptions]) error {
+	sess, err := conn.client.StartSession()
+	if err != nil {
+		return err
 	}
+	defer sess.EndSession(context.WithoutCancel(conn.context))
 
-	switch v := data[fieldName].(type) {
-	case int32:
-		return int64(int(v)), nil
-	case int64:
-		return v, nil
-	case float64:
-		return int64(int(v)), nil
-	default:
-		return 0, errors.New("unknown return type")
+	_, err = sess.WithTransaction(conn.context, func(ctx context.Context) (interface{}, error) {
+		return nil, fn(conn.WithContext(ctx))
+	}, opts...)
+
+	return err
+}
+
+// Watch starts a change stream against the collection of the StdConnector, based on the given pipeline and options.
+// It returns a pointer to a mongo.ChangeStream for iterating the changes, or an error if the collection is not set.
+func (conn *StdConnector) Watch(pipeline interface{}, opts ...options.Lister[options.ChangeStreamOptions]) (stream *mongo.ChangeStream, err error) {
+	if conn.collection == nil {
+		return nil, ErrNoCollectionSet
 	}
+
+	return conn.collection.Watch(conn.context, pipeline, opts...)
+}
+
+// WatchDatabase starts a change stream against all collections of the database.
+func (conn *StdConnector) WatchDatabase(pipeline interface{}, opts ...options.Lister[options.ChangeStreamOptions]) (stream *mongo.ChangeStream, err error) {
+	return conn.database.Watch(conn.context, pipeline, opts...)
+}
+
+// WatchDeployment starts a change stream against all databases of the deployment, except the system databases.
+func (conn *StdConnector) WatchDeployment(pipeline interface{}, opts ...options.Lister[options.ChangeStreamOptions]) (stream *mongo.ChangeStream, err error) {
+	return conn.client.Watch(conn.context, pipeline, opts...)
 }
@@ -11,6 +11,7 @@ import (
 	mock "github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
 )
 
 // NewConnectorMock creates a new instance of ConnectorMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -272,6 +273,76 @@ func (_c *ConnectorMock_Count_Call) RunAndReturn(run func(filter interface{}, op
 	return _c
 }
 
+// CreateCollection provides a mock function for the type ConnectorMock
+func (_mock *ConnectorMock) CreateCollection(name string, opts ...options.Lister[options.CreateCollectionOptions]) error {
+	// options.Lister[options.CreateCollectionOptions]
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, name)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateCollection")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, ...options.Lister[options.CreateCollectionOptions]) error); ok {
+		r0 = returnFunc(name, opts...)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// ConnectorMock_CreateCollection_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateCollection'
+type ConnectorMock_CreateCollection_Call struct {
+	*mock.Call
+}
+
+// CreateCollection is a helper method to define mock.On call
+//   - name string
+//   - opts ...options.Lister[options.CreateCollectionOptions]
+func (_e *ConnectorMock_Expecter) CreateCollection(name interface{}, opts ...interface{}) *ConnectorMock_CreateCollection_Call {
+	return &ConnectorMock_CreateCollection_Call{Call: _e.mock.On("CreateCollection",
+		append([]interface{}{name}, opts...)...)}
+}
+
+func (_c *ConnectorMock_CreateCollection_Call) Run(run func(name string, opts ...options.Lister[options.CreateCollectionOptions])) *ConnectorMock_CreateCollection_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 []options.Lister[options.CreateCollectionOptions]
+		variadicArgs := make([]options.Lister[options.CreateCollectionOptions], len(args)-1)
+		for i, a := range args[1:] {
+			if a != nil {
+				variadicArgs[i] = a.(options.Lister[options.CreateCollectionOptions])
+			}
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *ConnectorMock_CreateCollection_Call) Return(err error) *ConnectorMock_CreateCollection_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *ConnectorMock_CreateCollection_Call) RunAndReturn(run func(name string, opts ...options.Lister[options.CreateCollectionOptions]) error) *ConnectorMock_CreateCollection_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateIndex provides a mock function for the type ConnectorMock
 func (_mock *ConnectorMock) CreateIndex(model mongo.IndexModel, opts ...options.Lister[options.CreateIndexesOptions]) (string, error) {
 	// options.Lister[options.CreateIndexesOptions]
@@ -430,6 +501,167 @@ func (_c *ConnectorMock_CreateSearchIndex_Call) RunAndReturn(run func(model mong
 	return _c
 }
 
+// CreateView provides a mock function for the type ConnectorMock
+func (_mock *ConnectorMock) CreateView(name string, source string, pipeline interface{}, opts ...options.Lister[options.CreateViewOptions]) error {
+	// options.Lister[options.CreateViewOptions]
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, name, source, pipeline)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateView")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, string, interface{}, ...options.Lister[options.CreateViewOptions]) error); ok {
+		r0 = returnFunc(name, source, pipeline, opts...)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// ConnectorMock_CreateView_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateView'
+type ConnectorMock_CreateView_Call struct {
+	*mock.Call
+}
+
+// CreateView is a helper method to define mock.On call
+//   - name string
+//   - source string
+//   - pipeline interface{}
+//   - opts ...options.Lister[options.CreateViewOptions]
+func (_e *ConnectorMock_Expecter) CreateView(name interface{}, source interface{}, pipeline interface{}, opts ...interface{}) *ConnectorMock_CreateView_Call {
+	return &ConnectorMock_CreateView_Call{Call: _e.mock.On("CreateView",
+		append([]interface{}{name, source, pipeline}, opts...)...)}
+}
+
+func (_c *ConnectorMock_CreateView_Call) Run(run func(name string, source string, pipeline interface{}, opts ...options.Lister[options.CreateViewOptions])) *ConnectorMock_CreateView_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 interface{}
+		if args[2] != nil {
+			arg2 = args[2].(interface{})
+		}
+		var arg3 []options.Lister[options.CreateViewOptions]
+		variadicArgs := make([]options.Lister[options.CreateViewOptions], len(args)-3)
+		for i, a := range args[3:] {
+			if a != nil {
+				variadicArgs[i] = a.(options.Lister[options.CreateViewOptions])
+			}
+		}
+		arg3 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3...,
+		)
+	})
+	return _c
+}
+
+func (_c *ConnectorMock_CreateView_Call) Return(err error) *ConnectorMock_CreateView_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *ConnectorMock_CreateView_Call) RunAndReturn(run func(name string, source string, pipeline interface{}, opts ...options.Lister[options.CreateViewOptions]) error) *ConnectorMock_CreateView_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CurrentSeq provides a mock function for the type ConnectorMock
+func (_mock *ConnectorMock) CurrentSeq(name string, opts ...*mongodb.SeqOptions) (int64, error) {
+	// *mongodb.SeqOptions
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, name)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CurrentSeq")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, ...*mongodb.SeqOptions) (int64, error)); ok {
+		return returnFunc(name, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, ...*mongodb.SeqOptions) int64); ok {
+		r0 = returnFunc(name, opts...)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, ...*mongodb.SeqOptions) error); ok {
+		r1 = returnFunc(name, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// ConnectorMock_CurrentSeq_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CurrentSeq'
+type ConnectorMock_CurrentSeq_Call struct {
+	*mock.Call
+}
+
+// CurrentSeq is a helper method to define mock.On call
+//   - name string
+//   - opts ...*mongodb.SeqOptions
+func (_e *ConnectorMock_Expecter) CurrentSeq(name interface{}, opts ...interface{}) *ConnectorMock_CurrentSeq_Call {
+	return &ConnectorMock_CurrentSeq_Call{Call: _e.mock.On("CurrentSeq",
+		append([]interface{}{name}, opts...)...)}
+}
+
+func (_c *ConnectorMock_CurrentSeq_Call) Run(run func(name string, opts ...*mongodb.SeqOptions)) *ConnectorMock_CurrentSeq_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 []*mongodb.SeqOptions
+		variadicArgs := make([]*mongodb.SeqOptions, len(args)-1)
+		for i, a := range args[1:] {
+			if a != nil {
+				variadicArgs[i] = a.(*mongodb.SeqOptions)
+			}
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *ConnectorMock_CurrentSeq_Call) Return(seq int64, err error) *ConnectorMock_CurrentSeq_Call {
+	_c.Call.Return(seq, err)
+	return _c
+}
+
+func (_c *ConnectorMock_CurrentSeq_Call) RunAndReturn(run func(name string, opts ...*mongodb.SeqOptions) (int64, error)) *ConnectorMock_CurrentSeq_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Database provides a mock function for the type ConnectorMock
 func (_mock *ConnectorMock) Database() *mongo.Database {
 	ret := _mock.Called()
@@ -1265,8 +1497,8 @@ func (_c *ConnectorMock_FindOneAndUpdate_Call) RunAndReturn(run func(filter inte
 }
 
 // GetNextSeq provides a mock function for the type ConnectorMock
-func (_mock *ConnectorMock) GetNextSeq(name string, opts ...string) (int64, error) {
-	// string
+func (_mock *ConnectorMock) GetNextSeq(name string, opts ...*mongodb.SeqOptions) (int64, error) {
+	// *mongodb.SeqOptions
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
@@ -1282,15 +1514,15 @@ func (_mock *ConnectorMock) GetNextSeq(name string, opts ...string) (int64, erro
 
 	var r0 int64
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(string, ...string) (int64, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(string, ...*mongodb.SeqOptions) (int64, error)); ok {
 		return returnFunc(name, opts...)
 	}
-	if returnFunc, ok := ret.Get(0).(func(string, ...string) int64); ok {
+	if returnFunc, ok := ret.Get(0).(func(string, ...*mongodb.SeqOptions) int64); ok {
 		r0 = returnFunc(name, opts...)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
-	if returnFunc, ok := ret.Get(1).(func(string, ...string) error); ok {
+	if returnFunc, ok := ret.Get(1).(func(string, ...*mongodb.SeqOptions) error); ok {
 		r1 = returnFunc(name, opts...)
 	} else {
 		r1 = ret.Error(1)
@@ -1305,23 +1537,23 @@ type ConnectorMock_GetNextSeq_Call struct {
 
 // GetNextSeq is a helper method to define mock.On call
 //   - name string
-//   - opts ...string
+//   - opts ...*mongodb.SeqOptions
 func (_e *ConnectorMock_Expecter) GetNextSeq(name interface{}, opts ...interface{}) *ConnectorMock_GetNextSeq_Call {
 	return &ConnectorMock_GetNextSeq_Call{Call: _e.mock.On("GetNextSeq",
 		append([]interface{}{name}, opts...)...)}
 }
 
-func (_c *ConnectorMock_GetNextSeq_Call) Run(run func(name string, opts ...string)) *ConnectorMock_GetNextSeq_Call {
+func (_c *ConnectorMock_GetNextSeq_Call) Run(run func(name string, opts ...*mongodb.SeqOptions)) *ConnectorMock_GetNextSeq_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 string
 		if args[0] != nil {
 			arg0 = args[0].(string)
 		}
-		var arg1 []string
-		variadicArgs := make([]string, len(args)-1)
+		var arg1 []*mongodb.SeqOptions
+		variadicArgs := make([]*mongodb.SeqOptions, len(args)-1)
 		for i, a := range args[1:] {
 			if a != nil {
-				variadicArgs[i] = a.(string)
+				variadicArgs[i] = a.(*mongodb.SeqOptions)
 			}
 		}
 		arg1 = variadicArgs
@@ -1338,7 +1570,92 @@ func (_c *ConnectorMock_GetNextSeq_Call) Return(res int64, err error) *Connector
 	return _c
 }
 
-func (_c *ConnectorMock_GetNextSeq_Call) RunAndReturn(run func(name string, opts ...string) (int64, error)) *ConnectorMock_GetNextSeq_Call {
+func (_c *ConnectorMock_GetNextSeq_Call) RunAndReturn(run func(name string, opts ...*mongodb.SeqOptions) (int64, error)) *ConnectorMock_GetNextSeq_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetNextSeqRange provides a mock function for the type ConnectorMock
+func (_mock *ConnectorMock) GetNextSeqRange(name string, n int64, opts ...*mongodb.SeqOptions) (int64, error) {
+	// *mongodb.SeqOptions
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, name, n)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNextSeqRange")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, int64, ...*mongodb.SeqOptions) (int64, error)); ok {
+		return returnFunc(name, n, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, int64, ...*mongodb.SeqOptions) int64); ok {
+		r0 = returnFunc(name, n, opts...)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, int64, ...*mongodb.SeqOptions) error); ok {
+		r1 = returnFunc(name, n, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// ConnectorMock_GetNextSeqRange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetNextSeqRange'
+type ConnectorMock_GetNextSeqRange_Call struct {
+	*mock.Call
+}
+
+// GetNextSeqRange is a helper method to define mock.On call
+//   - name string
+//   - n int64
+//   - opts ...*mongodb.SeqOptions
+func (_e *ConnectorMock_Expecter) GetNextSeqRange(name interface{}, n interface{}, opts ...interface{}) *ConnectorMock_GetNextSeqRange_Call {
+	return &ConnectorMock_GetNextSeqRange_Call{Call: _e.mock.On("GetNextSeqRange",
+		append([]interface{}{name, n}, opts...)...)}
+}
+
+func (_c *ConnectorMock_GetNextSeqRange_Call) Run(run func(name string, n int64, opts ...*mongodb.SeqOptions)) *ConnectorMock_GetNextSeqRange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		var arg2 []*mongodb.SeqOptions
+		variadicArgs := make([]*mongodb.SeqOptions, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(*mongodb.SeqOptions)
+			}
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *ConnectorMock_GetNextSeqRange_Call) Return(first int64, err error) *ConnectorMock_GetNextSeqRange_Call {
+	_c.Call.Return(first, err)
+	return _c
+}
+
+func (_c *ConnectorMock_GetNextSeqRange_Call) RunAndReturn(run func(name string, n int64, opts ...*mongodb.SeqOptions) (int64, error)) *ConnectorMock_GetNextSeqRange_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -1560,50 +1877,131 @@ func (_c *ConnectorMock_InsertOne_Call) RunAndReturn(run func(document interface
 	return _c
 }
 
-// NewGridfsBucket provides a mock function for the type ConnectorMock
-func (_mock *ConnectorMock) NewGridfsBucket() (*mongo.GridFSBucket, error) {
-	ret := _mock.Called()
+// ListCollections provides a mock function for the type ConnectorMock
+func (_mock *ConnectorMock) ListCollections(filter interface{}, opts ...options.Lister[options.ListCollectionsOptions]) ([]mongo.CollectionSpecification, error) {
+	// options.Lister[options.ListCollectionsOptions]
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, filter)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
 
 	if len(ret) == 0 {
-		panic("no return value specified for NewGridfsBucket")
+		panic("no return value specified for ListCollections")
 	}
 
-	var r0 *mongo.GridFSBucket
+	var r0 []mongo.CollectionSpecification
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func() (*mongo.GridFSBucket, error)); ok {
-		return returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(interface{}, ...options.Lister[options.ListCollectionsOptions]) ([]mongo.CollectionSpecification, error)); ok {
+		return returnFunc(filter, opts...)
 	}
-	if returnFunc, ok := ret.Get(0).(func() *mongo.GridFSBucket); ok {
-		r0 = returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(interface{}, ...options.Lister[options.ListCollectionsOptions]) []mongo.CollectionSpecification); ok {
+		r0 = returnFunc(filter, opts...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*mongo.GridFSBucket)
+			r0 = ret.Get(0).([]mongo.CollectionSpecification)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func() error); ok {
-		r1 = returnFunc()
+	if returnFunc, ok := ret.Get(1).(func(interface{}, ...options.Lister[options.ListCollectionsOptions]) error); ok {
+		r1 = returnFunc(filter, opts...)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// ConnectorMock_NewGridfsBucket_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NewGridfsBucket'
-type ConnectorMock_NewGridfsBucket_Call struct {
+// ConnectorMock_ListCollections_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListCollections'
+type ConnectorMock_ListCollections_Call struct {
 	*mock.Call
 }
 
-// NewGridfsBucket is a helper method to define mock.On call
-func (_e *ConnectorMock_Expecter) NewGridfsBucket() *ConnectorMock_NewGridfsBucket_Call {
-	return &ConnectorMock_NewGridfsBucket_Call{Call: _e.mock.On("NewGridfsBucket")}
+// ListCollections is a helper method to define mock.On call
+//   - filter interface{}
+//   - opts ...options.Lister[options.ListCollectionsOptions]
+func (_e *ConnectorMock_Expecter) ListCollections(filter interface{}, opts ...interface{}) *ConnectorMock_ListCollections_Call {
+	return &ConnectorMock_ListCollections_Call{Call: _e.mock.On("ListCollections",
+		append([]interface{}{filter}, opts...)...)}
 }
 
-func (_c *ConnectorMock_NewGridfsBucket_Call) Run(run func()) *ConnectorMock_NewGridfsBucket_Call {
+func (_c *ConnectorMock_ListCollections_Call) Run(run func(filter interface{}, opts ...options.Lister[options.ListCollectionsOptions])) *ConnectorMock_ListCollections_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
-	})
-	return _c
-}
+		var arg0 interface{}
+		if args[0] != nil {
+			arg0 = args[0].(interface{})
+		}
+		var arg1 []options.Lister[options.ListCollectionsOptions]
+		variadicArgs := make([]options.Lister[options.ListCollectionsOptions], len(args)-1)
+		for i, a := range args[1:] {
+			if a != nil {
+				variadicArgs[i] = a.(options.Lister[options.ListCollectionsOptions])
+			}
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *ConnectorMock_ListCollections_Call) Return(res []mongo.CollectionSpecification, err error) *ConnectorMock_ListCollections_Call {
+	_c.Call.Return(res, err)
+	return _c
+}
+
+func (_c *ConnectorMock_ListCollections_Call) RunAndReturn(run func(filter interface{}, opts ...options.Lister[options.ListCollectionsOptions]) ([]mongo.CollectionSpecification, error)) *ConnectorMock_ListCollections_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewGridfsBucket provides a mock function for the type ConnectorMock
+func (_mock *ConnectorMock) NewGridfsBucket() (*mongo.GridFSBucket, error) {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for NewGridfsBucket")
+	}
+
+	var r0 *mongo.GridFSBucket
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func() (*mongo.GridFSBucket, error)); ok {
+		return returnFunc()
+	}
+	if returnFunc, ok := ret.Get(0).(func() *mongo.GridFSBucket); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*mongo.GridFSBucket)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func() error); ok {
+		r1 = returnFunc()
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// ConnectorMock_NewGridfsBucket_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NewGridfsBucket'
+type ConnectorMock_NewGridfsBucket_Call struct {
+	*mock.Call
+}
+
+// NewGridfsBucket is a helper method to define mock.On call
+func (_e *ConnectorMock_Expecter) NewGridfsBucket() *ConnectorMock_NewGridfsBucket_Call {
+	return &ConnectorMock_NewGridfsBucket_Call{Call: _e.mock.On("NewGridfsBucket")}
+}
+
+func (_c *ConnectorMock_NewGridfsBucket_Call) Run(run func()) *ConnectorMock_NewGridfsBucket_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
 
 func (_c *ConnectorMock_NewGridfsBucket_Call) Return(gridFSBucket *mongo.GridFSBucket, err error) *ConnectorMock_NewGridfsBucket_Call {
 	_c.Call.Return(gridFSBucket, err)
@@ -1753,6 +2151,148 @@ func (_c *ConnectorMock_ReplaceOne_Call) RunAndReturn(run func(filter interface{
 	return _c
 }
 
+// ResetSeq provides a mock function for the type ConnectorMock
+func (_mock *ConnectorMock) ResetSeq(name string, opts ...*mongodb.SeqOptions) error {
+	// *mongodb.SeqOptions
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, name)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetSeq")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, ...*mongodb.SeqOptions) error); ok {
+		r0 = returnFunc(name, opts...)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// ConnectorMock_ResetSeq_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetSeq'
+type ConnectorMock_ResetSeq_Call struct {
+	*mock.Call
+}
+
+// ResetSeq is a helper method to define mock.On call
+//   - name string
+//   - opts ...*mongodb.SeqOptions
+func (_e *ConnectorMock_Expecter) ResetSeq(name interface{}, opts ...interface{}) *ConnectorMock_ResetSeq_Call {
+	return &ConnectorMock_ResetSeq_Call{Call: _e.mock.On("ResetSeq",
+		append([]interface{}{name}, opts...)...)}
+}
+
+func (_c *ConnectorMock_ResetSeq_Call) Run(run func(name string, opts ...*mongodb.SeqOptions)) *ConnectorMock_ResetSeq_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 []*mongodb.SeqOptions
+		variadicArgs := make([]*mongodb.SeqOptions, len(args)-1)
+		for i, a := range args[1:] {
+			if a != nil {
+				variadicArgs[i] = a.(*mongodb.SeqOptions)
+			}
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *ConnectorMock_ResetSeq_Call) Return(err error) *ConnectorMock_ResetSeq_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *ConnectorMock_ResetSeq_Call) RunAndReturn(run func(name string, opts ...*mongodb.SeqOptions) error) *ConnectorMock_ResetSeq_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RunCommand provides a mock function for the type ConnectorMock
+func (_mock *ConnectorMock) RunCommand(cmd interface{}, opts ...options.Lister[options.RunCmdOptions]) *mongo.SingleResult {
+	// options.Lister[options.RunCmdOptions]
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, cmd)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunCommand")
+	}
+
+	var r0 *mongo.SingleResult
+	if returnFunc, ok := ret.Get(0).(func(interface{}, ...options.Lister[options.RunCmdOptions]) *mongo.SingleResult); ok {
+		r0 = returnFunc(cmd, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*mongo.SingleResult)
+		}
+	}
+	return r0
+}
+
+// ConnectorMock_RunCommand_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunCommand'
+type ConnectorMock_RunCommand_Call struct {
+	*mock.Call
+}
+
+// RunCommand is a helper method to define mock.On call
+//   - cmd interface{}
+//   - opts ...options.Lister[options.RunCmdOptions]
+func (_e *ConnectorMock_Expecter) RunCommand(cmd interface{}, opts ...interface{}) *ConnectorMock_RunCommand_Call {
+	return &ConnectorMock_RunCommand_Call{Call: _e.mock.On("RunCommand",
+		append([]interface{}{cmd}, opts...)...)}
+}
+
+func (_c *ConnectorMock_RunCommand_Call) Run(run func(cmd interface{}, opts ...options.Lister[options.RunCmdOptions])) *ConnectorMock_RunCommand_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 interface{}
+		if args[0] != nil {
+			arg0 = args[0].(interface{})
+		}
+		var arg1 []options.Lister[options.RunCmdOptions]
+		variadicArgs := make([]options.Lister[options.RunCmdOptions], len(args)-1)
+		for i, a := range args[1:] {
+			if a != nil {
+				variadicArgs[i] = a.(options.Lister[options.RunCmdOptions])
+			}
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *ConnectorMock_RunCommand_Call) Return(singleResult *mongo.SingleResult) *ConnectorMock_RunCommand_Call {
+	_c.Call.Return(singleResult)
+	return _c
+}
+
+func (_c *ConnectorMock_RunCommand_Call) RunAndReturn(run func(cmd interface{}, opts ...options.Lister[options.RunCmdOptions]) *mongo.SingleResult) *ConnectorMock_RunCommand_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SearchIndexes provides a mock function for the type ConnectorMock
 func (_mock *ConnectorMock) SearchIndexes() (*mongo.SearchIndexView, error) {
 	ret := _mock.Called()
@@ -1808,6 +2348,145 @@ func (_c *ConnectorMock_SearchIndexes_Call) RunAndReturn(run func() (*mongo.Sear
 	return _c
 }
 
+// SetSeq provides a mock function for the type ConnectorMock
+func (_mock *ConnectorMock) SetSeq(name string, value int64, opts ...*mongodb.SeqOptions) error {
+	// *mongodb.SeqOptions
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, name, value)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetSeq")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, int64, ...*mongodb.SeqOptions) error); ok {
+		r0 = returnFunc(name, value, opts...)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// ConnectorMock_SetSeq_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetSeq'
+type ConnectorMock_SetSeq_Call struct {
+	*mock.Call
+}
+
+// SetSeq is a helper method to define mock.On call
+//   - name string
+//   - value int64
+//   - opts ...*mongodb.SeqOptions
+func (_e *ConnectorMock_Expecter) SetSeq(name interface{}, value interface{}, opts ...interface{}) *ConnectorMock_SetSeq_Call {
+	return &ConnectorMock_SetSeq_Call{Call: _e.mock.On("SetSeq",
+		append([]interface{}{name, value}, opts...)...)}
+}
+
+func (_c *ConnectorMock_SetSeq_Call) Run(run func(name string, value int64, opts ...*mongodb.SeqOptions)) *ConnectorMock_SetSeq_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		var arg2 []*mongodb.SeqOptions
+		variadicArgs := make([]*mongodb.SeqOptions, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(*mongodb.SeqOptions)
+			}
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *ConnectorMock_SetSeq_Call) Return(err error) *ConnectorMock_SetSeq_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *ConnectorMock_SetSeq_Call) RunAndReturn(run func(name string, value int64, opts ...*mongodb.SeqOptions) error) *ConnectorMock_SetSeq_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetValidator provides a mock function for the type ConnectorMock
+func (_mock *ConnectorMock) SetValidator(validator interface{}, level string, action string) error {
+	ret := _mock.Called(validator, level, action)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetValidator")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(interface{}, string, string) error); ok {
+		r0 = returnFunc(validator, level, action)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// ConnectorMock_SetValidator_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetValidator'
+type ConnectorMock_SetValidator_Call struct {
+	*mock.Call
+}
+
+// SetValidator is a helper method to define mock.On call
+//   - validator interface{}
+//   - level string
+//   - action string
+func (_e *ConnectorMock_Expecter) SetValidator(validator interface{}, level interface{}, action interface{}) *ConnectorMock_SetValidator_Call {
+	return &ConnectorMock_SetValidator_Call{Call: _e.mock.On("SetValidator", validator, level, action)}
+}
+
+func (_c *ConnectorMock_SetValidator_Call) Run(run func(validator interface{}, level string, action string)) *ConnectorMock_SetValidator_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 interface{}
+		if args[0] != nil {
+			arg0 = args[0].(interface{})
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *ConnectorMock_SetValidator_Call) Return(err error) *ConnectorMock_SetValidator_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *ConnectorMock_SetValidator_Call) RunAndReturn(run func(validator interface{}, level string, action string) error) *ConnectorMock_SetValidator_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateById provides a mock function for the type ConnectorMock
 func (_mock *ConnectorMock) UpdateById(id interface{}, update interface{}, opts ...options.Lister[options.UpdateOneOptions]) (*mongo.UpdateResult, error) {
 	// options.Lister[options.UpdateOneOptions]
@@ -1890,187 +2569,349 @@ func (_c *ConnectorMock_UpdateById_Call) Return(res *mongo.UpdateResult, err err
 	return _c
 }
 
-func (_c *ConnectorMock_UpdateById_Call) RunAndReturn(run func(id interface{}, update interface{}, opts ...options.Lister[options.UpdateOneOptions]) (*mongo.UpdateResult, error)) *ConnectorMock_UpdateById_Call {
+func (_c *ConnectorMock_UpdateById_Call) RunAndReturn(run func(id interface{}, update interface{}, opts ...options.Lister[options.UpdateOneOptions]) (*mongo.UpdateResult, error)) *ConnectorMock_UpdateById_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateMany provides a mock function for the type ConnectorMock
+func (_mock *ConnectorMock) UpdateMany(filter interface{}, update interface{}, opts ...options.Lister[options.UpdateManyOptions]) (*mongo.UpdateResult, error) {
+	// options.Lister[options.UpdateManyOptions]
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, filter, update)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateMany")
+	}
+
+	var r0 *mongo.UpdateResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(interface{}, interface{}, ...options.Lister[options.UpdateManyOptions]) (*mongo.UpdateResult, error)); ok {
+		return returnFunc(filter, update, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(interface{}, interface{}, ...options.Lister[options.UpdateManyOptions]) *mongo.UpdateResult); ok {
+		r0 = returnFunc(filter, update, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*mongo.UpdateResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(interface{}, interface{}, ...options.Lister[options.UpdateManyOptions]) error); ok {
+		r1 = returnFunc(filter, update, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// ConnectorMock_UpdateMany_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateMany'
+type ConnectorMock_UpdateMany_Call struct {
+	*mock.Call
+}
+
+// UpdateMany is a helper method to define mock.On call
+//   - filter interface{}
+//   - update interface{}
+//   - opts ...options.Lister[options.UpdateManyOptions]
+func (_e *ConnectorMock_Expecter) UpdateMany(filter interface{}, update interface{}, opts ...interface{}) *ConnectorMock_UpdateMany_Call {
+	return &ConnectorMock_UpdateMany_Call{Call: _e.mock.On("UpdateMany",
+		append([]interface{}{filter, update}, opts...)...)}
+}
+
+func (_c *ConnectorMock_UpdateMany_Call) Run(run func(filter interface{}, update interface{}, opts ...options.Lister[options.UpdateManyOptions])) *ConnectorMock_UpdateMany_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 interface{}
+		if args[0] != nil {
+			arg0 = args[0].(interface{})
+		}
+		var arg1 interface{}
+		if args[1] != nil {
+			arg1 = args[1].(interface{})
+		}
+		var arg2 []options.Lister[options.UpdateManyOptions]
+		variadicArgs := make([]options.Lister[options.UpdateManyOptions], len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(options.Lister[options.UpdateManyOptions])
+			}
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *ConnectorMock_UpdateMany_Call) Return(res *mongo.UpdateResult, err error) *ConnectorMock_UpdateMany_Call {
+	_c.Call.Return(res, err)
+	return _c
+}
+
+func (_c *ConnectorMock_UpdateMany_Call) RunAndReturn(run func(filter interface{}, update interface{}, opts ...options.Lister[options.UpdateManyOptions]) (*mongo.UpdateResult, error)) *ConnectorMock_UpdateMany_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateOne provides a mock function for the type ConnectorMock
+func (_mock *ConnectorMock) UpdateOne(filter interface{}, update interface{}, opts ...options.Lister[options.UpdateOneOptions]) (*mongo.UpdateResult, error) {
+	// options.Lister[options.UpdateOneOptions]
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, filter, update)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateOne")
+	}
+
+	var r0 *mongo.UpdateResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(interface{}, interface{}, ...options.Lister[options.UpdateOneOptions]) (*mongo.UpdateResult, error)); ok {
+		return returnFunc(filter, update, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(interface{}, interface{}, ...options.Lister[options.UpdateOneOptions]) *mongo.UpdateResult); ok {
+		r0 = returnFunc(filter, update, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*mongo.UpdateResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(interface{}, interface{}, ...options.Lister[options.UpdateOneOptions]) error); ok {
+		r1 = returnFunc(filter, update, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// ConnectorMock_UpdateOne_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateOne'
+type ConnectorMock_UpdateOne_Call struct {
+	*mock.Call
+}
+
+// UpdateOne is a helper method to define mock.On call
+//   - filter interface{}
+//   - update interface{}
+//   - opts ...options.Lister[options.UpdateOneOptions]
+func (_e *ConnectorMock_Expecter) UpdateOne(filter interface{}, update interface{}, opts ...interface{}) *ConnectorMock_UpdateOne_Call {
+	return &ConnectorMock_UpdateOne_Call{Call: _e.mock.On("UpdateOne",
+		append([]interface{}{filter, update}, opts...)...)}
+}
+
+func (_c *ConnectorMock_UpdateOne_Call) Run(run func(filter interface{}, update interface{}, opts ...options.Lister[options.UpdateOneOptions])) *ConnectorMock_UpdateOne_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 interface{}
+		if args[0] != nil {
+			arg0 = args[0].(interface{})
+		}
+		var arg1 interface{}
+		if args[1] != nil {
+			arg1 = args[1].(interface{})
+		}
+		var arg2 []options.Lister[options.UpdateOneOptions]
+		variadicArgs := make([]options.Lister[options.UpdateOneOptions], len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(options.Lister[options.UpdateOneOptions])
+			}
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *ConnectorMock_UpdateOne_Call) Return(res *mongo.UpdateResult, err error) *ConnectorMock_UpdateOne_Call {
+	_c.Call.Return(res, err)
+	return _c
+}
+
+func (_c *ConnectorMock_UpdateOne_Call) RunAndReturn(run func(filter interface{}, update interface{}, opts ...options.Lister[options.UpdateOneOptions]) (*mongo.UpdateResult, error)) *ConnectorMock_UpdateOne_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateMany provides a mock function for the type ConnectorMock
-func (_mock *ConnectorMock) UpdateMany(filter interface{}, update interface{}, opts ...options.Lister[options.UpdateManyOptions]) (*mongo.UpdateResult, error) {
-	// options.Lister[options.UpdateManyOptions]
+// Watch provides a mock function for the type ConnectorMock
+func (_mock *ConnectorMock) Watch(pipeline interface{}, opts ...options.Lister[options.ChangeStreamOptions]) (*mongo.ChangeStream, error) {
+	// options.Lister[options.ChangeStreamOptions]
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
 	}
 	var _ca []interface{}
-	_ca = append(_ca, filter, update)
+	_ca = append(_ca, pipeline)
 	_ca = append(_ca, _va...)
 	ret := _mock.Called(_ca...)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateMany")
+		panic("no return value specified for Watch")
 	}
 
-	var r0 *mongo.UpdateResult
+	var r0 *mongo.ChangeStream
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(interface{}, interface{}, ...options.Lister[options.UpdateManyOptions]) (*mongo.UpdateResult, error)); ok {
-		return returnFunc(filter, update, opts...)
+	if returnFunc, ok := ret.Get(0).(func(interface{}, ...options.Lister[options.ChangeStreamOptions]) (*mongo.ChangeStream, error)); ok {
+		return returnFunc(pipeline, opts...)
 	}
-	if returnFunc, ok := ret.Get(0).(func(interface{}, interface{}, ...options.Lister[options.UpdateManyOptions]) *mongo.UpdateResult); ok {
-		r0 = returnFunc(filter, update, opts...)
+	if returnFunc, ok := ret.Get(0).(func(interface{}, ...options.Lister[options.ChangeStreamOptions]) *mongo.ChangeStream); ok {
+		r0 = returnFunc(pipeline, opts...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*mongo.UpdateResult)
+			r0 = ret.Get(0).(*mongo.ChangeStream)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(interface{}, interface{}, ...options.Lister[options.UpdateManyOptions]) error); ok {
-		r1 = returnFunc(filter, update, opts...)
+	if returnFunc, ok := ret.Get(1).(func(interface{}, ...options.Lister[options.ChangeStreamOptions]) error); ok {
+		r1 = returnFunc(pipeline, opts...)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// ConnectorMock_UpdateMany_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateMany'
-type ConnectorMock_UpdateMany_Call struct {
+// ConnectorMock_Watch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Watch'
+type ConnectorMock_Watch_Call struct {
 	*mock.Call
 }
 
-// UpdateMany is a helper method to define mock.On call
-//   - filter interface{}
-//   - update interface{}
-//   - opts ...options.Lister[options.UpdateManyOptions]
-func (_e *ConnectorMock_Expecter) UpdateMany(filter interface{}, update interface{}, opts ...interface{}) *ConnectorMock_UpdateMany_Call {
-	return &ConnectorMock_UpdateMany_Call{Call: _e.mock.On("UpdateMany",
-		append([]interface{}{filter, update}, opts...)...)}
+// Watch is a helper method to define mock.On call
+//   - pipeline interface{}
+//   - opts ...options.Lister[options.ChangeStreamOptions]
+func (_e *ConnectorMock_Expecter) Watch(pipeline interface{}, opts ...interface{}) *ConnectorMock_Watch_Call {
+	return &ConnectorMock_Watch_Call{Call: _e.mock.On("Watch",
+		append([]interface{}{pipeline}, opts...)...)}
 }
 
-func (_c *ConnectorMock_UpdateMany_Call) Run(run func(filter interface{}, update interface{}, opts ...options.Lister[options.UpdateManyOptions])) *ConnectorMock_UpdateMany_Call {
+func (_c *ConnectorMock_Watch_Call) Run(run func(pipeline interface{}, opts ...options.Lister[options.ChangeStreamOptions])) *ConnectorMock_Watch_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 interface{}
 		if args[0] != nil {
 			arg0 = args[0].(interface{})
 		}
-		var arg1 interface{}
-		if args[1] != nil {
-			arg1 = args[1].(interface{})
-		}
-		var arg2 []options.Lister[options.UpdateManyOptions]
-		variadicArgs := make([]options.Lister[options.UpdateManyOptions], len(args)-2)
-		for i, a := range args[2:] {
+		var arg1 []options.Lister[options.ChangeStreamOptions]
+		variadicArgs := make([]options.Lister[options.ChangeStreamOptions], len(args)-1)
+		for i, a := range args[1:] {
 			if a != nil {
-				variadicArgs[i] = a.(options.Lister[options.UpdateManyOptions])
+				variadicArgs[i] = a.(options.Lister[options.ChangeStreamOptions])
 			}
 		}
-		arg2 = variadicArgs
+		arg1 = variadicArgs
 		run(
 			arg0,
-			arg1,
-			arg2...,
+			arg1...,
 		)
 	})
 	return _c
 }
 
-func (_c *ConnectorMock_UpdateMany_Call) Return(res *mongo.UpdateResult, err error) *ConnectorMock_UpdateMany_Call {
-	_c.Call.Return(res, err)
+func (_c *ConnectorMock_Watch_Call) Return(stream *mongo.ChangeStream, err error) *ConnectorMock_Watch_Call {
+	_c.Call.Return(stream, err)
 	return _c
 }
 
-func (_c *ConnectorMock_UpdateMany_Call) RunAndReturn(run func(filter interface{}, update interface{}, opts ...options.Lister[options.UpdateManyOptions]) (*mongo.UpdateResult, error)) *ConnectorMock_UpdateMany_Call {
+func (_c *ConnectorMock_Watch_Call) RunAndReturn(run func(pipeline interface{}, opts ...options.Lister[options.ChangeStreamOptions]) (*mongo.ChangeStream, error)) *ConnectorMock_Watch_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateOne provides a mock function for the type ConnectorMock
-func (_mock *ConnectorMock) UpdateOne(filter interface{}, update interface{}, opts ...options.Lister[options.UpdateOneOptions]) (*mongo.UpdateResult, error) {
-	// options.Lister[options.UpdateOneOptions]
+// WatchDatabase provides a mock function for the type ConnectorMock
+func (_mock *ConnectorMock) WatchDatabase(pipeline interface{}, opts ...options.Lister[options.ChangeStreamOptions]) (*mongo.ChangeStream, error) {
+	// options.Lister[options.ChangeStreamOptions]
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
 	}
 	var _ca []interface{}
-	_ca = append(_ca, filter, update)
+	_ca = append(_ca, pipeline)
 	_ca = append(_ca, _va...)
 	ret := _mock.Called(_ca...)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateOne")
+		panic("no return value specified for WatchDatabase")
 	}
 
-	var r0 *mongo.UpdateResult
+	var r0 *mongo.ChangeStream
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(interface{}, interface{}, ...options.Lister[options.UpdateOneOptions]) (*mongo.UpdateResult, error)); ok {
-		return returnFunc(filter, update, opts...)
+	if returnFunc, ok := ret.Get(0).(func(interface{}, ...options.Lister[options.ChangeStreamOptions]) (*mongo.ChangeStream, error)); ok {
+		return returnFunc(pipeline, opts...)
 	}
-	if returnFunc, ok := ret.Get(0).(func(interface{}, interface{}, ...options.Lister[options.UpdateOneOptions]) *mongo.UpdateResult); ok {
-		r0 = returnFunc(filter, update, opts...)
+	if returnFunc, ok := ret.Get(0).(func(interface{}, ...options.Lister[options.ChangeStreamOptions]) *mongo.ChangeStream); ok {
+		r0 = returnFunc(pipeline, opts...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*mongo.UpdateResult)
+			r0 = ret.Get(0).(*mongo.ChangeStream)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(interface{}, interface{}, ...options.Lister[options.UpdateOneOptions]) error); ok {
-		r1 = returnFunc(filter, update, opts...)
+	if returnFunc, ok := ret.Get(1).(func(interface{}, ...options.Lister[options.ChangeStreamOptions]) error); ok {
+		r1 = returnFunc(pipeline, opts...)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// ConnectorMock_UpdateOne_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateOne'
-type ConnectorMock_UpdateOne_Call struct {
+// ConnectorMock_WatchDatabase_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WatchDatabase'
+type ConnectorMock_WatchDatabase_Call struct {
 	*mock.Call
 }
 
-// UpdateOne is a helper method to define mock.On call
-//   - filter interface{}
-//   - update interface{}
-//   - opts ...options.Lister[options.UpdateOneOptions]
-func (_e *ConnectorMock_Expecter) UpdateOne(filter interface{}, update interface{}, opts ...interface{}) *ConnectorMock_UpdateOne_Call {
-	return &ConnectorMock_UpdateOne_Call{Call: _e.mock.On("UpdateOne",
-		append([]interface{}{filter, update}, opts...)...)}
+// WatchDatabase is a helper method to define mock.On call
+//   - pipeline interface{}
+//   - opts ...options.Lister[options.ChangeStreamOptions]
+func (_e *ConnectorMock_Expecter) WatchDatabase(pipeline interface{}, opts ...interface{}) *ConnectorMock_WatchDatabase_Call {
+	return &ConnectorMock_WatchDatabase_Call{Call: _e.mock.On("WatchDatabase",
+		append([]interface{}{pipeline}, opts...)...)}
 }
 
-func (_c *ConnectorMock_UpdateOne_Call) Run(run func(filter interface{}, update interface{}, opts ...options.Lister[options.UpdateOneOptions])) *ConnectorMock_UpdateOne_Call {
+func (_c *ConnectorMock_WatchDatabase_Call) Run(run func(pipeline interface{}, opts ...options.Lister[options.ChangeStreamOptions])) *ConnectorMock_WatchDatabase_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 interface{}
 		if args[0] != nil {
 			arg0 = args[0].(interface{})
 		}
-		var arg1 interface{}
-		if args[1] != nil {
-			arg1 = args[1].(interface{})
-		}
-		var arg2 []options.Lister[options.UpdateOneOptions]
-		variadicArgs := make([]options.Lister[options.UpdateOneOptions], len(args)-2)
-		for i, a := range args[2:] {
+		var arg1 []options.Lister[options.ChangeStreamOptions]
+		variadicArgs := make([]options.Lister[options.ChangeStreamOptions], len(args)-1)
+		for i, a := range args[1:] {
 			if a != nil {
-				variadicArgs[i] = a.(options.Lister[options.UpdateOneOptions])
+				variadicArgs[i] = a.(options.Lister[options.ChangeStreamOptions])
 			}
 		}
-		arg2 = variadicArgs
+		arg1 = variadicArgs
 		run(
 			arg0,
-			arg1,
-			arg2...,
+			arg1...,
 		)
 	})
 	return _c
 }
 
-func (_c *ConnectorMock_UpdateOne_Call) Return(res *mongo.UpdateResult, err error) *ConnectorMock_UpdateOne_Call {
-	_c.Call.Return(res, err)
+func (_c *ConnectorMock_WatchDatabase_Call) Return(stream *mongo.ChangeStream, err error) *ConnectorMock_WatchDatabase_Call {
+	_c.Call.Return(stream, err)
 	return _c
 }
 
-func (_c *ConnectorMock_UpdateOne_Call) RunAndReturn(run func(filter interface{}, update interface{}, opts ...options.Lister[options.UpdateOneOptions]) (*mongo.UpdateResult, error)) *ConnectorMock_UpdateOne_Call {
+func (_c *ConnectorMock_WatchDatabase_Call) RunAndReturn(run func(pipeline interface{}, opts ...options.Lister[options.ChangeStreamOptions]) (*mongo.ChangeStream, error)) *ConnectorMock_WatchDatabase_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Watch provides a mock function for the type ConnectorMock
-func (_mock *ConnectorMock) Watch(pipeline interface{}, opts ...options.Lister[options.ChangeStreamOptions]) (*mongo.ChangeStream, error) {
+// WatchDeployment provides a mock function for the type ConnectorMock
+func (_mock *ConnectorMock) WatchDeployment(pipeline interface{}, opts ...options.Lister[options.ChangeStreamOptions]) (*mongo.ChangeStream, error) {
 	// options.Lister[options.ChangeStreamOptions]
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
@@ -2082,7 +2923,7 @@ func (_mock *ConnectorMock) Watch(pipeline interface{}, opts ...options.Lister[o
 	ret := _mock.Called(_ca...)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Watch")
+		panic("no return value specified for WatchDeployment")
 	}
 
 	var r0 *mongo.ChangeStream
@@ -2105,20 +2946,20 @@ func (_mock *ConnectorMock) Watch(pipeline interface{}, opts ...options.Lister[o
 	return r0, r1
 }
 
-// ConnectorMock_Watch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Watch'
-type ConnectorMock_Watch_Call struct {
+// ConnectorMock_WatchDeployment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WatchDeployment'
+type ConnectorMock_WatchDeployment_Call struct {
 	*mock.Call
 }
 
-// Watch is a helper method to define mock.On call
+// WatchDeployment is a helper method to define mock.On call
 //   - pipeline interface{}
 //   - opts ...options.Lister[options.ChangeStreamOptions]
-func (_e *ConnectorMock_Expecter) Watch(pipeline interface{}, opts ...interface{}) *ConnectorMock_Watch_Call {
-	return &ConnectorMock_Watch_Call{Call: _e.mock.On("Watch",
+func (_e *ConnectorMock_Expecter) WatchDeployment(pipeline interface{}, opts ...interface{}) *ConnectorMock_WatchDeployment_Call {
+	return &ConnectorMock_WatchDeployment_Call{Call: _e.mock.On("WatchDeployment",
 		append([]interface{}{pipeline}, opts...)...)}
 }
 
-func (_c *ConnectorMock_Watch_Call) Run(run func(pipeline interface{}, opts ...options.Lister[options.ChangeStreamOptions])) *ConnectorMock_Watch_Call {
+func (_c *ConnectorMock_WatchDeployment_Call) Run(run func(pipeline interface{}, opts ...options.Lister[options.ChangeStreamOptions])) *ConnectorMock_WatchDeployment_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 interface{}
 		if args[0] != nil {
@@ -2140,12 +2981,63 @@ func (_c *ConnectorMock_Watch_Call) Run(run func(pipeline interface{}, opts ...o
 	return _c
 }
 
-func (_c *ConnectorMock_Watch_Call) Return(stream *mongo.ChangeStream, err error) *ConnectorMock_Watch_Call {
+func (_c *ConnectorMock_WatchDeployment_Call) Return(stream *mongo.ChangeStream, err error) *ConnectorMock_WatchDeployment_Call {
 	_c.Call.Return(stream, err)
 	return _c
 }
 
-func (_c *ConnectorMock_Watch_Call) RunAndReturn(run func(pipeline interface{}, opts ...options.Lister[options.ChangeStreamOptions]) (*mongo.ChangeStream, error)) *ConnectorMock_Watch_Call {
+func (_c *ConnectorMock_WatchDeployment_Call) RunAndReturn(run func(pipeline interface{}, opts ...options.Lister[options.ChangeStreamOptions]) (*mongo.ChangeStream, error)) *ConnectorMock_WatchDeployment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WithCausalConsistency provides a mock function for the type ConnectorMock
+func (_mock *ConnectorMock) WithCausalConsistency(fn func(conn mongodb.Connector) error) error {
+	ret := _mock.Called(fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithCausalConsistency")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(func(conn mongodb.Connector) error) error); ok {
+		r0 = returnFunc(fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// ConnectorMock_WithCausalConsistency_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithCausalConsistency'
+type ConnectorMock_WithCausalConsistency_Call struct {
+	*mock.Call
+}
+
+// WithCausalConsistency is a helper method to define mock.On call
+//   - fn func(conn mongodb.Connector) error
+func (_e *ConnectorMock_Expecter) WithCausalConsistency(fn interface{}) *ConnectorMock_WithCausalConsistency_Call {
+	return &ConnectorMock_WithCausalConsistency_Call{Call: _e.mock.On("WithCausalConsistency", fn)}
+}
+
+func (_c *ConnectorMock_WithCausalConsistency_Call) Run(run func(fn func(conn mongodb.Connector) error)) *ConnectorMock_WithCausalConsistency_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 func(conn mongodb.Connector) error
+		if args[0] != nil {
+			arg0 = args[0].(func(conn mongodb.Connector) error)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *ConnectorMock_WithCausalConsistency_Call) Return(err error) *ConnectorMock_WithCausalConsistency_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *ConnectorMock_WithCausalConsistency_Call) RunAndReturn(run func(fn func(conn mongodb.Connector) error) error) *ConnectorMock_WithCausalConsistency_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -2274,3 +3166,126 @@ func (_c *ConnectorMock_WithContext_Call) RunAndReturn(run func(context1 context
 	_c.Call.Return(run)
 	return _c
 }
+
+// WithReadPreference provides a mock function for the type ConnectorMock
+func (_mock *ConnectorMock) WithReadPreference(rp *readpref.ReadPref) mongodb.Connector {
+	ret := _mock.Called(rp)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithReadPreference")
+	}
+
+	var r0 mongodb.Connector
+	if returnFunc, ok := ret.Get(0).(func(*readpref.ReadPref) mongodb.Connector); ok {
+		r0 = returnFunc(rp)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(mongodb.Connector)
+		}
+	}
+	return r0
+}
+
+// ConnectorMock_WithReadPreference_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithReadPreference'
+type ConnectorMock_WithReadPreference_Call struct {
+	*mock.Call
+}
+
+// WithReadPreference is a helper method to define mock.On call
+//   - rp *readpref.ReadPref
+func (_e *ConnectorMock_Expecter) WithReadPreference(rp interface{}) *ConnectorMock_WithReadPreference_Call {
+	return &ConnectorMock_WithReadPreference_Call{Call: _e.mock.On("WithReadPreference", rp)}
+}
+
+func (_c *ConnectorMock_WithReadPreference_Call) Run(run func(rp *readpref.ReadPref)) *ConnectorMock_WithReadPreference_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *readpref.ReadPref
+		if args[0] != nil {
+			arg0 = args[0].(*readpref.ReadPref)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *ConnectorMock_WithReadPreference_Call) Return(connector mongodb.Connector) *ConnectorMock_WithReadPreference_Call {
+	_c.Call.Return(connector)
+	return _c
+}
+
+func (_c *ConnectorMock_WithReadPreference_Call) RunAndReturn(run func(rp *readpref.ReadPref) mongodb.Connector) *ConnectorMock_WithReadPreference_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WithTransaction provides a mock function for the type ConnectorMock
+func (_mock *ConnectorMock) WithTransaction(fn func(conn mongodb.Connector) error, opts ...options.Lister[options.TransactionOptions]) error {
+	// options.Lister[options.TransactionOptions]
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, fn)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithTransaction")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(func(conn mongodb.Connector) error, ...options.Lister[options.TransactionOptions]) error); ok {
+		r0 = returnFunc(fn, opts...)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// ConnectorMock_WithTransaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithTransaction'
+type ConnectorMock_WithTransaction_Call struct {
+	*mock.Call
+}
+
+// WithTransaction is a helper method to define mock.On call
+//   - fn func(conn mongodb.Connector) error
+//   - opts ...options.Lister[options.TransactionOptions]
+func (_e *ConnectorMock_Expecter) WithTransaction(fn interface{}, opts ...interface{}) *ConnectorMock_WithTransaction_Call {
+	return &ConnectorMock_WithTransaction_Call{Call: _e.mock.On("WithTransaction",
+		append([]interface{}{fn}, opts...)...)}
+}
+
+func (_c *ConnectorMock_WithTransaction_Call) Run(run func(fn func(conn mongodb.Connector) error, opts ...options.Lister[options.TransactionOptions])) *ConnectorMock_WithTransaction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 func(conn mongodb.Connector) error
+		if args[0] != nil {
+			arg0 = args[0].(func(conn mongodb.Connector) error)
+		}
+		var arg1 []options.Lister[options.TransactionOptions]
+		variadicArgs := make([]options.Lister[options.TransactionOptions], len(args)-1)
+		for i, a := range args[1:] {
+			if a != nil {
+				variadicArgs[i] = a.(options.Lister[options.TransactionOptions])
+			}
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *ConnectorMock_WithTransaction_Call) Return(err error) *ConnectorMock_WithTransaction_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *ConnectorMock_WithTransaction_Call) RunAndReturn(run func(fn func(conn mongodb.Connector) error, opts ...options.Lister[options.TransactionOptions]) error) *ConnectorMock_WithTransaction_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,153 @@
+package mongodb
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// sentinel errors returned by WrapError, they can be checked using errors.Is
+var (
+	ErrNotFound     = errors.New("document not found")
+	ErrDuplicateKey = errors.New("duplicate key")
+	ErrTimeout      = errors.New("operation timed out")
+	ErrNetwork      = errors.New("network error")
+)
+
+var (
+	dupKeyIndex  = regexp.MustCompile(`index: (\S+)`)
+	dupKeyValues = regexp.MustCompile(`dup key: \{(.*)\}`)
+	dupKeyPair   = regexp.MustCompile(`([\w.$]+): ("(?:[^"\\]|\\.)*"|[^,]+)`)
+)
+
+// IsNotFound reports whether err is caused by a missing document, e.g. mongo.ErrNoDocuments.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound) || errors.Is(err, mongo.ErrNoDocuments)
+}
+
+// IsDuplicateKey reports whether err is caused by a violated unique index.
+func IsDuplicateKey(err error) bool {
+	return errors.Is(err, ErrDuplicateKey) || mongo.IsDuplicateKeyError(err)
+}
+
+// IsTimeout reports whether err is caused by a timeout, including the server selection and context deadlines.
+func IsTimeout(err error) bool {
+	return errors.Is(err, ErrTimeout) || mongo.IsTimeout(err)
+}
+
+// IsNetworkError reports whether err is caused by a network error.
+func IsNetworkError(err error) bool {
+	return errors.Is(err, ErrNetwork) || mongo.IsNetworkError(err)
+}
+
+// classifiedError joins a sentinel error with the original error, so both of them can be found by errors.Is and errors.As.
+type classifiedError struct {
+	sentinel error
+	err      error
+}
+
+func (e classifiedError) Error() string {
+	return e.sentinel.Error() + ": " + e.err.Error()
+}
+
+func (e classifiedError) Unwrap() []error {
+	return []error{e.sentinel, e.err}
+}
+
+// WrapError wraps the driver errors into the sentinel errors ErrNotFound, ErrDuplicateKey, ErrTimeout and ErrNetwork,
+// the original error is kept, so errors.As still finds e.g. a mongo.WriteException. Other errors are returned as is.
+// It is meant to be used by the database access layer, so the callers do not depend on the driver errors.
+func WrapError(err error) error {
+	var sentinel error
+
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, mongo.ErrNoDocuments):
+		sentinel = ErrNotFound
+	case mongo.IsDuplicateKeyError(err):
+		sentinel = ErrDuplicateKey
+	case mongo.IsTimeout(err):
+		sentinel = ErrTimeout
+	case mongo.IsNetworkError(err):
+		sentinel = ErrNetwork
+	default:
+		return err
+	}
+
+	if errors.Is(err, sentinel) {
+		return err
+	}
+
+	return classifiedError{sentinel: sentinel, err: err}
+}
+
+// DuplicateKeyFields returns the name of the violated unique index and the duplicated values by field,
+// ok is false if err is not a duplicate key error. The values are taken from the keyValue of the server response,
+// for older servers they are parsed from the error message, in this case the values are strings.
+func DuplicateKeyFields(err error) (index string, keys bson.M, ok bool) {
+	var we mongo.WriteException
+	if errors.As(err, &we) {
+		for _, e := range we.WriteErrors {
+			if isDuplicateKeyCode(e.Code) {
+				index, keys = parseDuplicateKey(e.Message, e.Raw)
+				return index, keys, true
+			}
+		}
+	}
+
+	var bwe mongo.BulkWriteException
+	if errors.As(err, &bwe) {
+		for _, e := range bwe.WriteErrors {
+			if isDuplicateKeyCode(e.Code) {
+				index, keys = parseDuplicateKey(e.Message, e.Raw)
+				return index, keys, true
+			}
+		}
+	}
+
+	var ce mongo.CommandError
+	if errors.As(err, &ce) && isDuplicateKeyCode(int(ce.Code)) {
+		index, keys = parseDuplicateKey(ce.Message, ce.Raw)
+		return index, keys, true
+	}
+
+	return "", nil, false
+}
+
+func isDuplicateKeyCode(code int) bool {
+	return code == 11000 || code == 11001 || code == 12582
+}
+
+// parseDuplicateKey extracts the index and the key values from a duplicate key error.
+func parseDuplicateKey(msg string, raw bson.Raw) (index string, keys bson.M) {
+	if m := dupKeyIndex.FindStringSubmatch(msg); m != nil {
+		index = m[1]
+	}
+
+	if kv, ok := raw.Lookup("keyValue").DocumentOK(); ok {
+		if err := bson.Unmarshal(kv, &keys); err == nil {
+			return index, keys
+		}
+	}
+
+	m := dupKeyValues.FindStringSubmatch(msg)
+	if m == nil {
+		return index, nil
+	}
+
+	keys = bson.M{}
+	for _, pair := range dupKeyPair.FindAllStringSubmatch(m[1], -1) {
+		value := strings.TrimSpace(pair[2])
+		if s, err := strconv.Unquote(value); err == nil {
+			value = s
+		}
+		keys[pair[1]] = value
+	}
+
+	return index, keys
+}
@@ -0,0 +1,110 @@
+package mongodb_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mbretter/go-mongodb/v2"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func duplicateKeyError(raw bson.Raw) mongo.WriteException {
+	return mongo.WriteException{WriteErrors: []mongo.WriteError{{
+		Code:    11000,
+		Message: `E11000 duplicate key error collection: test.users index: username_1_tenant_1 dup key: { username: "foo, bar", tenant: 3 }`,
+		Raw:     raw,
+	}}}
+}
+
+func TestErrorHelpers(t *testing.T) {
+	dupErr := duplicateKeyError(nil)
+	timeoutErr := fmt.Errorf("find: %w", context.DeadlineExceeded)
+
+	assert.True(t, mongodb.IsNotFound(mongo.ErrNoDocuments))
+	assert.True(t, mongodb.IsNotFound(mongodb.ErrNotFound))
+	assert.False(t, mongodb.IsNotFound(dupErr))
+
+	assert.True(t, mongodb.IsDuplicateKey(dupErr))
+	assert.True(t, mongodb.IsDuplicateKey(mongodb.ErrDuplicateKey))
+	assert.False(t, mongodb.IsDuplicateKey(mongo.ErrNoDocuments))
+
+	assert.True(t, mongodb.IsTimeout(timeoutErr))
+	assert.False(t, mongodb.IsTimeout(dupErr))
+
+	assert.True(t, mongodb.IsNetworkError(mongodb.ErrNetwork))
+	assert.True(t, mongodb.IsNetworkError(mongo.CommandError{Labels: []string{"NetworkError"}}))
+	assert.False(t, mongodb.IsNetworkError(dupErr))
+}
+
+func TestWrapError(t *testing.T) {
+	dupErr := duplicateKeyError(nil)
+	otherErr := errors.New("failed")
+
+	tests := []struct {
+		name     string
+		err      error
+		sentinel error
+	}{
+		{"not found", mongo.ErrNoDocuments, mongodb.ErrNotFound},
+		{"duplicate key", dupErr, mongodb.ErrDuplicateKey},
+		{"timeout", context.DeadlineExceeded, mongodb.ErrTimeout},
+		{"network", mongo.CommandError{Labels: []string{"NetworkError"}}, mongodb.ErrNetwork},
+		{"no collection", mongodb.ErrNoCollectionSet, mongodb.ErrNoCollectionSet},
+		{"other", otherErr, otherErr},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := mongodb.WrapError(test.err)
+
+			assert.True(t, errors.Is(err, test.sentinel))
+			assert.Contains(t, err.Error(), test.err.Error())
+			assert.Equal(t, err, mongodb.WrapError(err))
+		})
+	}
+
+	var we mongo.WriteException
+	assert.True(t, errors.As(mongodb.WrapError(dupErr), &we))
+	assert.True(t, errors.Is(mongodb.WrapError(mongo.ErrNoDocuments), mongo.ErrNoDocuments))
+	assert.True(t, mongo.IsDuplicateKeyError(mongodb.WrapError(dupErr)))
+	assert.Nil(t, mongodb.WrapError(nil))
+}
+
+func TestDuplicateKeyFields(t *testing.T) {
+	raw, _ := bson.Marshal(bson.D{
+		{"code", 11000},
+		{"keyPattern", bson.D{{"username", 1}, {"tenant", 1}}},
+		{"keyValue", bson.D{{"username", "foo, bar"}, {"tenant", int32(3)}}},
+	})
+
+	tests := []struct {
+		name  string
+		err   error
+		index string
+		keys  bson.M
+		ok    bool
+	}{
+		{"key value", duplicateKeyError(raw), "username_1_tenant_1", bson.M{"username": "foo, bar", "tenant": int32(3)}, true},
+		{"message", duplicateKeyError(nil), "username_1_tenant_1", bson.M{"username": "foo, bar", "tenant": "3"}, true},
+		{"wrapped", mongodb.WrapError(duplicateKeyError(raw)), "username_1_tenant_1", bson.M{"username": "foo, bar", "tenant": int32(3)}, true},
+		{"command", mongo.CommandError{Code: 11000, Message: `E11000 duplicate key error collection: test.seq index: _id_ dup key: { _id: "users" }`},
+			"_id_", bson.M{"_id": "users"}, true},
+		{"bulk", mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{{WriteError: duplicateKeyError(raw).WriteErrors[0]}}},
+			"username_1_tenant_1", bson.M{"username": "foo, bar", "tenant": int32(3)}, true},
+		{"other", mongo.ErrNoDocuments, "", nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			index, keys, ok := mongodb.DuplicateKeyFields(test.err)
+
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.index, index)
+			assert.Equal(t, test.keys, keys)
+		})
+	}
+}
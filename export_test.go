@@ -0,0 +1,4 @@
+package mongodb
+
+// SetValidatorWith exposes setValidator to the tests using the ConnectorMock.
+var SetValidatorWith = setValidator
@@ -0,0 +1,54 @@
+package mongodb
+
+import (
+	"github.com/mbretter/go-mongodb/v2/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// earthRadius is the equatorial radius of the earth in meters, as used by the server for spherical geometry.
+const earthRadius = 6378100.0
+
+// GeoIndexModel returns the model of a 2dsphere index on the given fields holding GeoJSON geometries,
+// to be created using CreateIndex.
+func GeoIndexModel(fields ...string) mongo.IndexModel {
+	keys := bson.D{}
+	for _, field := range fields {
+		keys = append(keys, bson.E{field, "2dsphere"})
+	}
+
+	return mongo.IndexModel{Keys: keys}
+}
+
+// NearFilter returns a $near filter on field, which sorts the documents by their distance to the point, nearest first.
+// The distances are in meters, a distance less than or equal to zero is omitted. It requires a 2dsphere index.
+func NearFilter(field string, point types.Point, minDistance float64, maxDistance float64) bson.D {
+	near := bson.D{{"$geometry", point}}
+	if minDistance > 0 {
+		near = append(near, bson.E{"$minDistance", minDistance})
+	}
+	if maxDistance > 0 {
+		near = append(near, bson.E{"$maxDistance", maxDistance})
+	}
+
+	return bson.D{{field, bson.D{{"$near", near}}}}
+}
+
+// GeoWithinFilter returns a $geoWithin filter on field, matching the geometries entirely within the polygon
+// or multi polygon.
+func GeoWithinFilter(field string, geometry types.Geometry) bson.D {
+	return bson.D{{field, bson.D{{"$geoWithin", bson.D{{"$geometry", geometry}}}}}}
+}
+
+// GeoWithinRadiusFilter returns a $geoWithin filter on field, matching the geometries within the radius in meters
+// around the center. Unlike NearFilter, the documents are not sorted and no index is required.
+func GeoWithinRadiusFilter(field string, center types.Point, radius float64) bson.D {
+	return bson.D{{field, bson.D{{"$geoWithin", bson.D{
+		{"$centerSphere", bson.A{center.Coordinates, radius / earthRadius}},
+	}}}}}
+}
+
+// GeoIntersectsFilter returns a $geoIntersects filter on field, matching the geometries intersecting the geometry.
+func GeoIntersectsFilter(field string, geometry types.Geometry) bson.D {
+	return bson.D{{field, bson.D{{"$geoIntersects", bson.D{{"$geometry", geometry}}}}}}
+}
@@ -0,0 +1,57 @@
+package mongodb_test
+
+import (
+	"testing"
+
+	"github.com/mbretter/go-mongodb/v2"
+	"github.com/mbretter/go-mongodb/v2/types"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestGeoIndexModel(t *testing.T) {
+	assert.Equal(t, mongo.IndexModel{Keys: bson.D{{"location", "2dsphere"}, {"area", "2dsphere"}}},
+		mongodb.GeoIndexModel("location", "area"))
+}
+
+func TestNearFilter(t *testing.T) {
+	point := types.NewPoint(16.37, 48.21)
+
+	assert.Equal(t, bson.D{{"location", bson.D{{"$near", bson.D{
+		{"$geometry", point},
+		{"$minDistance", 10.0},
+		{"$maxDistance", 5000.0},
+	}}}}}, mongodb.NearFilter("location", point, 10, 5000))
+
+	assert.Equal(t, bson.D{{"location", bson.D{{"$near", bson.D{{"$geometry", point}}}}}},
+		mongodb.NearFilter("location", point, 0, 0))
+}
+
+func TestGeoWithinFilter(t *testing.T) {
+	area := types.NewPolygon([]types.Position{{16, 48}, {17, 48}, {17, 49}})
+
+	assert.Equal(t, bson.D{{"location", bson.D{{"$geoWithin", bson.D{{"$geometry", area}}}}}},
+		mongodb.GeoWithinFilter("location", area))
+}
+
+func TestGeoWithinRadiusFilter(t *testing.T) {
+	filter := mongodb.GeoWithinRadiusFilter("location", types.NewPoint(16.37, 48.21), 6378.1)
+
+	assert.Equal(t, bson.D{{"location", bson.D{{"$geoWithin", bson.D{
+		{"$centerSphere", bson.A{types.Position{16.37, 48.21}, 0.001}},
+	}}}}}, filter)
+}
+
+func TestGeoIntersectsFilter(t *testing.T) {
+	route := types.LineString{Coordinates: []types.Position{{16, 48}, {17, 49}}}
+
+	f := mongodb.GeoIntersectsFilter("area", route)
+	assert.Equal(t, bson.D{{"area", bson.D{{"$geoIntersects", bson.D{{"$geometry", route}}}}}}, f)
+
+	// the geometry is rendered as GeoJSON
+	data, err := bson.Marshal(f)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "LineString", bson.Raw(data).Lookup("area", "$geoIntersects", "$geometry", "type").StringValue())
+	}
+}
@@ -0,0 +1,248 @@
+// Package conntest provides a fake mongodb.Connector for the tests of the packages built on top of the connector.
+//
+// The fake records the calls of the operations used by these packages and returns configurable results, calling
+// any other method of the connector panics. Filters and updates have to be bson.D.
+package conntest
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/mbretter/go-mongodb/v2"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Call is a recorded operation.
+type Call struct {
+	Op         string
+	Collection string
+	Filter     bson.D
+	Update     bson.D
+	// Document is the inserted document, or the index model of CreateIndex.
+	Document interface{}
+}
+
+// Fake holds the results and the recorded calls of the connectors returned by Conn.
+// The hooks replace the default results of their operation, if set.
+type Fake struct {
+	mu    sync.Mutex
+	calls []Call
+
+	// Seq is the last number returned by GetNextSeq, each call increments it.
+	Seq int64
+	// Docs are returned one by one by FindOneAndUpdate, afterward mongo.ErrNoDocuments is returned.
+	Docs []interface{}
+	// Matched is the number of matched documents of UpdateOne and the number of deleted documents of DeleteOne.
+	Matched int64
+	// Errors are returned by the operations with the given name, e.g. "InsertOne".
+	Errors map[string]error
+
+	OnFind             func(call Call) ([]interface{}, error)
+	OnFindOneAndUpdate func(call Call) (interface{}, error)
+	OnUpdateOne        func(call Call) (*mongo.UpdateResult, error)
+	OnInsertOne        func(call Call) error
+	OnDeleteOne        func(call Call) (*mongo.DeleteResult, error)
+	OnDeleteMany       func(call Call) (*mongo.DeleteResult, error)
+}
+
+// Conn returns a connector backed by the fake.
+func (f *Fake) Conn() mongodb.Connector {
+	return &conn{fake: f, ctx: context.Background()}
+}
+
+// Calls returns the recorded calls of the given operations, all calls if no operation is given.
+func (f *Fake) Calls(ops ...string) []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	calls := make([]Call, 0, len(f.calls))
+	for _, call := range f.calls {
+		if len(ops) == 0 || slices.Contains(ops, call.Op) {
+			calls = append(calls, call)
+		}
+	}
+
+	return calls
+}
+
+// record records the call and returns the configured error of the operation.
+func (f *Fake) record(call Call) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, call)
+
+	return f.Errors[call.Op]
+}
+
+// conn is the connector of the fake bound to a context and a collection, the operations fail with the error of
+// the context, if it is done.
+type conn struct {
+	mongodb.Connector
+	fake       *Fake
+	ctx        context.Context
+	collection string
+}
+
+func (c *conn) WithContext(ctx context.Context) mongodb.Connector {
+	return &conn{fake: c.fake, ctx: ctx, collection: c.collection}
+}
+
+func (c *conn) WithCollection(coll string, _ ...options.Lister[options.CollectionOptions]) mongodb.Connector {
+	return &conn{fake: c.fake, ctx: c.ctx, collection: coll}
+}
+
+// call records the call and returns the error of the operation.
+func (c *conn) call(op string, filter interface{}, update interface{}, doc interface{}) (Call, error) {
+	call := Call{Op: op, Collection: c.collection, Document: doc}
+	if filter != nil {
+		call.Filter = filter.(bson.D)
+	}
+	if update != nil {
+		call.Update = update.(bson.D)
+	}
+
+	if err := c.ctx.Err(); err != nil {
+		return call, err
+	}
+
+	return call, c.fake.record(call)
+}
+
+func (c *conn) GetNextSeq(name string, _ ...*mongodb.SeqOptions) (int64, error) {
+	if _, err := c.call("GetNextSeq", nil, nil, name); err != nil {
+		return 0, err
+	}
+
+	c.fake.mu.Lock()
+	defer c.fake.mu.Unlock()
+	c.fake.Seq++
+
+	return c.fake.Seq, nil
+}
+
+func (c *conn) Find(filter interface{}, _ ...options.Lister[options.FindOptions]) (*mongo.Cursor, error) {
+	call, err := c.call("Find", filter, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []interface{}
+	if c.fake.OnFind != nil {
+		if docs, err = c.fake.OnFind(call); err != nil {
+			return nil, err
+		}
+	}
+
+	return mongo.NewCursorFromDocuments(docs, nil, nil)
+}
+
+func (c *conn) FetchAll(cur *mongo.Cursor, results interface{}) error {
+	return cur.All(c.ctx, results)
+}
+
+func (c *conn) Next(cur *mongo.Cursor) bool {
+	return cur.Next(c.ctx)
+}
+
+func (c *conn) Decode(cur *mongo.Cursor, val interface{}) error {
+	return cur.Decode(val)
+}
+
+func (c *conn) FindOneAndUpdate(filter interface{}, update interface{}, _ ...options.Lister[options.FindOneAndUpdateOptions]) *mongo.SingleResult {
+	call, err := c.call("FindOneAndUpdate", filter, update, nil)
+	if err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+
+	if c.fake.OnFindOneAndUpdate != nil {
+		doc, err := c.fake.OnFindOneAndUpdate(call)
+		if doc == nil {
+			doc = bson.D{}
+		}
+		return mongo.NewSingleResultFromDocument(doc, err, nil)
+	}
+
+	c.fake.mu.Lock()
+	defer c.fake.mu.Unlock()
+
+	if len(c.fake.Docs) == 0 {
+		return mongo.NewSingleResultFromDocument(bson.D{}, mongo.ErrNoDocuments, nil)
+	}
+
+	doc := c.fake.Docs[0]
+	c.fake.Docs = c.fake.Docs[1:]
+
+	return mongo.NewSingleResultFromDocument(doc, nil, nil)
+}
+
+func (c *conn) UpdateOne(filter interface{}, update interface{}, _ ...options.Lister[options.UpdateOneOptions]) (*mongo.UpdateResult, error) {
+	call, err := c.call("UpdateOne", filter, update, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.fake.OnUpdateOne != nil {
+		return c.fake.OnUpdateOne(call)
+	}
+
+	c.fake.mu.Lock()
+	defer c.fake.mu.Unlock()
+
+	return &mongo.UpdateResult{MatchedCount: c.fake.Matched, ModifiedCount: c.fake.Matched}, nil
+}
+
+func (c *conn) InsertOne(doc interface{}, _ ...options.Lister[options.InsertOneOptions]) (*mongo.InsertOneResult, error) {
+	call, err := c.call("InsertOne", nil, nil, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.fake.OnInsertOne != nil {
+		if err := c.fake.OnInsertOne(call); err != nil {
+			return nil, err
+		}
+	}
+
+	return &mongo.InsertOneResult{}, nil
+}
+
+func (c *conn) DeleteOne(filter interface{}, _ ...options.Lister[options.DeleteOneOptions]) (*mongo.DeleteResult, error) {
+	call, err := c.call("DeleteOne", filter, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.fake.OnDeleteOne != nil {
+		return c.fake.OnDeleteOne(call)
+	}
+
+	c.fake.mu.Lock()
+	defer c.fake.mu.Unlock()
+
+	return &mongo.DeleteResult{DeletedCount: c.fake.Matched}, nil
+}
+
+func (c *conn) DeleteMany(filter interface{}, _ ...options.Lister[options.DeleteManyOptions]) (*mongo.DeleteResult, error) {
+	call, err := c.call("DeleteMany", filter, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.fake.OnDeleteMany != nil {
+		return c.fake.OnDeleteMany(call)
+	}
+
+	return &mongo.DeleteResult{}, nil
+}
+
+func (c *conn) CreateIndex(model mongo.IndexModel, _ ...options.Lister[options.CreateIndexesOptions]) (string, error) {
+	if _, err := c.call("CreateIndex", nil, nil, model); err != nil {
+		return "", err
+	}
+
+	return "", nil
+}
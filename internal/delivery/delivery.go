@@ -0,0 +1,83 @@
+// Package delivery contains the redelivery logic shared by the queue and the outbox: the exponential backoff of
+// failed attempts, moving documents into a dead-letter collection and waking up the workers on inserts.
+package delivery
+
+import (
+	"context"
+	"time"
+
+	"github.com/mbretter/go-mongodb/v2"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// Backoff is an exponential backoff bounded by Min and Max.
+type Backoff struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// NewBackoff returns the backoff bounded by minDelay and maxDelay, minDelay defaults to 1s and maxDelay to 1h,
+// but at least minDelay.
+func NewBackoff(minDelay time.Duration, maxDelay time.Duration) Backoff {
+	if minDelay <= 0 {
+		minDelay = time.Second
+	}
+
+	if maxDelay < minDelay {
+		maxDelay = max(time.Hour, minDelay)
+	}
+
+	return Backoff{Min: minDelay, Max: maxDelay}
+}
+
+// Delay returns the delay before the next attempt, it doubles with each attempt bounded by Min and Max.
+func (b Backoff) Delay(attempts int) time.Duration {
+	delay := b.Min
+	for i := 1; i < attempts && delay < b.Max; i++ {
+		delay *= 2
+	}
+
+	return min(delay, b.Max)
+}
+
+// DeadLetter inserts doc into the dead-letter collection coll, the caller removes the original document afterward.
+// A document, which has been moved before, but not removed, is ignored.
+func DeadLetter(conn mongodb.Connector, coll string, doc interface{}) error {
+	_, err := conn.WithCollection(coll).InsertOne(doc)
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+
+	return nil
+}
+
+// Watch signals the documents inserted into the collection of conn on wakeup, until ctx is done. The errors are
+// passed to onError, after a fatal error Watch returns and the callers fall back to polling.
+func Watch(ctx context.Context, conn mongodb.Connector, wakeup chan<- struct{}, onError func(err error)) {
+	consumer := mongodb.NewChangeStreamConsumer(conn, mongodb.ChangeStreamParams{
+		Pipeline: mongo.Pipeline{{{"$match", bson.D{{"operationType", "insert"}}}}},
+		OnError: func(err error, _ time.Duration) {
+			onError(err)
+		},
+	}, func(mongodb.ChangeEvent[bson.Raw]) error {
+		select {
+		case wakeup <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+
+	if err := consumer.Run(ctx); err != nil {
+		onError(err)
+	}
+}
+
+// Wait blocks until ctx is done, a wakeup is signaled or the poll interval elapsed.
+func Wait(ctx context.Context, wakeup <-chan struct{}, pollInterval time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-wakeup:
+	case <-time.After(pollInterval):
+	}
+}
@@ -0,0 +1,70 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mbretter/go-mongodb/v2/internal/conntest"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestNewBackoff(t *testing.T) {
+	assert.Equal(t, Backoff{Min: time.Second, Max: time.Hour}, NewBackoff(0, 0))
+	assert.Equal(t, Backoff{Min: 2 * time.Hour, Max: 2 * time.Hour}, NewBackoff(2*time.Hour, time.Minute))
+	assert.Equal(t, Backoff{Min: time.Second, Max: 5 * time.Second}, NewBackoff(time.Second, 5*time.Second))
+}
+
+func TestBackoff_Delay(t *testing.T) {
+	b := NewBackoff(time.Second, 5*time.Second)
+
+	assert.Equal(t, time.Second, b.Delay(1))
+	assert.Equal(t, 2*time.Second, b.Delay(2))
+	assert.Equal(t, 4*time.Second, b.Delay(3))
+	assert.Equal(t, 5*time.Second, b.Delay(4))
+	assert.Equal(t, 5*time.Second, b.Delay(100))
+}
+
+func TestDeadLetter(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		exp  error
+	}{
+		{"moved", nil, nil},
+		{"moved before", mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000}}}, nil},
+		{"failed", errors.New("network"), errors.New("network")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := &conntest.Fake{Errors: map[string]error{"InsertOne": test.err}}
+
+			err := DeadLetter(fake.Conn().WithCollection("Jobs"), "DeadJobs", bson.D{{"_id", 1}})
+
+			assert.Equal(t, test.exp, err)
+			if calls := fake.Calls("InsertOne"); assert.Len(t, calls, 1) {
+				assert.Equal(t, "DeadJobs", calls[0].Collection)
+				assert.Equal(t, bson.D{{"_id", 1}}, calls[0].Document)
+			}
+		})
+	}
+}
+
+func TestWait(t *testing.T) {
+	wakeup := make(chan struct{}, 1)
+	wakeup <- struct{}{}
+
+	start := time.Now()
+	Wait(context.Background(), wakeup, time.Minute)
+	Wait(context.Background(), wakeup, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	Wait(ctx, wakeup, time.Minute)
+
+	assert.Less(t, time.Since(start), time.Second)
+}
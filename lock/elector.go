@@ -0,0 +1,160 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// ElectorParams holds the parameters of the LeaderElector.
+type ElectorParams struct {
+	// Role is the name of the lock the instances compete for.
+	Role string
+	// Identity identifies the instance, defaults to hostname and pid.
+	Identity string
+	// Ttl is the lease of the leader, another instance takes over if the leader did not renew it, defaults to 15 seconds.
+	Ttl time.Duration
+	// Interval is the time between the renewals of the leader and the attempts of the followers, defaults to a third
+	// of Ttl, but at least 10ms.
+	Interval time.Duration
+	// OnElected is called when the instance became the leader.
+	OnElected func()
+	// OnDeposed is called when the instance lost the leadership or stepped down.
+	OnDeposed func()
+	// OnError is called with the errors of acquiring and renewing the lease.
+	OnError func(err error)
+}
+
+// LeaderElector lets multiple instances compete for the leadership of a role, only one instance at a time is the leader.
+// The callbacks are called from the goroutine of Run, so they should not block.
+type LeaderElector struct {
+	locker *Locker
+	params ElectorParams
+	mu     sync.Mutex
+	lock   *Lock
+}
+
+// NewLeaderElector creates a new LeaderElector using the given locker and parameters.
+func NewLeaderElector(locker *Locker, params ElectorParams) *LeaderElector {
+	if len(params.Identity) == 0 {
+		host, _ := os.Hostname()
+		params.Identity = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+
+	if params.Ttl <= 0 {
+		params.Ttl = 15 * time.Second
+	}
+
+	if params.Interval <= 0 {
+		params.Interval = renewInterval(params.Ttl)
+	}
+
+	return &LeaderElector{locker: locker, params: params}
+}
+
+// IsLeader returns true if the instance currently holds the leadership.
+func (e *LeaderElector) IsLeader() bool {
+	return e.Lock() != nil
+}
+
+// Lock returns the lock of the leader, or nil if the instance is not the leader.
+// Its token can be used for fencing the writes of the leader.
+func (e *LeaderElector) Lock() *Lock {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.lock
+}
+
+// Run campaigns for the leadership and renews it while being the leader, until the context is done.
+// On cancellation, the leader steps down by releasing the lock, so another instance can take over immediately.
+func (e *LeaderElector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.params.Interval)
+	defer ticker.Stop()
+
+	for {
+		if lk := e.Lock(); lk == nil {
+			e.campaign()
+		} else {
+			e.renew(lk)
+		}
+
+		select {
+		case <-ctx.Done():
+			e.stepDown()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// campaign tries to acquire the lock of the role.
+func (e *LeaderElector) campaign() {
+	lk, err := e.locker.Acquire(e.params.Role, e.params.Identity, e.params.Ttl)
+	if errors.Is(err, ErrLocked) {
+		return
+	}
+
+	if err != nil {
+		e.onError(err)
+		return
+	}
+
+	e.setLock(lk)
+	if e.params.OnElected != nil {
+		e.params.OnElected()
+	}
+}
+
+// renew extends the lease, the leadership is lost if the lock has been taken over or expired.
+func (e *LeaderElector) renew(lk *Lock) {
+	err := lk.Renew()
+	if err == nil {
+		return
+	}
+
+	if !errors.Is(err, ErrNotHeld) {
+		e.onError(err)
+		if time.Now().Before(lk.ExpiresAt()) {
+			return
+		}
+	}
+
+	e.depose()
+}
+
+// stepDown releases the lock, if the instance is the leader.
+func (e *LeaderElector) stepDown() {
+	lk := e.Lock()
+	if lk == nil {
+		return
+	}
+
+	if err := lk.Release(); err != nil && !errors.Is(err, ErrNotHeld) {
+		e.onError(err)
+	}
+
+	e.depose()
+}
+
+func (e *LeaderElector) depose() {
+	e.setLock(nil)
+	if e.params.OnDeposed != nil {
+		e.params.OnDeposed()
+	}
+}
+
+func (e *LeaderElector) setLock(lk *Lock) {
+	e.mu.Lock()
+	e.lock = lk
+	e.mu.Unlock()
+}
+
+func (e *LeaderElector) onError(err error) {
+	if e.params.OnError != nil {
+		e.params.OnError(err)
+	}
+}
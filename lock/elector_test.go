@@ -0,0 +1,93 @@
+package lock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mbretter/go-mongodb/v2/internal/conntest"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestNewLeaderElector_Defaults(t *testing.T) {
+	e := NewLeaderElector(nil, ElectorParams{Role: "cron"})
+
+	assert.NotEmpty(t, e.params.Identity)
+	assert.Equal(t, 15*time.Second, e.params.Ttl)
+	assert.Equal(t, 5*time.Second, e.params.Interval)
+}
+
+func TestNewLeaderElector_ShortTtl(t *testing.T) {
+	e := NewLeaderElector(nil, ElectorParams{Role: "cron", Ttl: 2})
+
+	assert.Equal(t, 10*time.Millisecond, e.params.Interval)
+}
+
+func TestLeaderElector_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	fake := &conntest.Fake{Matched: 1}
+	e := NewLeaderElector(NewLocker(fake.Conn(), NewParams{}), ElectorParams{
+		Role:     "cron",
+		Identity: "worker-1",
+		Interval: time.Millisecond,
+	})
+
+	var events []string
+	e.params.OnElected = func() {
+		assert.True(t, e.IsLeader())
+		assert.Equal(t, int64(1), e.Lock().Token)
+		events = append(events, "elected")
+		cancel()
+	}
+	e.params.OnDeposed = func() {
+		events = append(events, "deposed")
+	}
+
+	e.Run(ctx)
+
+	assert.False(t, e.IsLeader())
+	assert.Equal(t, []string{"elected", "deposed"}, events)
+	// acquire, release
+	assert.Len(t, fake.Calls("FindOneAndUpdate", "UpdateOne", "DeleteOne"), 2)
+}
+
+func TestLeaderElector_RunFollower(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	fake := &conntest.Fake{Errors: map[string]error{
+		"FindOneAndUpdate": mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000}}},
+	}}
+	e := NewLeaderElector(NewLocker(fake.Conn(), NewParams{}), ElectorParams{
+		Role:     "cron",
+		Interval: time.Millisecond,
+		OnElected: func() {
+			t.Fatal("follower elected")
+		},
+		OnError: func(err error) {
+			t.Fatal(err)
+		},
+	})
+
+	e.Run(ctx)
+
+	assert.False(t, e.IsLeader())
+}
+
+func TestLeaderElector_RenewLost(t *testing.T) {
+	fake := &conntest.Fake{}
+	deposed := false
+	e := NewLeaderElector(NewLocker(fake.Conn(), NewParams{}), ElectorParams{
+		Role:      "cron",
+		OnDeposed: func() { deposed = true },
+	})
+
+	e.campaign()
+	assert.True(t, e.IsLeader())
+
+	e.renew(e.Lock())
+	assert.False(t, e.IsLeader())
+	assert.True(t, deposed)
+}
@@ -0,0 +1,228 @@
+// Package lock provides distributed locks with leases built on the mongodb.Connector.
+//
+// The locks are stored in a "Locks" collection, a lock is acquired by using FindOneAndUpdate with upsert, like
+// GetNextSeq does. A lock expires after its ttl, unless it is renewed by its owner, an expired lock is taken over by
+// the next owner acquiring it, abandoned locks are removed by a TTL index.
+//
+// Each acquisition gets a fencing token from an incrementing sequence, the tokens of a lock are strictly increasing,
+// so a resource can reject the writes of an owner, whose lock has expired in the meantime.
+//
+// The LeaderElector builds on the locks to elect a single leader among multiple instances.
+package lock
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/mbretter/go-mongodb/v2"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var (
+	ErrLocked  = errors.New("lock is held by another owner")
+	ErrNotHeld = errors.New("lock is not held by the owner")
+)
+
+// NewParams holds the parameters of the Locker.
+type NewParams struct {
+	// Collection where the locks are stored, defaults to "Locks".
+	Collection string
+}
+
+// Locker acquires locks.
+type Locker struct {
+	conn       mongodb.Connector
+	collection string
+}
+
+// Lock is an acquired lock.
+type Lock struct {
+	locker *Locker
+	// Name of the lock.
+	Name string
+	// Owner holding the lock.
+	Owner string
+	// Token is the fencing token of the acquisition.
+	Token     int64
+	ttl       time.Duration
+	mu        sync.Mutex
+	expiresAt time.Time
+	stop      chan struct{}
+}
+
+// record is the document stored for each lock.
+type record struct {
+	Name      string    `bson:"_id"`
+	Owner     string    `bson:"Owner"`
+	Token     int64     `bson:"Token"`
+	ExpiresAt time.Time `bson:"ExpiresAt"`
+}
+
+// NewLocker creates a new Locker using the given connector and parameters.
+func NewLocker(conn mongodb.Connector, params NewParams) *Locker {
+	if len(params.Collection) == 0 {
+		params.Collection = "Locks"
+	}
+
+	return &Locker{conn: conn, collection: params.Collection}
+}
+
+// EnsureIndexes creates the TTL index, which removes the expired locks.
+func (l *Locker) EnsureIndexes() error {
+	_, err := l.conn.WithCollection(l.collection).CreateIndex(mongo.IndexModel{
+		Keys:    bson.D{{"ExpiresAt", 1}},
+		Options: options.Index().SetName("ExpiresAt_ttl").SetExpireAfterSeconds(0),
+	})
+
+	return err
+}
+
+// Acquire acquires the named lock for owner for the duration of ttl, an expired lock of another owner is taken over.
+// If the owner is already holding the lock, it is acquired again with a new token.
+// ErrLocked is returned if another owner holds the lock.
+func (l *Locker) Acquire(name string, owner string, ttl time.Duration) (*Lock, error) {
+	// the sequence is stored separately, because the TTL index removes expired locks
+	token, err := l.conn.GetNextSeq(l.collection)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(ttl)
+
+	res := l.conn.WithCollection(l.collection).FindOneAndUpdate(
+		bson.D{{"_id", name}, {"Token", bson.D{{"$lt", token}}}, {"$or", bson.A{
+			bson.D{{"Owner", owner}},
+			bson.D{{"ExpiresAt", bson.D{{"$lte", now}}}},
+		}}},
+		bson.D{{"$set", bson.D{{"Owner", owner}, {"Token", token}, {"ExpiresAt", expiresAt}}}},
+		options.FindOneAndUpdate().SetUpsert(true))
+
+	err = res.Err()
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, ErrLocked
+	}
+
+	// ErrNoDocuments means, that there was no lock document and the upsert succeeded
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+
+	return &Lock{
+		locker:    l,
+		Name:      name,
+		Owner:     owner,
+		Token:     token,
+		ttl:       ttl,
+		expiresAt: expiresAt,
+	}, nil
+}
+
+// ExpiresAt returns the time, when the lock expires if it is not renewed.
+func (lk *Lock) ExpiresAt() time.Time {
+	lk.mu.Lock()
+	defer lk.mu.Unlock()
+
+	return lk.expiresAt
+}
+
+// Renew extends the lock by its ttl, ErrNotHeld is returned if the lock has been released or taken over.
+func (lk *Lock) Renew() error {
+	expiresAt := time.Now().UTC().Add(lk.ttl)
+
+	res, err := lk.locker.conn.WithCollection(lk.locker.collection).UpdateOne(
+		bson.D{{"_id", lk.Name}, {"Owner", lk.Owner}, {"Token", lk.Token}},
+		bson.D{{"$set", bson.D{{"ExpiresAt", expiresAt}}}})
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return ErrNotHeld
+	}
+
+	lk.mu.Lock()
+	lk.expiresAt = expiresAt
+	lk.mu.Unlock()
+
+	return nil
+}
+
+// Release stops the heartbeat and releases the lock, ErrNotHeld is returned if the lock has been taken over.
+func (lk *Lock) Release() error {
+	lk.mu.Lock()
+	if lk.stop != nil {
+		close(lk.stop)
+		lk.stop = nil
+	}
+	lk.mu.Unlock()
+
+	res, err := lk.locker.conn.WithCollection(lk.locker.collection).DeleteOne(
+		bson.D{{"_id", lk.Name}, {"Owner", lk.Owner}, {"Token", lk.Token}})
+	if err != nil {
+		return err
+	}
+
+	if res.DeletedCount == 0 {
+		return ErrNotHeld
+	}
+
+	return nil
+}
+
+// minInterval bounds the default renewal interval for tiny ttls.
+const minInterval = 10 * time.Millisecond
+
+// renewInterval returns the default interval for renewing a lock with the given ttl, a third of the ttl.
+func renewInterval(ttl time.Duration) time.Duration {
+	return max(ttl/3, minInterval)
+}
+
+// Heartbeat renews the lock every interval in a goroutine, until the lock is released.
+// Failed renewals are retried on the next interval, the returned channel is closed if the lock has been lost,
+// either because it was taken over, or because it expired before it could be renewed.
+// The interval should be a fraction of the ttl, zero or a negative interval defaults to a third of the ttl, but at
+// least 10ms.
+func (lk *Lock) Heartbeat(interval time.Duration) <-chan struct{} {
+	if interval <= 0 {
+		interval = renewInterval(lk.ttl)
+	}
+
+	lost := make(chan struct{})
+	stop := make(chan struct{})
+
+	lk.mu.Lock()
+	if lk.stop != nil {
+		close(lk.stop)
+	}
+	lk.stop = stop
+	lk.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+
+			err := lk.Renew()
+			if errors.Is(err, ErrNotHeld) || (err != nil && time.Now().After(lk.ExpiresAt())) {
+				select {
+				case <-stop: // released in the meantime
+				default:
+					close(lost)
+				}
+				return
+			}
+		}
+	}()
+
+	return lost
+}
@@ -0,0 +1,120 @@
+package lock
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mbretter/go-mongodb/v2/internal/conntest"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestNewLocker_Defaults(t *testing.T) {
+	assert.Equal(t, "Locks", NewLocker(nil, NewParams{}).collection)
+	assert.Equal(t, "Mutex", NewLocker(nil, NewParams{Collection: "Mutex"}).collection)
+}
+
+func TestLocker_EnsureIndexes(t *testing.T) {
+	fake := &conntest.Fake{}
+
+	assert.Nil(t, NewLocker(fake.Conn(), NewParams{}).EnsureIndexes())
+	if indexes := fake.Calls("CreateIndex"); assert.Len(t, indexes, 1) {
+		assert.Equal(t, bson.D{{"ExpiresAt", 1}}, indexes[0].Document.(mongo.IndexModel).Keys)
+	}
+}
+
+func TestLocker_Acquire(t *testing.T) {
+	tests := []struct {
+		name string
+		docs []interface{}
+		err  error
+		exp  error
+	}{
+		{"inserted", nil, nil, nil},
+		{"taken over", []interface{}{bson.D{}}, nil, nil},
+		{"locked", nil, mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000}}}, ErrLocked},
+		{"failed", nil, errors.New("network"), errors.New("network")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := &conntest.Fake{Seq: 41, Docs: test.docs, Errors: map[string]error{"FindOneAndUpdate": test.err}}
+
+			lk, err := NewLocker(fake.Conn(), NewParams{}).Acquire("reports", "worker-1", time.Minute)
+
+			assert.Equal(t, test.exp, err)
+			if test.exp != nil {
+				assert.Nil(t, lk)
+				return
+			}
+
+			assert.Equal(t, "reports", lk.Name)
+			assert.Equal(t, "worker-1", lk.Owner)
+			assert.Equal(t, int64(42), lk.Token)
+			assert.WithinDuration(t, time.Now().Add(time.Minute), lk.ExpiresAt(), time.Second)
+			assert.Equal(t, bson.E{"Token", bson.D{{"$lt", int64(42)}}}, fake.Calls("FindOneAndUpdate")[0].Filter[1])
+		})
+	}
+}
+
+func TestLock_Renew(t *testing.T) {
+	fake := &conntest.Fake{Matched: 1}
+	lk := &Lock{locker: NewLocker(fake.Conn(), NewParams{}), Name: "reports", Owner: "worker-1", Token: 3, ttl: time.Minute}
+
+	assert.Nil(t, lk.Renew())
+	assert.Equal(t, bson.D{{"_id", "reports"}, {"Owner", "worker-1"}, {"Token", int64(3)}}, fake.Calls("UpdateOne")[0].Filter)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), lk.ExpiresAt(), time.Second)
+
+	fake.Matched = 0
+	assert.Equal(t, ErrNotHeld, lk.Renew())
+}
+
+func TestLock_Release(t *testing.T) {
+	fake := &conntest.Fake{Matched: 1}
+	lk := &Lock{locker: NewLocker(fake.Conn(), NewParams{}), Name: "reports", Owner: "worker-1", Token: 3}
+
+	assert.Nil(t, lk.Release())
+	assert.Equal(t, bson.D{{"_id", "reports"}, {"Owner", "worker-1"}, {"Token", int64(3)}}, fake.Calls("DeleteOne")[0].Filter)
+
+	fake.Matched = 0
+	assert.Equal(t, ErrNotHeld, lk.Release())
+}
+
+func TestLock_HeartbeatLost(t *testing.T) {
+	fake := &conntest.Fake{}
+	lk := &Lock{locker: NewLocker(fake.Conn(), NewParams{}), Name: "reports", Owner: "worker-1", ttl: time.Minute}
+
+	select {
+	case <-lk.Heartbeat(time.Millisecond):
+	case <-time.After(time.Second):
+		t.Fatal("lock not lost")
+	}
+}
+
+func TestLock_HeartbeatDefaultInterval(t *testing.T) {
+	fake := &conntest.Fake{}
+	lk := &Lock{locker: NewLocker(fake.Conn(), NewParams{}), Name: "reports", Owner: "worker-1", ttl: 2}
+
+	select {
+	case <-lk.Heartbeat(0):
+	case <-time.After(time.Second):
+		t.Fatal("lock not lost")
+	}
+}
+
+func TestLock_HeartbeatReleased(t *testing.T) {
+	fake := &conntest.Fake{Matched: 1}
+	lk := &Lock{locker: NewLocker(fake.Conn(), NewParams{}), Name: "reports", Owner: "worker-1", ttl: time.Minute}
+
+	lost := lk.Heartbeat(time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	assert.Nil(t, lk.Release())
+
+	select {
+	case <-lost:
+		t.Fatal("released lock reported as lost")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
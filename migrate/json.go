@@ -0,0 +1,112 @@
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mbretter/go-mongodb/v2"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// jsonFilePattern matches migration files like 20261018120000_create_users.json
+var jsonFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.json$`)
+
+// jsonMigration is the file format of JSON migrations, the up and down lists contain database commands
+// in MongoDB extended JSON, which are executed in order.
+//
+//	{
+//	  "description": "create users index",
+//	  "up": [
+//	    {"createIndexes": "Users", "indexes": [{"key": {"username": 1}, "name": "username_1"}]}
+//	  ],
+//	  "down": [
+//	    {"dropIndexes": "Users", "index": "username_1"}
+//	  ]
+//	}
+type jsonMigration struct {
+	Description string   `bson:"description"`
+	Up          []bson.D `bson:"up"`
+	Down        []bson.D `bson:"down"`
+}
+
+// LoadJsonMigrations reads all JSON migrations from dir, the version is taken from the file name,
+// which has the format <version>_<name>.json. Other files are ignored.
+func LoadJsonMigrations(dir string) ([]Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		matches := jsonFilePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		mig, err := ParseJsonMigration(version, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+
+		if len(mig.Description) == 0 {
+			mig.Description = strings.ReplaceAll(matches[2], "_", " ")
+		}
+
+		migrations = append(migrations, mig)
+	}
+
+	return migrations, nil
+}
+
+// ParseJsonMigration creates a migration from its JSON representation, the commands are executed by using RunCommand.
+// If the down list is missing, the migration can not be reverted.
+func ParseJsonMigration(version int64, data []byte) (Migration, error) {
+	var jm jsonMigration
+	if err := bson.UnmarshalExtJSON(data, false, &jm); err != nil {
+		return Migration{}, err
+	}
+
+	mig := Migration{
+		Version:     version,
+		Description: jm.Description,
+		Up:          runCommands(jm.Up),
+	}
+
+	if jm.Down != nil {
+		mig.Down = runCommands(jm.Down)
+	}
+
+	return mig, nil
+}
+
+// runCommands returns a migration function executing the commands in order.
+func runCommands(cmds []bson.D) func(conn mongodb.Connector) error {
+	return func(conn mongodb.Connector) error {
+		for i, cmd := range cmds {
+			if err := conn.RunCommand(cmd).Err(); err != nil {
+				return fmt.Errorf("command %d: %w", i, err)
+			}
+		}
+
+		return nil
+	}
+}
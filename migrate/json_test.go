@@ -0,0 +1,68 @@
+package migrate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJsonMigration(t *testing.T) {
+	mig, err := ParseJsonMigration(7, []byte(`{
+		"description": "users index",
+		"up": [{"createIndexes": "Users", "indexes": [{"key": {"username": 1}, "name": "username_1"}]}],
+		"down": [{"dropIndexes": "Users", "index": "username_1"}]
+	}`))
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(7), mig.Version)
+	assert.Equal(t, "users index", mig.Description)
+	assert.NotNil(t, mig.Up)
+	assert.NotNil(t, mig.Down)
+}
+
+func TestParseJsonMigration_NoDown(t *testing.T) {
+	mig, err := ParseJsonMigration(7, []byte(`{"up": []}`))
+
+	assert.Nil(t, err)
+	assert.NotNil(t, mig.Up)
+	assert.Nil(t, mig.Down)
+}
+
+func TestParseJsonMigration_Invalid(t *testing.T) {
+	_, err := ParseJsonMigration(7, []byte(`{"up": `))
+
+	assert.NotNil(t, err)
+}
+
+func TestLoadJsonMigrations(t *testing.T) {
+	dir := t.TempDir()
+
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "20260102030405_create_users.json"), []byte(`{"up": []}`), 0o644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "20260102030406_x.json"), []byte(`{"description": "desc", "up": []}`), 0o644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte(`readme`), 0o644))
+	assert.Nil(t, os.Mkdir(filepath.Join(dir, "1_sub.json"), 0o755))
+
+	migrations, err := LoadJsonMigrations(dir)
+
+	assert.Nil(t, err)
+	if assert.Len(t, migrations, 2) {
+		assert.Equal(t, int64(20260102030405), migrations[0].Version)
+		assert.Equal(t, "create users", migrations[0].Description)
+		assert.Equal(t, int64(20260102030406), migrations[1].Version)
+		assert.Equal(t, "desc", migrations[1].Description)
+	}
+}
+
+func TestLoadJsonMigrations_Invalid(t *testing.T) {
+	dir := t.TempDir()
+
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "1_broken.json"), []byte(`{`), 0o644))
+
+	_, err := LoadJsonMigrations(dir)
+	assert.NotNil(t, err)
+
+	_, err = LoadJsonMigrations(filepath.Join(dir, "missing"))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,532 @@
+// Package migrate provides a runner for versioned schema migrations built on the mongodb.Connector.
+//
+// Migrations are registered with a unique version and applied in ascending order, the applied versions are
+// tracked in a "Migrations" collection, similar to the "Sequences" collection used by GetNextSeq.
+// A lock document ensures, that only one instance is migrating at a time.
+package migrate
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mbretter/go-mongodb/v2"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var (
+	ErrLocked           = errors.New("migrations are locked by another instance")
+	ErrLockLost         = errors.New("migration lock has been taken over by another instance")
+	ErrInvalidVersion   = errors.New("migration version must be greater than zero")
+	ErrDuplicateVersion = errors.New("duplicate migration version")
+	ErrNoUp             = errors.New("migration has no up function")
+	ErrNoDown           = errors.New("migration has no down function")
+)
+
+// lockId is the _id of the lock document inside the migrations collection.
+const lockId = "lock"
+
+// Migration is a single versioned migration step, Up applies the migration, Down reverts it.
+// The connector passed to the functions has no collection set.
+type Migration struct {
+	Version     int64
+	Description string
+	Up          func(conn mongodb.Connector) error
+	Down        func(conn mongodb.Connector) error
+}
+
+// Status describes a registered or applied migration.
+type Status struct {
+	Version     int64
+	Description string
+	Applied     bool
+	AppliedAt   time.Time
+	// Missing is set, if the migration was applied, but is not registered anymore.
+	Missing bool
+}
+
+// NewParams holds the parameters of the Migrator.
+type NewParams struct {
+	// Collection where the applied versions are tracked, defaults to "Migrations".
+	Collection string
+	// Owner identifies the instance holding the lock, defaults to hostname and pid.
+	Owner string
+	// LockTtl is the duration after which a lock of a crashed instance is taken over, defaults to 10 minutes.
+	// The lock is renewed every third of LockTtl, but at most every 10ms, while migrating, a lost lock fails
+	// the migration.
+	LockTtl time.Duration
+	// DryRun reports the migrations, which would be applied or reverted, without executing them.
+	DryRun bool
+}
+
+// Migrator applies and reverts the registered migrations.
+type Migrator struct {
+	conn       mongodb.Connector
+	collection string
+	owner      string
+	lockTtl    time.Duration
+	dryRun     bool
+	migrations []Migration
+}
+
+// record is the document stored for each applied migration.
+type record struct {
+	Version     int64     `bson:"_id"`
+	Description string    `bson:"Description"`
+	AppliedAt   time.Time `bson:"AppliedAt"`
+}
+
+// NewMigrator creates a new Migrator using the given connector and parameters.
+func NewMigrator(conn mongodb.Connector, params NewParams) *Migrator {
+	m := Migrator{
+		conn:       conn,
+		collection: params.Collection,
+		owner:      params.Owner,
+		lockTtl:    params.LockTtl,
+		dryRun:     params.DryRun,
+	}
+
+	if len(m.collection) == 0 {
+		m.collection = "Migrations"
+	}
+
+	if len(m.owner) == 0 {
+		host, _ := os.Hostname()
+		m.owner = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+
+	if m.lockTtl <= 0 {
+		m.lockTtl = 10 * time.Minute
+	}
+
+	return &m
+}
+
+// Register adds migrations to the migrator, the migrations are kept ordered by version.
+// It returns an error if a version is invalid or has already been registered.
+func (m *Migrator) Register(migrations ...Migration) error {
+	for _, mig := range migrations {
+		if mig.Version <= 0 {
+			return fmt.Errorf("%w: %d", ErrInvalidVersion, mig.Version)
+		}
+
+		if mig.Up == nil {
+			return fmt.Errorf("%w: %d", ErrNoUp, mig.Version)
+		}
+
+		if _, found := m.find(mig.Version); found {
+			return fmt.Errorf("%w: %d", ErrDuplicateVersion, mig.Version)
+		}
+
+		m.migrations = append(m.migrations, mig)
+		slices.SortFunc(m.migrations, func(a, b Migration) int {
+			return cmp.Compare(a.Version, b.Version)
+		})
+	}
+
+	return nil
+}
+
+// Migrations returns the registered migrations ordered by version.
+func (m *Migrator) Migrations() []Migration {
+	return slices.Clone(m.migrations)
+}
+
+// Up applies all pending migrations in ascending order and returns the applied migrations.
+// On dry-run, the pending migrations are returned without applying them.
+func (m *Migrator) Up() ([]Migration, error) {
+	return m.UpTo(0)
+}
+
+// UpTo applies the pending migrations up to and including the given version, a version of 0 applies all.
+func (m *Migrator) UpTo(version int64) (done []Migration, err error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	todo := pending(m.migrations, applied, version)
+	if m.dryRun || len(todo) == 0 {
+		return todo, nil
+	}
+
+	if err := m.lock(); err != nil {
+		return nil, err
+	}
+	keeper := m.keepLocked()
+	defer func() {
+		keeper.stop()
+		err = errors.Join(err, m.unlock())
+	}()
+
+	// re-read under lock, another instance might have migrated in the meantime
+	applied, err = m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, mig := range pending(m.migrations, applied, version) {
+		if err := keeper.err(); err != nil {
+			return done, err
+		}
+
+		if err := mig.Up(m.conn); err != nil {
+			return done, fmt.Errorf("migration %d up failed: %w", mig.Version, err)
+		}
+
+		if err := keeper.err(); err != nil {
+			return done, fmt.Errorf("migration %d up: %w", mig.Version, err)
+		}
+
+		_, err = m.conn.WithCollection(m.collection).InsertOne(record{
+			Version:     mig.Version,
+			Description: mig.Description,
+			AppliedAt:   time.Now().UTC(),
+		})
+		if err != nil {
+			return done, err
+		}
+
+		done = append(done, mig)
+	}
+
+	return done, nil
+}
+
+// Down reverts the last n applied migrations in descending order and returns the reverted migrations.
+// On dry-run, the migrations to be reverted are returned without reverting them.
+func (m *Migrator) Down(n int) (done []Migration, err error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	todo, err := revertible(m.migrations, applied, n)
+	if err != nil || m.dryRun || len(todo) == 0 {
+		return todo, err
+	}
+
+	if err := m.lock(); err != nil {
+		return nil, err
+	}
+	keeper := m.keepLocked()
+	defer func() {
+		keeper.stop()
+		err = errors.Join(err, m.unlock())
+	}()
+
+	applied, err = m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	todo, err = revertible(m.migrations, applied, n)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, mig := range todo {
+		if err := keeper.err(); err != nil {
+			return done, err
+		}
+
+		if err := mig.Down(m.conn); err != nil {
+			return done, fmt.Errorf("migration %d down failed: %w", mig.Version, err)
+		}
+
+		if err := keeper.err(); err != nil {
+			return done, fmt.Errorf("migration %d down: %w", mig.Version, err)
+		}
+
+		_, err = m.conn.WithCollection(m.collection).DeleteOne(bson.D{{"_id", mig.Version}})
+		if err != nil {
+			return done, err
+		}
+
+		done = append(done, mig)
+	}
+
+	return done, nil
+}
+
+// Force sets the migration state to the given version without executing any migration,
+// the registered migrations up to version are marked as applied, all later ones as not applied.
+// It is used to repair the state after a failed migration has been fixed manually.
+func (m *Migrator) Force(version int64) (err error) {
+	if m.dryRun {
+		return nil
+	}
+
+	if err := m.lock(); err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, m.unlock())
+	}()
+
+	conn := m.conn.WithCollection(m.collection)
+
+	_, err = conn.DeleteMany(bson.D{{"_id", bson.D{{"$type", "number"}, {"$gt", version}}}})
+	if err != nil || version <= 0 {
+		return err
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+
+	for _, mig := range pending(m.migrations, applied, version) {
+		_, err = conn.InsertOne(record{
+			Version:     mig.Version,
+			Description: mig.Description,
+			AppliedAt:   time.Now().UTC(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Status lists all registered migrations together with the applied ones, ordered by version.
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	return status(m.migrations, applied), nil
+}
+
+// applied returns the applied migrations by version.
+func (m *Migrator) applied() (map[int64]record, error) {
+	conn := m.conn.WithCollection(m.collection)
+
+	cur, err := conn.Find(bson.D{{"_id", bson.D{{"$type", "number"}}}})
+	if err != nil {
+		return nil, err
+	}
+
+	var records []record
+	if err := conn.FetchAll(cur, &records); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]record, len(records))
+	for _, r := range records {
+		applied[r.Version] = r
+	}
+
+	return applied, nil
+}
+
+// lock acquires the migration lock, an expired lock of another owner is taken over.
+// ErrLocked is returned if another instance holds the lock.
+func (m *Migrator) lock() error {
+	now := time.Now().UTC()
+
+	res := m.conn.WithCollection(m.collection).FindOneAndUpdate(
+		bson.D{{"_id", lockId}, {"$or", bson.A{
+			bson.D{{"Owner", m.owner}},
+			bson.D{{"ExpiresAt", bson.D{{"$lt", now}}}},
+		}}},
+		bson.D{{"$set", bson.D{{"Owner", m.owner}, {"ExpiresAt", now.Add(m.lockTtl)}}}},
+		options.FindOneAndUpdate().SetUpsert(true))
+
+	err := res.Err()
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// no previous lock document, the upsert succeeded
+		return nil
+	}
+
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrLocked
+	}
+
+	return err
+}
+
+// renew extends the migration lock held by the owner, ErrLockLost is returned if another instance has taken it over.
+func (m *Migrator) renew(conn mongodb.Connector) error {
+	res, err := conn.UpdateOne(
+		bson.D{{"_id", lockId}, {"Owner", m.owner}},
+		bson.D{{"$set", bson.D{{"ExpiresAt", time.Now().UTC().Add(m.lockTtl)}}}})
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return ErrLockLost
+	}
+
+	return nil
+}
+
+// lockKeeper renews the migration lock in the background, while migrations are running.
+type lockKeeper struct {
+	done chan struct{}
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	lost error
+}
+
+// minRenewInterval bounds the renewals of the lock for tiny lock ttls.
+const minRenewInterval = 10 * time.Millisecond
+
+// renewInterval returns the interval the lock is renewed at, a third of the lock ttl.
+func (m *Migrator) renewInterval() time.Duration {
+	return max(m.lockTtl/3, minRenewInterval)
+}
+
+// keepLocked starts renewing the lock every renewInterval, until stop is called.
+// Renewing stops at the first failure, which is returned by err, the migrations must not continue then,
+// because another instance might take over the lock.
+func (m *Migrator) keepLocked() *lockKeeper {
+	k := &lockKeeper{done: make(chan struct{})}
+	conn := m.conn.WithCollection(m.collection)
+
+	k.wg.Add(1)
+	go func() {
+		defer k.wg.Done()
+
+		ticker := time.NewTicker(m.renewInterval())
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-k.done:
+				return
+			case <-ticker.C:
+				if err := m.renew(conn); err != nil {
+					k.mu.Lock()
+					k.lost = err
+					k.mu.Unlock()
+					return
+				}
+			}
+		}
+	}()
+
+	return k
+}
+
+// err returns the error of a failed renewal.
+func (k *lockKeeper) err() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.lost
+}
+
+// stop stops renewing the lock.
+func (k *lockKeeper) stop() {
+	close(k.done)
+	k.wg.Wait()
+}
+
+// unlock releases the migration lock held by the owner.
+func (m *Migrator) unlock() error {
+	_, err := m.conn.WithCollection(m.collection).DeleteOne(bson.D{{"_id", lockId}, {"Owner", m.owner}})
+	return err
+}
+
+// find returns the registered migration with the given version.
+func (m *Migrator) find(version int64) (Migration, bool) {
+	idx := slices.IndexFunc(m.migrations, func(mig Migration) bool {
+		return mig.Version == version
+	})
+	if idx < 0 {
+		return Migration{}, false
+	}
+
+	return m.migrations[idx], true
+}
+
+// pending returns the not applied migrations up to version in ascending order, a version of 0 means all.
+func pending(migrations []Migration, applied map[int64]record, version int64) []Migration {
+	var todo []Migration
+	for _, mig := range migrations {
+		if version > 0 && mig.Version > version {
+			break
+		}
+
+		if _, ok := applied[mig.Version]; !ok {
+			todo = append(todo, mig)
+		}
+	}
+
+	return todo
+}
+
+// revertible returns the last n applied migrations in descending order.
+// It fails if an applied migration is not registered or has no down function.
+func revertible(migrations []Migration, applied map[int64]record, n int) ([]Migration, error) {
+	versions := make([]int64, 0, len(applied))
+	for v := range applied {
+		versions = append(versions, v)
+	}
+	slices.SortFunc(versions, func(a, b int64) int {
+		return cmp.Compare(b, a)
+	})
+
+	if n < len(versions) {
+		versions = versions[:max(n, 0)]
+	}
+
+	todo := make([]Migration, 0, len(versions))
+	for _, v := range versions {
+		idx := slices.IndexFunc(migrations, func(mig Migration) bool {
+			return mig.Version == v
+		})
+		if idx < 0 {
+			return nil, fmt.Errorf("applied migration %d is not registered", v)
+		}
+
+		if migrations[idx].Down == nil {
+			return nil, fmt.Errorf("%w: %d", ErrNoDown, v)
+		}
+
+		todo = append(todo, migrations[idx])
+	}
+
+	return todo, nil
+}
+
+// status merges the registered and the applied migrations.
+func status(migrations []Migration, applied map[int64]record) []Status {
+	list := make([]Status, 0, len(migrations))
+	for _, mig := range migrations {
+		s := Status{Version: mig.Version, Description: mig.Description}
+		if r, ok := applied[mig.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = r.AppliedAt
+		}
+		list = append(list, s)
+	}
+
+	for v, r := range applied {
+		if slices.ContainsFunc(migrations, func(mig Migration) bool { return mig.Version == v }) {
+			continue
+		}
+		list = append(list, Status{
+			Version:     v,
+			Description: r.Description,
+			Applied:     true,
+			AppliedAt:   r.AppliedAt,
+			Missing:     true,
+		})
+	}
+
+	slices.SortFunc(list, func(a, b Status) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+
+	return list
+}
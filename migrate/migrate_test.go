@@ -0,0 +1,383 @@
+package migrate
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mbretter/go-mongodb/v2"
+	"github.com/mbretter/go-mongodb/v2/internal/conntest"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// migrationStore keeps the migration records and the lock of the fake connector in memory.
+type migrationStore struct {
+	mu         sync.Mutex
+	collection string
+	records    []record
+	lockOwner  string
+	renewals   int
+}
+
+// conn returns a fake connector backed by the store.
+func (s *migrationStore) conn() mongodb.Connector {
+	fake := &conntest.Fake{
+		OnFind:             s.find,
+		OnFindOneAndUpdate: s.acquire,
+		OnUpdateOne:        s.renew,
+		OnInsertOne:        s.insert,
+		OnDeleteOne:        s.delete,
+		OnDeleteMany:       s.deleteMany,
+	}
+
+	return fake.Conn()
+}
+
+func (s *migrationStore) find(call conntest.Call) ([]interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.collection = call.Collection
+	docs := make([]interface{}, len(s.records))
+	for i, r := range s.records {
+		docs[i] = r
+	}
+	return docs, nil
+}
+
+// acquire acquires the lock, like the upsert fails with a duplicate key, if the lock is held by another owner.
+func (s *migrationStore) acquire(call conntest.Call) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	owner := call.Filter[1].Value.(bson.A)[0].(bson.D)[0].Value.(string)
+	if len(s.lockOwner) > 0 && s.lockOwner != owner {
+		return nil, mongo.CommandError{Code: 11000}
+	}
+
+	s.lockOwner = owner
+	return nil, mongo.ErrNoDocuments
+}
+
+// renew renews the lock of the owner.
+func (s *migrationStore) renew(call conntest.Call) (*mongo.UpdateResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.lockOwner != call.Filter[1].Value {
+		return &mongo.UpdateResult{}, nil
+	}
+	s.renewals++
+	return &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil
+}
+
+func (s *migrationStore) insert(call conntest.Call) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, call.Document.(record))
+	return nil
+}
+
+// delete releases the lock of the owner or deletes a migration record.
+func (s *migrationStore) delete(call conntest.Call) (*mongo.DeleteResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f := call.Filter
+	if f[0].Value == lockId {
+		if s.lockOwner == f[1].Value {
+			s.lockOwner = ""
+		}
+		return &mongo.DeleteResult{}, nil
+	}
+
+	s.records = slices.DeleteFunc(s.records, func(r record) bool {
+		return r.Version == f[0].Value
+	})
+	return &mongo.DeleteResult{DeletedCount: 1}, nil
+}
+
+func (s *migrationStore) deleteMany(call conntest.Call) (*mongo.DeleteResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	gt := call.Filter[0].Value.(bson.D)[1].Value.(int64)
+	s.records = slices.DeleteFunc(s.records, func(r record) bool {
+		return r.Version > gt
+	})
+	return &mongo.DeleteResult{}, nil
+}
+
+func (s *migrationStore) setLockOwner(owner string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lockOwner = owner
+}
+
+func noop(mongodb.Connector) error {
+	return nil
+}
+
+func versions(migrations []Migration) []int64 {
+	v := make([]int64, len(migrations))
+	for i, m := range migrations {
+		v[i] = m.Version
+	}
+	return v
+}
+
+func TestNewMigrator_Defaults(t *testing.T) {
+	m := NewMigrator(nil, NewParams{})
+
+	assert.Equal(t, "Migrations", m.collection)
+	assert.Equal(t, 10*time.Minute, m.lockTtl)
+	assert.NotEmpty(t, m.owner)
+	assert.False(t, m.dryRun)
+
+	m = NewMigrator(nil, NewParams{Collection: "Schema", Owner: "me", LockTtl: time.Minute, DryRun: true})
+
+	assert.Equal(t, "Schema", m.collection)
+	assert.Equal(t, time.Minute, m.lockTtl)
+	assert.Equal(t, "me", m.owner)
+	assert.True(t, m.dryRun)
+}
+
+func TestMigrator_RenewInterval(t *testing.T) {
+	assert.Equal(t, 20*time.Second, NewMigrator(nil, NewParams{LockTtl: time.Minute}).renewInterval())
+	assert.Equal(t, 10*time.Millisecond, NewMigrator(nil, NewParams{LockTtl: 2}).renewInterval())
+}
+
+func TestMigrator_Register(t *testing.T) {
+	m := NewMigrator(nil, NewParams{})
+
+	err := m.Register(
+		Migration{Version: 3, Up: noop},
+		Migration{Version: 1, Up: noop},
+		Migration{Version: 2, Up: noop},
+	)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, versions(m.Migrations()))
+}
+
+func TestMigrator_RegisterInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		mig  Migration
+		err  error
+	}{
+		{"zero version", Migration{Version: 0, Up: noop}, ErrInvalidVersion},
+		{"negative version", Migration{Version: -1, Up: noop}, ErrInvalidVersion},
+		{"no up", Migration{Version: 5}, ErrNoUp},
+		{"duplicate", Migration{Version: 1, Up: noop}, ErrDuplicateVersion},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := NewMigrator(nil, NewParams{})
+			assert.Nil(t, m.Register(Migration{Version: 1, Up: noop}))
+
+			err := m.Register(test.mig)
+			assert.True(t, errors.Is(err, test.err))
+			assert.Len(t, m.Migrations(), 1)
+		})
+	}
+}
+
+func TestPending(t *testing.T) {
+	migrations := []Migration{{Version: 1}, {Version: 2}, {Version: 3}, {Version: 4}}
+	applied := map[int64]record{1: {Version: 1}, 3: {Version: 3}}
+
+	assert.Equal(t, []int64{2, 4}, versions(pending(migrations, applied, 0)))
+	assert.Equal(t, []int64{2}, versions(pending(migrations, applied, 3)))
+	assert.Empty(t, pending(migrations, applied, 1))
+}
+
+func TestRevertible(t *testing.T) {
+	migrations := []Migration{{Version: 1, Down: noop}, {Version: 2, Down: noop}, {Version: 3, Down: noop}}
+	applied := map[int64]record{1: {Version: 1}, 2: {Version: 2}, 3: {Version: 3}}
+
+	todo, err := revertible(migrations, applied, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{3, 2}, versions(todo))
+
+	todo, err = revertible(migrations, applied, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{3, 2, 1}, versions(todo))
+
+	todo, err = revertible(migrations, applied, 0)
+	assert.Nil(t, err)
+	assert.Empty(t, todo)
+}
+
+func TestRevertible_Errors(t *testing.T) {
+	migrations := []Migration{{Version: 1, Down: noop}, {Version: 2}}
+
+	_, err := revertible(migrations, map[int64]record{2: {Version: 2}}, 1)
+	assert.True(t, errors.Is(err, ErrNoDown))
+
+	_, err = revertible(migrations, map[int64]record{5: {Version: 5}}, 1)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "applied migration 5 is not registered", err.Error())
+	}
+}
+
+func TestStatus(t *testing.T) {
+	appliedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	migrations := []Migration{{Version: 1, Description: "one"}, {Version: 3, Description: "three"}}
+	applied := map[int64]record{
+		1: {Version: 1, Description: "one", AppliedAt: appliedAt},
+		2: {Version: 2, Description: "two", AppliedAt: appliedAt},
+	}
+
+	assert.Equal(t, []Status{
+		{Version: 1, Description: "one", Applied: true, AppliedAt: appliedAt},
+		{Version: 2, Description: "two", Applied: true, AppliedAt: appliedAt, Missing: true},
+		{Version: 3, Description: "three"},
+	}, status(migrations, applied))
+}
+
+// recorder returns a migration function recording its version into calls.
+func recorder(calls *[]int64, version int64) func(mongodb.Connector) error {
+	return func(mongodb.Connector) error {
+		*calls = append(*calls, version)
+		return nil
+	}
+}
+
+func newTestMigrator(store *migrationStore, params NewParams) (*Migrator, *[]int64) {
+	var calls []int64
+
+	params.Owner = "me"
+	m := NewMigrator(store.conn(), params)
+	for _, v := range []int64{1, 2, 3} {
+		_ = m.Register(Migration{Version: v, Description: "test", Up: recorder(&calls, v), Down: recorder(&calls, -v)})
+	}
+
+	return m, &calls
+}
+
+func TestMigrator_Up(t *testing.T) {
+	store := migrationStore{records: []record{{Version: 1}}}
+	m, calls := newTestMigrator(&store, NewParams{})
+
+	done, err := m.Up()
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{2, 3}, versions(done))
+	assert.Equal(t, []int64{2, 3}, *calls)
+	assert.Equal(t, "Migrations", store.collection)
+	assert.Len(t, store.records, 3)
+	assert.Empty(t, store.lockOwner)
+
+	done, err = m.Up()
+	assert.Nil(t, err)
+	assert.Empty(t, done)
+}
+
+func TestMigrator_UpTo(t *testing.T) {
+	store := migrationStore{}
+	m, calls := newTestMigrator(&store, NewParams{})
+
+	done, err := m.UpTo(2)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 2}, versions(done))
+	assert.Equal(t, []int64{1, 2}, *calls)
+}
+
+func TestMigrator_UpDryRun(t *testing.T) {
+	store := migrationStore{}
+	m, calls := newTestMigrator(&store, NewParams{DryRun: true})
+
+	done, err := m.Up()
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, versions(done))
+	assert.Empty(t, *calls)
+	assert.Empty(t, store.records)
+}
+
+func TestMigrator_UpFailed(t *testing.T) {
+	store := migrationStore{}
+	m, calls := newTestMigrator(&store, NewParams{})
+	m.migrations[1].Up = func(mongodb.Connector) error {
+		return errors.New("boom")
+	}
+
+	done, err := m.Up()
+	assert.EqualError(t, err, "migration 2 up failed: boom")
+	assert.Equal(t, []int64{1}, versions(done))
+	assert.Equal(t, []int64{1}, *calls)
+	assert.Len(t, store.records, 1)
+	assert.Empty(t, store.lockOwner)
+}
+
+func TestMigrator_Locked(t *testing.T) {
+	store := migrationStore{lockOwner: "other"}
+	m, calls := newTestMigrator(&store, NewParams{})
+
+	_, err := m.Up()
+	assert.ErrorIs(t, err, ErrLocked)
+
+	assert.ErrorIs(t, m.Force(1), ErrLocked)
+	assert.Empty(t, *calls)
+	assert.Equal(t, "other", store.lockOwner)
+}
+
+func TestMigrator_LockRenewed(t *testing.T) {
+	store := migrationStore{}
+	m, _ := newTestMigrator(&store, NewParams{LockTtl: 30 * time.Millisecond})
+	m.migrations[0].Up = func(mongodb.Connector) error {
+		time.Sleep(60 * time.Millisecond)
+		return nil
+	}
+
+	done, err := m.Up()
+	assert.Nil(t, err)
+	assert.Len(t, done, 3)
+	assert.Greater(t, store.renewals, 0)
+}
+
+func TestMigrator_LockLost(t *testing.T) {
+	store := migrationStore{}
+	m, calls := newTestMigrator(&store, NewParams{LockTtl: 30 * time.Millisecond})
+	m.migrations[0].Up = func(mongodb.Connector) error {
+		// another instance takes over the lock
+		store.setLockOwner("other")
+		time.Sleep(60 * time.Millisecond)
+		return nil
+	}
+
+	done, err := m.Up()
+	assert.ErrorIs(t, err, ErrLockLost)
+	assert.Empty(t, done)
+	assert.Empty(t, *calls)
+	assert.Empty(t, store.records)
+	assert.Equal(t, "other", store.lockOwner)
+}
+
+func TestMigrator_Down(t *testing.T) {
+	store := migrationStore{records: []record{{Version: 1}, {Version: 2}, {Version: 3}}}
+	m, calls := newTestMigrator(&store, NewParams{})
+
+	done, err := m.Down(2)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{3, 2}, versions(done))
+	assert.Equal(t, []int64{-3, -2}, *calls)
+	assert.Equal(t, []record{{Version: 1}}, store.records)
+	assert.Empty(t, store.lockOwner)
+}
+
+func TestMigrator_Force(t *testing.T) {
+	store := migrationStore{records: []record{{Version: 3}}}
+	m, calls := newTestMigrator(&store, NewParams{})
+
+	assert.Nil(t, m.Force(2))
+	assert.Len(t, store.records, 2)
+	assert.Equal(t, int64(1), store.records[0].Version)
+	assert.Equal(t, int64(2), store.records[1].Version)
+	assert.Empty(t, *calls)
+	assert.Empty(t, store.lockOwner)
+}
@@ -0,0 +1,91 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// scaffold formats
+const (
+	FormatGo   = "go"
+	FormatJson = "json"
+)
+
+var ErrUnknownFormat = errors.New("unknown migration format")
+
+var nonAlnum = regexp.MustCompile(`[^a-z0-9]+`)
+
+const goTemplate = `package migrations
+
+import (
+	"github.com/mbretter/go-mongodb/v2"
+	"github.com/mbretter/go-mongodb/v2/migrate"
+)
+
+// Migration%[1]d %[2]s
+var Migration%[1]d = migrate.Migration{
+	Version:     %[1]d,
+	Description: %[2]q,
+	Up: func(conn mongodb.Connector) error {
+		return nil
+	},
+	Down: func(conn mongodb.Connector) error {
+		return nil
+	},
+}
+`
+
+const jsonTemplate = `{
+  "description": %q,
+  "up": [],
+  "down": []
+}
+`
+
+// VersionFromTime returns a migration version of the format YYYYMMDDhhmmss.
+func VersionFromTime(t time.Time) int64 {
+	t = t.UTC()
+	return int64(t.Year())*10000000000 + int64(t.Month())*100000000 + int64(t.Day())*1000000 +
+		int64(t.Hour())*10000 + int64(t.Minute())*100 + int64(t.Second())
+}
+
+// Scaffold creates an empty migration file in dir and returns its path.
+// The format is either FormatGo or FormatJson, the version is derived from the given time.
+func Scaffold(dir string, name string, format string, now time.Time) (string, error) {
+	slug := strings.Trim(nonAlnum.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if len(slug) == 0 {
+		return "", errors.New("migration name must not be empty")
+	}
+
+	version := VersionFromTime(now)
+
+	var content string
+	switch format {
+	case FormatGo:
+		content = fmt.Sprintf(goTemplate, version, name)
+	case FormatJson:
+		content = fmt.Sprintf(jsonTemplate, name)
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%d_%s.%s", version, slug, format))
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = f.WriteString(content)
+
+	return path, errors.Join(err, f.Close())
+}
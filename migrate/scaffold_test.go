@@ -0,0 +1,61 @@
+package migrate
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var scaffoldTime = time.Date(2026, 10, 18, 9, 8, 7, 0, time.UTC)
+
+func TestVersionFromTime(t *testing.T) {
+	assert.Equal(t, int64(20261018090807), VersionFromTime(scaffoldTime))
+}
+
+func TestScaffold_Json(t *testing.T) {
+	dir := t.TempDir()
+
+	path, err := Scaffold(dir, "Create Users!", FormatJson, scaffoldTime)
+
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(dir, "20261018090807_create_users.json"), path)
+
+	data, _ := os.ReadFile(path)
+	mig, err := ParseJsonMigration(1, data)
+	assert.Nil(t, err)
+	assert.Equal(t, "Create Users!", mig.Description)
+}
+
+func TestScaffold_Go(t *testing.T) {
+	dir := t.TempDir()
+
+	path, err := Scaffold(dir, "create users", FormatGo, scaffoldTime)
+
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(dir, "20261018090807_create_users.go"), path)
+
+	data, _ := os.ReadFile(path)
+	assert.Contains(t, string(data), "var Migration20261018090807 = migrate.Migration{")
+	assert.Contains(t, string(data), `Description: "create users",`)
+}
+
+func TestScaffold_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := Scaffold(dir, "x", "yaml", scaffoldTime)
+	assert.True(t, errors.Is(err, ErrUnknownFormat))
+
+	_, err = Scaffold(dir, "!!", FormatJson, scaffoldTime)
+	assert.NotNil(t, err)
+
+	_, err = Scaffold(dir, "x", FormatJson, scaffoldTime)
+	assert.Nil(t, err)
+
+	// already exists
+	_, err = Scaffold(dir, "x", FormatJson, scaffoldTime)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,111 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mbretter/go-mongodb/v2"
+	"github.com/mbretter/go-mongodb/v2/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ConvertLegacyUuids rewrites the UUIDs of field in all documents of the collection from the legacy binary subtype 3,
+// written using the representation r, to the standard subtype 4. The field may be a dotted path into embedded
+// documents, arrays are not traversed. It returns the number of converted documents, running it again is a no-op.
+func ConvertLegacyUuids(conn mongodb.Connector, collection string, field string, r types.UuidRepresentation) (int64, error) {
+	return convertUuids(conn, collection, field, bson.TypeBinaryUUIDOld, func(data []byte) ([]byte, error) {
+		u, err := types.UuidFromLegacyBytes(data, r)
+		if err != nil {
+			return nil, err
+		}
+		return u.LegacyBytes(types.UuidStandard)
+	})
+}
+
+// RevertLegacyUuids rewrites the UUIDs of field from subtype 4 back to the legacy subtype 3 using the representation r.
+// It is not a true inverse of ConvertLegacyUuids: the origin of a UUID is not recorded, so every subtype 4 value is
+// reverted, including the ones that were stored with subtype 4 before the conversion or written afterward.
+func RevertLegacyUuids(conn mongodb.Connector, collection string, field string, r types.UuidRepresentation) (int64, error) {
+	return convertUuids(conn, collection, field, bson.TypeBinaryUUID, func(data []byte) ([]byte, error) {
+		u, err := types.UuidFromLegacyBytes(data, types.UuidStandard)
+		if err != nil {
+			return nil, err
+		}
+		return u.LegacyBytes(r)
+	})
+}
+
+// LegacyUuidMigration returns a migration converting the UUIDs of field in the collection from the legacy subtype 3
+// to subtype 4 using ConvertLegacyUuids, Down reverts them using RevertLegacyUuids. Down reverts every subtype 4 value
+// of the field, not only the converted ones, so it is meant for collections where all UUIDs of the field were stored
+// with subtype 3 before the migration and no application writes subtype 4 until it is reverted.
+func LegacyUuidMigration(version int64, collection string, field string, r types.UuidRepresentation) Migration {
+	return Migration{
+		Version:     version,
+		Description: fmt.Sprintf("convert legacy UUIDs of %s.%s", collection, field),
+		Up: func(conn mongodb.Connector) error {
+			_, err := ConvertLegacyUuids(conn, collection, field, r)
+			return err
+		},
+		Down: func(conn mongodb.Connector) error {
+			_, err := RevertLegacyUuids(conn, collection, field, r)
+			return err
+		},
+	}
+}
+
+// convertUuids replaces the binaries of field having the given subtype with the converted bytes,
+// using the opposite subtype. Each document is updated only if the field has not been changed meanwhile.
+func convertUuids(conn mongodb.Connector, collection string, field string, subtype byte, convert func([]byte) ([]byte, error)) (int64, error) {
+	conn = conn.WithCollection(collection)
+
+	target := bson.TypeBinaryUUID
+	if subtype == bson.TypeBinaryUUID {
+		target = bson.TypeBinaryUUIDOld
+	}
+
+	cur, err := conn.Find(bson.D{{field, bson.D{{"$type", "binData"}}}},
+		options.Find().SetProjection(bson.D{{field, 1}}))
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = cur.Close(context.Background()) }()
+
+	path := strings.Split(field, ".")
+
+	var count int64
+	for conn.Next(cur) {
+		var doc bson.Raw
+		if err := conn.Decode(cur, &doc); err != nil {
+			return count, err
+		}
+
+		val, err := doc.LookupErr(path...)
+		if err != nil || val.Type != bson.TypeBinary {
+			continue
+		}
+
+		sub, data := val.Binary()
+		if sub != subtype {
+			continue
+		}
+
+		converted, err := convert(data)
+		if err != nil {
+			return count, fmt.Errorf("document %s: %w", doc.Lookup("_id"), err)
+		}
+
+		res, err := conn.UpdateOne(
+			bson.D{{"_id", doc.Lookup("_id")}, {field, bson.Binary{Subtype: subtype, Data: data}}},
+			bson.D{{"$set", bson.D{{field, bson.Binary{Subtype: target, Data: converted}}}}})
+		if err != nil {
+			return count, err
+		}
+
+		count += res.ModifiedCount
+	}
+
+	return count, cur.Err()
+}
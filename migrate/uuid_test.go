@@ -0,0 +1,102 @@
+package migrate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mbretter/go-mongodb/v2"
+	"github.com/mbretter/go-mongodb/v2/types"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// fakeConn implements the used methods of the connector, calling any other method panics.
+type fakeConn struct {
+	mongodb.Connector
+	collection string
+	docs       []interface{}
+	filters    []bson.D
+	updates    []bson.D
+}
+
+func (c *fakeConn) WithCollection(coll string, _ ...options.Lister[options.CollectionOptions]) mongodb.Connector {
+	c.collection = coll
+	return c
+}
+
+func (c *fakeConn) Find(filter interface{}, _ ...options.Lister[options.FindOptions]) (*mongo.Cursor, error) {
+	c.filters = append(c.filters, filter.(bson.D))
+	return mongo.NewCursorFromDocuments(c.docs, nil, nil)
+}
+
+func (c *fakeConn) Next(cur *mongo.Cursor) bool {
+	return cur.Next(context.Background())
+}
+
+func (c *fakeConn) Decode(cur *mongo.Cursor, val interface{}) error {
+	return cur.Decode(val)
+}
+
+func (c *fakeConn) UpdateOne(filter interface{}, update interface{}, _ ...options.Lister[options.UpdateOneOptions]) (*mongo.UpdateResult, error) {
+	c.updates = append(c.updates, filter.(bson.D), update.(bson.D))
+	return &mongo.UpdateResult{ModifiedCount: 1}, nil
+}
+
+const legacyUuid = types.UUID("00112233-4455-6677-8899-aabbccddeeff")
+
+func TestConvertLegacyUuids(t *testing.T) {
+	java, _ := legacyUuid.LegacyBytes(types.UuidJavaLegacy)
+	standard, _ := legacyUuid.LegacyBytes(types.UuidStandard)
+
+	conn := fakeConn{docs: []interface{}{
+		bson.D{{"_id", 1}, {"ref", bson.D{{"id", bson.Binary{Subtype: bson.TypeBinaryUUIDOld, Data: java}}}}},
+		bson.D{{"_id", 2}, {"ref", bson.D{{"id", bson.Binary{Subtype: bson.TypeBinaryUUID, Data: standard}}}}},
+		bson.D{{"_id", 3}, {"ref", bson.D{{"id", bson.Binary{Subtype: bson.TypeBinaryGeneric, Data: []byte{1}}}}}},
+	}}
+
+	n, err := ConvertLegacyUuids(&conn, "Users", "ref.id", types.UuidJavaLegacy)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Equal(t, int64(1), n)
+	assert.Equal(t, "Users", conn.collection)
+	assert.Equal(t, []bson.D{{{"ref.id", bson.D{{"$type", "binData"}}}}}, conn.filters)
+	if !assert.Len(t, conn.updates, 2) {
+		return
+	}
+
+	assert.Equal(t, bson.Binary{Subtype: bson.TypeBinaryUUIDOld, Data: java}, conn.updates[0][1].Value)
+	assert.Equal(t, bson.D{{"$set", bson.D{{"ref.id", bson.Binary{Subtype: bson.TypeBinaryUUID, Data: standard}}}}}, conn.updates[1])
+}
+
+func TestRevertLegacyUuids(t *testing.T) {
+	csharp, _ := legacyUuid.LegacyBytes(types.UuidCSharpLegacy)
+	standard, _ := legacyUuid.LegacyBytes(types.UuidStandard)
+
+	conn := fakeConn{docs: []interface{}{
+		bson.D{{"_id", 1}, {"uid", bson.Binary{Subtype: bson.TypeBinaryUUID, Data: standard}}},
+		bson.D{{"_id", 2}, {"uid", bson.Binary{Subtype: bson.TypeBinaryUUIDOld, Data: csharp}}},
+	}}
+
+	mig := LegacyUuidMigration(3, "Users", "uid", types.UuidCSharpLegacy)
+	assert.Equal(t, int64(3), mig.Version)
+
+	if !assert.Nil(t, mig.Down(&conn)) || !assert.Len(t, conn.updates, 2) {
+		return
+	}
+
+	assert.Equal(t, bson.D{{"$set", bson.D{{"uid", bson.Binary{Subtype: bson.TypeBinaryUUIDOld, Data: csharp}}}}}, conn.updates[1])
+}
+
+func TestConvertLegacyUuids_Invalid(t *testing.T) {
+	conn := fakeConn{docs: []interface{}{
+		bson.D{{"_id", 1}, {"uid", bson.Binary{Subtype: bson.TypeBinaryUUIDOld, Data: []byte{1, 2, 3}}}},
+	}}
+
+	_, err := ConvertLegacyUuids(&conn, "Users", "uid", types.UuidJavaLegacy)
+	assert.ErrorContains(t, err, "invalid UUID length")
+	assert.Empty(t, conn.updates)
+}
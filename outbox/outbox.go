@@ -0,0 +1,292 @@
+// Package outbox implements the transactional outbox pattern on top of the mongodb.Connector.
+//
+// Events are added to an "Outbox" collection inside the same transaction as the business write, so either both
+// are stored or none of them. A relay dispatches the stored events afterwards to a Publisher, dispatched events
+// are marked and removed later by a TTL index.
+//
+// Dispatching is at-least-once: if the relay crashes after publishing, but before the event has been marked as
+// dispatched, the event is published again, so the consumers must be idempotent. Events, which could not be
+// published, are retried with exponential backoff, after the maximum number of attempts they are moved into an
+// "OutboxDeadLetters" collection.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mbretter/go-mongodb/v2"
+	"github.com/mbretter/go-mongodb/v2/internal/delivery"
+	"github.com/mbretter/go-mongodb/v2/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Message is an event read from the outbox.
+type Message struct {
+	Id        types.ObjectId `bson:"_id"`
+	Topic     string         `bson:"Topic"`
+	Key       string         `bson:"Key"`
+	Payload   bson.RawValue  `bson:"Payload"`
+	CreatedAt time.Time      `bson:"CreatedAt"`
+	// Attempts counts the dispatch attempts including the current one.
+	Attempts  int    `bson:"Attempts"`
+	LastError string `bson:"LastError,omitempty"`
+}
+
+// deadEvent is the document stored in the dead-letter collection.
+type deadEvent struct {
+	Message  `bson:",inline"`
+	FailedAt time.Time `bson:"FailedAt"`
+}
+
+// Publisher delivers the messages of the outbox, e.g. to a message broker.
+// A message is marked as dispatched if Publish returns nil, otherwise it is retried after a backoff.
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) error
+}
+
+// PublisherFunc is an adapter to use an ordinary function as Publisher.
+type PublisherFunc func(ctx context.Context, msg Message) error
+
+// Publish calls f(ctx, msg).
+func (f PublisherFunc) Publish(ctx context.Context, msg Message) error {
+	return f(ctx, msg)
+}
+
+// entry is the document stored in the outbox collection.
+type entry struct {
+	Id           types.ObjectId `bson:"_id"`
+	Topic        string         `bson:"Topic"`
+	Key          string         `bson:"Key"`
+	Payload      interface{}    `bson:"Payload"`
+	CreatedAt    time.Time      `bson:"CreatedAt"`
+	Attempts     int            `bson:"Attempts"`
+	LockedUntil  *time.Time     `bson:"LockedUntil"`
+	DispatchedAt *time.Time     `bson:"DispatchedAt"`
+}
+
+// NewParams holds the parameters of the Outbox.
+type NewParams struct {
+	// Collection where the events are stored, defaults to "Outbox".
+	Collection string
+	// DeadLetterCollection where the events are moved after MaxAttempts, defaults to "OutboxDeadLetters".
+	DeadLetterCollection string
+	// PollInterval is the time the relay waits before looking for new events, defaults to 1 second.
+	PollInterval time.Duration
+	// LeaseTime is the duration a relay reserves an event for publishing, afterward the event is
+	// taken over by another relay, defaults to 30 seconds.
+	LeaseTime time.Duration
+	// MaxAttempts is the number of attempts before an event is dead-lettered, defaults to 10.
+	MaxAttempts int
+	// MinBackoff and MaxBackoff bound the exponential backoff of failed events, default to 1s and 1h.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// Retention is the time dispatched events are kept before the TTL index removes them, defaults to 7 days.
+	Retention time.Duration
+	// Watch wakes up the relay on inserted events by using a change stream, the polling is kept as fallback.
+	Watch bool
+	// OnError is called with the errors of the relay, which continues afterward.
+	OnError func(err error)
+}
+
+// Outbox writes events and relays them to a Publisher.
+type Outbox struct {
+	conn    mongodb.Connector
+	params  NewParams
+	backoff delivery.Backoff
+}
+
+// NewOutbox creates a new Outbox using the given connector and parameters.
+func NewOutbox(conn mongodb.Connector, params NewParams) *Outbox {
+	if len(params.Collection) == 0 {
+		params.Collection = "Outbox"
+	}
+
+	if len(params.DeadLetterCollection) == 0 {
+		params.DeadLetterCollection = "OutboxDeadLetters"
+	}
+
+	if params.PollInterval <= 0 {
+		params.PollInterval = time.Second
+	}
+
+	if params.LeaseTime <= 0 {
+		params.LeaseTime = 30 * time.Second
+	}
+
+	if params.MaxAttempts <= 0 {
+		params.MaxAttempts = 10
+	}
+
+	backoff := delivery.NewBackoff(params.MinBackoff, params.MaxBackoff)
+	params.MinBackoff, params.MaxBackoff = backoff.Min, backoff.Max
+
+	if params.Retention <= 0 {
+		params.Retention = 7 * 24 * time.Hour
+	}
+
+	return &Outbox{conn: conn, params: params, backoff: backoff}
+}
+
+// Add stores an event with the given topic, key and payload by using the connector tx, which is usually the
+// connector passed by mongodb.Connector.WithTransaction, so the event is committed together with the business write.
+func (o *Outbox) Add(tx mongodb.Connector, topic string, key string, payload interface{}) (types.ObjectId, error) {
+	e := entry{
+		Id:        types.NewObjectId(),
+		Topic:     topic,
+		Key:       key,
+		Payload:   payload,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	_, err := tx.WithCollection(o.params.Collection).InsertOne(e)
+	if err != nil {
+		return "", err
+	}
+
+	return e.Id, nil
+}
+
+// EnsureIndexes creates the TTL index removing the dispatched events and the index used by the relay.
+func (o *Outbox) EnsureIndexes() error {
+	conn := o.conn.WithCollection(o.params.Collection)
+
+	_, err := conn.CreateIndex(mongo.IndexModel{
+		Keys: bson.D{{"DispatchedAt", 1}},
+		Options: options.Index().SetName("DispatchedAt_ttl").
+			SetExpireAfterSeconds(int32(o.params.Retention.Seconds())),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.CreateIndex(mongo.IndexModel{Keys: bson.D{{"DispatchedAt", 1}, {"LockedUntil", 1}, {"_id", 1}}})
+
+	return err
+}
+
+// Dispatch publishes the pending events in the order they were added, until there are no events left.
+// An event, which could not be published, is retried after an exponential backoff, meanwhile the following events
+// are dispatched, after MaxAttempts it is moved into the dead-letter collection.
+// The number of published events is returned together with the errors of the failed events.
+func (o *Outbox) Dispatch(ctx context.Context, publisher Publisher) (n int, err error) {
+	conn := o.conn.WithContext(ctx).WithCollection(o.params.Collection)
+	// the outcome of a claimed event is stored, even if the relay is stopped meanwhile
+	bookkeeping := o.conn.WithContext(context.WithoutCancel(ctx)).WithCollection(o.params.Collection)
+
+	var errs []error
+	for ctx.Err() == nil {
+		msg, err := o.claim(conn)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+
+		if err != nil {
+			return n, errors.Join(append(errs, err)...)
+		}
+
+		if err := publisher.Publish(ctx, msg); err != nil {
+			errs = append(errs, fmt.Errorf("publishing %s failed: %w", msg.Id, err))
+
+			// the relay is stopped, the event is not to blame
+			if ctx.Err() != nil {
+				return n, errors.Join(append(errs, o.release(bookkeeping, msg))...)
+			}
+
+			if err := o.retry(bookkeeping, msg, err); err != nil {
+				return n, errors.Join(append(errs, err)...)
+			}
+
+			continue
+		}
+
+		_, err = bookkeeping.UpdateOne(
+			bson.D{{"_id", msg.Id}},
+			bson.D{{"$set", bson.D{{"DispatchedAt", time.Now().UTC()}, {"LockedUntil", nil}}}})
+		if err != nil {
+			return n, errors.Join(append(errs, err)...)
+		}
+
+		n++
+	}
+
+	return n, errors.Join(errs...)
+}
+
+// Run relays the events to the publisher until the context is done, errors are passed to OnError.
+func (o *Outbox) Run(ctx context.Context, publisher Publisher) {
+	wakeup := make(chan struct{}, 1)
+	if o.params.Watch {
+		// on fatal errors the relay falls back to polling
+		go delivery.Watch(ctx, o.conn.WithCollection(o.params.Collection), wakeup, o.onError)
+	}
+
+	for ctx.Err() == nil {
+		if _, err := o.Dispatch(ctx, publisher); err != nil && ctx.Err() == nil {
+			o.onError(err)
+		}
+
+		delivery.Wait(ctx, wakeup, o.params.PollInterval)
+	}
+}
+
+// claim reserves the oldest pending event, which is not reserved by another relay.
+func (o *Outbox) claim(conn mongodb.Connector) (msg Message, err error) {
+	now := time.Now().UTC()
+
+	err = conn.FindOneAndUpdate(
+		bson.D{{"DispatchedAt", nil}, {"$or", bson.A{
+			bson.D{{"LockedUntil", nil}},
+			bson.D{{"LockedUntil", bson.D{{"$lte", now}}}},
+		}}},
+		bson.D{{"$set", bson.D{{"LockedUntil", now.Add(o.params.LeaseTime)}}}, {"$inc", bson.D{{"Attempts", 1}}}},
+		options.FindOneAndUpdate().SetSort(bson.D{{"_id", 1}}).SetReturnDocument(options.After)).Decode(&msg)
+
+	return msg, err
+}
+
+// release removes the reservation of an event, so it is retried immediately.
+func (o *Outbox) release(conn mongodb.Connector, msg Message) error {
+	_, err := conn.UpdateOne(bson.D{{"_id", msg.Id}}, bson.D{{"$set", bson.D{{"LockedUntil", nil}}}})
+	return err
+}
+
+// retry reserves a failed event for the backoff, so it is retried afterward, or moves it into the dead-letter
+// collection if it reached the maximum number of attempts.
+func (o *Outbox) retry(conn mongodb.Connector, msg Message, reason error) error {
+	msg.LastError = reason.Error()
+
+	if msg.Attempts >= o.params.MaxAttempts {
+		return o.deadLetter(conn, msg)
+	}
+
+	_, err := conn.UpdateOne(
+		bson.D{{"_id", msg.Id}},
+		bson.D{{"$set", bson.D{
+			{"LockedUntil", time.Now().UTC().Add(o.backoff.Delay(msg.Attempts))},
+			{"LastError", msg.LastError},
+		}}})
+
+	return err
+}
+
+// deadLetter moves the event into the dead-letter collection.
+func (o *Outbox) deadLetter(conn mongodb.Connector, msg Message) error {
+	err := delivery.DeadLetter(conn, o.params.DeadLetterCollection, deadEvent{Message: msg, FailedAt: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.DeleteOne(bson.D{{"_id", msg.Id}})
+
+	return err
+}
+
+func (o *Outbox) onError(err error) {
+	if o.params.OnError != nil {
+		o.params.OnError(err)
+	}
+}
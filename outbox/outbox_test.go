@@ -0,0 +1,215 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mbretter/go-mongodb/v2/internal/conntest"
+	"github.com/mbretter/go-mongodb/v2/types"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func message(id string, topic string) Message {
+	payload, _ := bson.Marshal(bson.D{{"name", topic}})
+	return Message{
+		Id:      types.ObjectId(id),
+		Topic:   topic,
+		Payload: bson.RawValue{Type: bson.TypeEmbeddedDocument, Value: payload},
+	}
+}
+
+func TestNewOutbox_Defaults(t *testing.T) {
+	o := NewOutbox(nil, NewParams{})
+
+	assert.Equal(t, "Outbox", o.params.Collection)
+	assert.Equal(t, "OutboxDeadLetters", o.params.DeadLetterCollection)
+	assert.Equal(t, 10, o.params.MaxAttempts)
+	assert.Equal(t, time.Second, o.params.MinBackoff)
+	assert.Equal(t, time.Hour, o.params.MaxBackoff)
+	assert.Equal(t, time.Second, o.params.PollInterval)
+	assert.Equal(t, 30*time.Second, o.params.LeaseTime)
+	assert.Equal(t, 7*24*time.Hour, o.params.Retention)
+}
+
+func TestOutbox_Add(t *testing.T) {
+	fake := &conntest.Fake{}
+	o := NewOutbox(nil, NewParams{Collection: "Events"})
+
+	id, err := o.Add(fake.Conn(), "user.created", "u1", bson.M{"username": "foo"})
+
+	assert.Nil(t, err)
+	assert.False(t, id.IsZero())
+	if inserted := fake.Calls("InsertOne"); assert.Len(t, inserted, 1) {
+		assert.Equal(t, "Events", inserted[0].Collection)
+		e := inserted[0].Document.(entry)
+		assert.Equal(t, id, e.Id)
+		assert.Equal(t, "user.created", e.Topic)
+		assert.Equal(t, "u1", e.Key)
+		assert.Equal(t, bson.M{"username": "foo"}, e.Payload)
+		assert.Nil(t, e.DispatchedAt)
+	}
+}
+
+func TestOutbox_EnsureIndexes(t *testing.T) {
+	fake := &conntest.Fake{}
+	o := NewOutbox(fake.Conn(), NewParams{Retention: time.Hour})
+
+	assert.Nil(t, o.EnsureIndexes())
+	if indexes := fake.Calls("CreateIndex"); assert.Len(t, indexes, 2) {
+		model := indexes[0].Document.(mongo.IndexModel)
+		assert.Equal(t, bson.D{{"DispatchedAt", 1}}, model.Keys)
+
+		var opts options.IndexOptions
+		for _, set := range model.Options.List() {
+			_ = set(&opts)
+		}
+		assert.Equal(t, int32(3600), *opts.ExpireAfterSeconds)
+	}
+}
+
+func TestOutbox_Dispatch(t *testing.T) {
+	fake := &conntest.Fake{Docs: []interface{}{
+		message("66cc9ca8c042f7a732b7fc2a", "a"),
+		message("66cc9ca8c042f7a732b7fc2b", "b"),
+	}}
+	o := NewOutbox(fake.Conn(), NewParams{})
+
+	var topics []string
+	n, err := o.Dispatch(context.Background(), PublisherFunc(func(ctx context.Context, msg Message) error {
+		var payload bson.M
+		assert.Nil(t, msg.Payload.Unmarshal(&payload))
+		assert.Equal(t, msg.Topic, payload["name"])
+
+		topics = append(topics, msg.Topic)
+		return nil
+	}))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{"a", "b"}, topics)
+	if updates := fake.Calls("UpdateOne"); assert.Len(t, updates, 2) {
+		assert.Equal(t, bson.D{{"_id", types.ObjectId("66cc9ca8c042f7a732b7fc2a")}}, updates[0].Filter)
+		assert.Equal(t, "DispatchedAt", updates[0].Update[0].Value.(bson.D)[0].Key)
+	}
+}
+
+func TestOutbox_DispatchPublishFailed(t *testing.T) {
+	failing := message("66cc9ca8c042f7a732b7fc2a", "a")
+	failing.Attempts = 3
+
+	fake := &conntest.Fake{Docs: []interface{}{failing, message("66cc9ca8c042f7a732b7fc2b", "b")}}
+	o := NewOutbox(fake.Conn(), NewParams{})
+
+	publishErr := errors.New("broker down")
+	var topics []string
+	start := time.Now().UTC()
+	n, err := o.Dispatch(context.Background(), PublisherFunc(func(ctx context.Context, msg Message) error {
+		if msg.Topic == "a" {
+			return publishErr
+		}
+
+		topics = append(topics, msg.Topic)
+		return nil
+	}))
+
+	assert.True(t, errors.Is(err, publishErr))
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []string{"b"}, topics)
+	assert.Empty(t, fake.Docs)
+	if updates := fake.Calls("UpdateOne"); assert.Len(t, updates, 2) {
+		assert.Equal(t, bson.D{{"_id", types.ObjectId("66cc9ca8c042f7a732b7fc2a")}}, updates[0].Filter)
+
+		set := updates[0].Update[0].Value.(bson.D)
+		assert.Equal(t, "LockedUntil", set[0].Key)
+		assert.WithinDuration(t, start.Add(4*time.Second), set[0].Value.(time.Time), time.Second)
+		assert.Equal(t, bson.E{"LastError", "broker down"}, set[1])
+
+		assert.Equal(t, bson.D{{"_id", types.ObjectId("66cc9ca8c042f7a732b7fc2b")}}, updates[1].Filter)
+		assert.Equal(t, "DispatchedAt", updates[1].Update[0].Value.(bson.D)[0].Key)
+	}
+}
+
+func TestOutbox_DispatchDeadLetter(t *testing.T) {
+	failing := message("66cc9ca8c042f7a732b7fc2a", "a")
+	failing.Attempts = 2
+
+	fake := &conntest.Fake{Docs: []interface{}{failing}}
+	o := NewOutbox(fake.Conn(), NewParams{MaxAttempts: 2})
+
+	publishErr := errors.New("broker down")
+	n, err := o.Dispatch(context.Background(), PublisherFunc(func(ctx context.Context, msg Message) error {
+		return publishErr
+	}))
+
+	assert.True(t, errors.Is(err, publishErr))
+	assert.Equal(t, 0, n)
+	assert.Empty(t, fake.Calls("UpdateOne"))
+	if inserted := fake.Calls("InsertOne"); assert.Len(t, inserted, 1) {
+		assert.Equal(t, "OutboxDeadLetters", inserted[0].Collection)
+		dead := inserted[0].Document.(deadEvent)
+		assert.Equal(t, failing.Id, dead.Id)
+		assert.Equal(t, "broker down", dead.LastError)
+		assert.False(t, dead.FailedAt.IsZero())
+	}
+	if deleted := fake.Calls("DeleteOne"); assert.Len(t, deleted, 1) {
+		assert.Equal(t, "Outbox", deleted[0].Collection)
+		assert.Equal(t, bson.D{{"_id", failing.Id}}, deleted[0].Filter)
+	}
+}
+
+func TestOutbox_DispatchStopped(t *testing.T) {
+	fake := &conntest.Fake{Docs: []interface{}{
+		message("66cc9ca8c042f7a732b7fc2a", "a"),
+		message("66cc9ca8c042f7a732b7fc2b", "b"),
+	}}
+	o := NewOutbox(fake.Conn(), NewParams{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	n, err := o.Dispatch(ctx, PublisherFunc(func(ctx context.Context, msg Message) error {
+		cancel()
+		return nil
+	}))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+	if updates := fake.Calls("UpdateOne"); assert.Len(t, updates, 1) {
+		assert.Equal(t, "DispatchedAt", updates[0].Update[0].Value.(bson.D)[0].Key)
+	}
+}
+
+func TestOutbox_DispatchStoppedRelease(t *testing.T) {
+	fake := &conntest.Fake{Docs: []interface{}{message("66cc9ca8c042f7a732b7fc2a", "a")}}
+	o := NewOutbox(fake.Conn(), NewParams{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	n, err := o.Dispatch(ctx, PublisherFunc(func(ctx context.Context, msg Message) error {
+		cancel()
+		return ctx.Err()
+	}))
+
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, 0, n)
+	if updates := fake.Calls("UpdateOne"); assert.Len(t, updates, 1) {
+		assert.Equal(t, bson.D{{"_id", types.ObjectId("66cc9ca8c042f7a732b7fc2a")}}, updates[0].Filter)
+		assert.Equal(t, bson.D{{"$set", bson.D{{"LockedUntil", nil}}}}, updates[0].Update)
+	}
+}
+
+func TestOutbox_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	fake := &conntest.Fake{Docs: []interface{}{message("66cc9ca8c042f7a732b7fc2a", "a")}}
+	o := NewOutbox(fake.Conn(), NewParams{PollInterval: time.Millisecond})
+
+	o.Run(ctx, PublisherFunc(func(ctx context.Context, msg Message) error {
+		cancel()
+		return nil
+	}))
+
+	assert.Empty(t, fake.Docs)
+}
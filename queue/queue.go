@@ -0,0 +1,290 @@
+// Package queue provides a persistent job queue backed by a collection of the mongodb.Connector.
+//
+// Jobs are stored in a "Jobs" collection, workers claim them atomically by using FindOneAndUpdate, a claimed job
+// is invisible to other workers for the visibility timeout. If the worker neither acknowledges nor rejects the job
+// within the timeout, e.g. because it crashed, the job is claimed by another worker. Rejected jobs are retried with
+// exponential backoff, after the maximum number of attempts they are moved into a "DeadJobs" collection.
+package queue
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mbretter/go-mongodb/v2"
+	"github.com/mbretter/go-mongodb/v2/internal/delivery"
+	"github.com/mbretter/go-mongodb/v2/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var (
+	ErrDuplicate = errors.New("a job with the same dedupe key is already queued")
+	ErrLeaseLost = errors.New("job is not claimed by the worker anymore")
+)
+
+// claimSort is the order jobs are claimed in, the claim index has the same keys.
+var claimSort = bson.D{{"Priority", -1}, {"VisibleAt", 1}, {"_id", 1}}
+
+// Job is a claimed job.
+type Job struct {
+	Id        types.ObjectId `bson:"_id"`
+	Payload   bson.RawValue  `bson:"Payload"`
+	Priority  int            `bson:"Priority"`
+	DedupeKey string         `bson:"DedupeKey,omitempty"`
+	// Attempts counts the claims of the job including the current one.
+	Attempts  int            `bson:"Attempts"`
+	LastError string         `bson:"LastError,omitempty"`
+	CreatedAt time.Time      `bson:"CreatedAt"`
+	VisibleAt time.Time      `bson:"VisibleAt"`
+	Lease     types.ObjectId `bson:"Lease"`
+}
+
+// deadJob is the document stored in the dead-letter collection.
+type deadJob struct {
+	Job      `bson:",inline"`
+	FailedAt time.Time `bson:"FailedAt"`
+}
+
+// entry is the document stored for an enqueued job.
+type entry struct {
+	Id        types.ObjectId `bson:"_id"`
+	Payload   interface{}    `bson:"Payload"`
+	Priority  int            `bson:"Priority"`
+	DedupeKey string         `bson:"DedupeKey,omitempty"`
+	Attempts  int            `bson:"Attempts"`
+	CreatedAt time.Time      `bson:"CreatedAt"`
+	VisibleAt time.Time      `bson:"VisibleAt"`
+}
+
+// EnqueueOptions holds the options of an enqueued job.
+type EnqueueOptions struct {
+	// Priority of the job, jobs with a higher priority are claimed first.
+	Priority int
+	// Delay postpones the job.
+	Delay time.Duration
+	// DedupeKey prevents enqueuing a job, while another job with the same key is queued.
+	DedupeKey string
+}
+
+// NewParams holds the parameters of the Queue.
+type NewParams struct {
+	// Collection where the jobs are stored, defaults to "Jobs".
+	Collection string
+	// DeadLetterCollection where the jobs are moved after MaxAttempts, defaults to "DeadJobs".
+	DeadLetterCollection string
+	// VisibilityTimeout is the time a claimed job is hidden from other workers, defaults to 5 minutes.
+	VisibilityTimeout time.Duration
+	// MaxAttempts is the number of attempts before a job is dead-lettered, defaults to 5.
+	MaxAttempts int
+	// MinBackoff and MaxBackoff bound the exponential backoff of retried jobs, default to 1s and 1h.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// PollInterval is the time a worker waits before looking for new jobs, defaults to 1 second.
+	PollInterval time.Duration
+	// Watch wakes up the workers on enqueued jobs by using a change stream, the polling is kept as fallback.
+	Watch bool
+	// OnError is called with the errors of the workers, which continue afterward.
+	OnError func(err error)
+}
+
+// Queue enqueues and claims jobs.
+type Queue struct {
+	conn    mongodb.Connector
+	params  NewParams
+	backoff delivery.Backoff
+}
+
+// NewQueue creates a new Queue using the given connector and parameters.
+func NewQueue(conn mongodb.Connector, params NewParams) *Queue {
+	if len(params.Collection) == 0 {
+		params.Collection = "Jobs"
+	}
+
+	if len(params.DeadLetterCollection) == 0 {
+		params.DeadLetterCollection = "DeadJobs"
+	}
+
+	if params.VisibilityTimeout <= 0 {
+		params.VisibilityTimeout = 5 * time.Minute
+	}
+
+	if params.MaxAttempts <= 0 {
+		params.MaxAttempts = 5
+	}
+
+	backoff := delivery.NewBackoff(params.MinBackoff, params.MaxBackoff)
+	params.MinBackoff, params.MaxBackoff = backoff.Min, backoff.Max
+
+	if params.PollInterval <= 0 {
+		params.PollInterval = time.Second
+	}
+
+	return &Queue{conn: conn, params: params, backoff: backoff}
+}
+
+// EnsureIndexes creates the index used for claiming jobs and the unique index of the dedupe keys.
+func (q *Queue) EnsureIndexes() error {
+	conn := q.conn.WithCollection(q.params.Collection)
+
+	_, err := conn.CreateIndex(mongo.IndexModel{Keys: claimSort})
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.CreateIndex(mongo.IndexModel{
+		Keys: bson.D{{"DedupeKey", 1}},
+		Options: options.Index().SetName("DedupeKey_unique").SetUnique(true).
+			SetPartialFilterExpression(bson.D{{"DedupeKey", bson.D{{"$type", "string"}}}}),
+	})
+
+	return err
+}
+
+// Enqueue adds a job with the given payload, ErrDuplicate is returned if a job with the same dedupe key is queued.
+// The dedupe key requires the unique index created by EnsureIndexes.
+func (q *Queue) Enqueue(payload interface{}, opts EnqueueOptions) (types.ObjectId, error) {
+	now := time.Now().UTC()
+
+	e := entry{
+		Id:        types.NewObjectId(),
+		Payload:   payload,
+		Priority:  opts.Priority,
+		DedupeKey: opts.DedupeKey,
+		CreatedAt: now,
+		VisibleAt: now.Add(max(opts.Delay, 0)),
+	}
+
+	_, err := q.conn.WithCollection(q.params.Collection).InsertOne(e)
+	if mongo.IsDuplicateKeyError(err) {
+		return "", ErrDuplicate
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return e.Id, nil
+}
+
+// Claim claims the visible job with the highest priority, it returns nil if there is no job.
+// The job has to be acknowledged or rejected within the visibility timeout.
+func (q *Queue) Claim() (*Job, error) {
+	now := time.Now().UTC()
+
+	var job Job
+	err := q.conn.WithCollection(q.params.Collection).FindOneAndUpdate(
+		bson.D{{"VisibleAt", bson.D{{"$lte", now}}}},
+		bson.D{
+			{"$set", bson.D{{"VisibleAt", now.Add(q.params.VisibilityTimeout)}, {"Lease", types.NewObjectId()}}},
+			{"$inc", bson.D{{"Attempts", 1}}},
+		},
+		options.FindOneAndUpdate().
+			SetSort(claimSort).
+			SetReturnDocument(options.After)).Decode(&job)
+
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// Ack acknowledges a processed job by removing it from the queue.
+// ErrLeaseLost is returned if the visibility timeout expired and the job has been claimed by another worker.
+func (q *Queue) Ack(job *Job) error {
+	res, err := q.conn.WithCollection(q.params.Collection).DeleteOne(bson.D{{"_id", job.Id}, {"Lease", job.Lease}})
+	if err != nil {
+		return err
+	}
+
+	if res.DeletedCount == 0 {
+		return ErrLeaseLost
+	}
+
+	return nil
+}
+
+// Nack rejects a failed job, it is retried after an exponential backoff, or moved into the dead-letter collection
+// if it reached the maximum number of attempts.
+func (q *Queue) Nack(job *Job, reason error) error {
+	conn := q.conn.WithCollection(q.params.Collection)
+
+	if reason != nil {
+		job.LastError = reason.Error()
+	}
+
+	if job.Attempts >= q.params.MaxAttempts {
+		return q.deadLetter(job)
+	}
+
+	res, err := conn.UpdateOne(
+		bson.D{{"_id", job.Id}, {"Lease", job.Lease}},
+		bson.D{
+			{"$set", bson.D{{"VisibleAt", time.Now().UTC().Add(q.backoff.Delay(job.Attempts))}, {"LastError", job.LastError}}},
+			{"$unset", bson.D{{"Lease", ""}}},
+		})
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return ErrLeaseLost
+	}
+
+	return nil
+}
+
+// Work claims and processes jobs until the context is done, a job is acknowledged if the handler returns nil,
+// otherwise it is rejected. For processing jobs concurrently, start Work in multiple goroutines.
+func (q *Queue) Work(ctx context.Context, handler func(ctx context.Context, job *Job) error) {
+	wakeup := make(chan struct{}, 1)
+	if q.params.Watch {
+		// on fatal errors the worker falls back to polling
+		go delivery.Watch(ctx, q.conn.WithCollection(q.params.Collection), wakeup, q.onError)
+	}
+
+	for ctx.Err() == nil {
+		job, err := q.Claim()
+		if err != nil {
+			q.onError(err)
+		}
+
+		if job != nil {
+			if err := handler(ctx, job); err != nil {
+				err = q.Nack(job, err)
+			} else {
+				err = q.Ack(job)
+			}
+
+			if err != nil {
+				q.onError(err)
+			}
+
+			continue
+		}
+
+		delivery.Wait(ctx, wakeup, q.params.PollInterval)
+	}
+}
+
+// deadLetter moves the job into the dead-letter collection.
+func (q *Queue) deadLetter(job *Job) error {
+	err := delivery.DeadLetter(q.conn, q.params.DeadLetterCollection, deadJob{Job: *job, FailedAt: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+
+	return q.Ack(job)
+}
+
+func (q *Queue) onError(err error) {
+	if q.params.OnError != nil {
+		q.params.OnError(err)
+	}
+}
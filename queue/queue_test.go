@@ -0,0 +1,155 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mbretter/go-mongodb/v2/internal/conntest"
+	"github.com/mbretter/go-mongodb/v2/types"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func job(id string, attempts int) Job {
+	typ, payload, _ := bson.MarshalValue("payload")
+
+	return Job{
+		Id:       types.ObjectId(id),
+		Payload:  bson.RawValue{Type: bson.Type(typ), Value: payload},
+		Attempts: attempts,
+		Lease:    types.NewObjectId(),
+	}
+}
+
+func TestNewQueue_Defaults(t *testing.T) {
+	q := NewQueue(nil, NewParams{})
+
+	assert.Equal(t, "Jobs", q.params.Collection)
+	assert.Equal(t, "DeadJobs", q.params.DeadLetterCollection)
+	assert.Equal(t, 5*time.Minute, q.params.VisibilityTimeout)
+	assert.Equal(t, 5, q.params.MaxAttempts)
+	assert.Equal(t, time.Second, q.params.MinBackoff)
+	assert.Equal(t, time.Hour, q.params.MaxBackoff)
+	assert.Equal(t, time.Second, q.params.PollInterval)
+}
+
+func TestQueue_EnsureIndexes(t *testing.T) {
+	fake := &conntest.Fake{}
+	q := NewQueue(fake.Conn(), NewParams{})
+
+	assert.Nil(t, q.EnsureIndexes())
+	if indexes := fake.Calls("CreateIndex"); assert.Len(t, indexes, 2) {
+		assert.Equal(t, bson.D{{"Priority", -1}, {"VisibleAt", 1}, {"_id", 1}}, indexes[0].Document.(mongo.IndexModel).Keys)
+		assert.Equal(t, bson.D{{"DedupeKey", 1}}, indexes[1].Document.(mongo.IndexModel).Keys)
+	}
+}
+
+func TestQueue_Enqueue(t *testing.T) {
+	fake := &conntest.Fake{}
+	q := NewQueue(fake.Conn(), NewParams{})
+
+	id, err := q.Enqueue(bson.M{"mail": "foo@example.com"}, EnqueueOptions{Priority: 2, Delay: time.Minute, DedupeKey: "foo"})
+
+	assert.Nil(t, err)
+	if inserted := fake.Calls("InsertOne"); assert.Len(t, inserted, 1) {
+		assert.Equal(t, "Jobs", inserted[0].Collection)
+		e := inserted[0].Document.(entry)
+		assert.Equal(t, id, e.Id)
+		assert.Equal(t, 2, e.Priority)
+		assert.Equal(t, "foo", e.DedupeKey)
+		assert.Equal(t, time.Minute, e.VisibleAt.Sub(e.CreatedAt))
+	}
+}
+
+func TestQueue_EnqueueDuplicate(t *testing.T) {
+	fake := &conntest.Fake{Errors: map[string]error{
+		"InsertOne": mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000}}},
+	}}
+
+	_, err := NewQueue(fake.Conn(), NewParams{}).Enqueue("payload", EnqueueOptions{DedupeKey: "foo"})
+
+	assert.Equal(t, ErrDuplicate, err)
+}
+
+func TestQueue_Claim(t *testing.T) {
+	fake := &conntest.Fake{Docs: []interface{}{job("66cc9ca8c042f7a732b7fc2a", 1)}}
+	q := NewQueue(fake.Conn(), NewParams{})
+
+	job, err := q.Claim()
+	assert.Nil(t, err)
+	assert.Equal(t, types.ObjectId("66cc9ca8c042f7a732b7fc2a"), job.Id)
+	assert.Equal(t, "payload", job.Payload.StringValue())
+
+	job, err = q.Claim()
+	assert.Nil(t, err)
+	assert.Nil(t, job)
+}
+
+func TestQueue_Ack(t *testing.T) {
+	fake := &conntest.Fake{Matched: 1}
+	q := NewQueue(fake.Conn(), NewParams{})
+	job := &Job{Id: "66cc9ca8c042f7a732b7fc2a", Lease: "66cc9ca8c042f7a732b7fc2b"}
+
+	assert.Nil(t, q.Ack(job))
+	assert.Equal(t, bson.D{{"_id", job.Id}, {"Lease", job.Lease}}, fake.Calls("DeleteOne")[0].Filter)
+
+	fake.Matched = 0
+	assert.Equal(t, ErrLeaseLost, q.Ack(job))
+}
+
+func TestQueue_NackRetry(t *testing.T) {
+	fake := &conntest.Fake{Matched: 1}
+	q := NewQueue(fake.Conn(), NewParams{MinBackoff: time.Minute})
+	job := &Job{Id: "66cc9ca8c042f7a732b7fc2a", Attempts: 3, Lease: "66cc9ca8c042f7a732b7fc2b"}
+
+	assert.Nil(t, q.Nack(job, errors.New("smtp down")))
+	if updates := fake.Calls("UpdateOne"); assert.Len(t, updates, 1) {
+		set := updates[0].Update[0].Value.(bson.D)
+		assert.WithinDuration(t, time.Now().Add(4*time.Minute), set[0].Value.(time.Time), time.Second)
+		assert.Equal(t, bson.E{"LastError", "smtp down"}, set[1])
+	}
+	assert.Empty(t, fake.Calls("InsertOne"))
+}
+
+func TestQueue_NackDeadLetter(t *testing.T) {
+	fake := &conntest.Fake{Matched: 1}
+	q := NewQueue(fake.Conn(), NewParams{MaxAttempts: 3})
+	job := &Job{Id: "66cc9ca8c042f7a732b7fc2a", Attempts: 3, Lease: "66cc9ca8c042f7a732b7fc2b"}
+
+	assert.Nil(t, q.Nack(job, errors.New("smtp down")))
+	if inserted := fake.Calls("InsertOne"); assert.Len(t, inserted, 1) {
+		assert.Equal(t, "DeadJobs", inserted[0].Collection)
+		dead := inserted[0].Document.(deadJob)
+		assert.Equal(t, "smtp down", dead.LastError)
+		assert.False(t, dead.FailedAt.IsZero())
+	}
+	assert.Len(t, fake.Calls("DeleteOne"), 1)
+	assert.Empty(t, fake.Calls("UpdateOne"))
+}
+
+func TestQueue_Work(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	fake := &conntest.Fake{Matched: 1, Docs: []interface{}{
+		job("66cc9ca8c042f7a732b7fc2a", 1),
+		job("66cc9ca8c042f7a732b7fc2b", 1),
+	}}
+	q := NewQueue(fake.Conn(), NewParams{PollInterval: time.Millisecond})
+
+	var handled []types.ObjectId
+	q.Work(ctx, func(ctx context.Context, job *Job) error {
+		handled = append(handled, job.Id)
+		if len(handled) == 2 {
+			cancel()
+			return errors.New("failed")
+		}
+		return nil
+	})
+
+	assert.Equal(t, []types.ObjectId{"66cc9ca8c042f7a732b7fc2a", "66cc9ca8c042f7a732b7fc2b"}, handled)
+	assert.Len(t, fake.Calls("DeleteOne"), 1)
+	assert.Len(t, fake.Calls("UpdateOne"), 1)
+}
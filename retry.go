@@ -0,0 +1,472 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+)
+
+// retryableCodes are the server error codes, which are considered transient, like elections or write conflicts.
+var retryableCodes = []int{
+	6,     // HostUnreachable
+	7,     // HostNotFound
+	89,    // NetworkTimeout
+	91,    // ShutdownInProgress
+	112,   // WriteConflict
+	189,   // PrimarySteppedDown
+	262,   // ExceededTimeLimit
+	9001,  // SocketException
+	10107, // NotWritablePrimary
+	11600, // InterruptedAtShutdown
+	11602, // InterruptedDueToReplStateChange
+	13435, // NotPrimaryNoSecondaryOk
+	13436, // NotPrimaryOrSecondary
+}
+
+// IsRetryableError reports whether err is transient and the operation can be retried, these are network errors,
+// errors labeled with TransientTransactionError or RetryableWriteError, and errors caused by elections or write conflicts.
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if mongo.IsNetworkError(err) {
+		return true
+	}
+
+	var se mongo.ServerError
+	if !errors.As(err, &se) {
+		return false
+	}
+
+	if se.HasErrorLabel("TransientTransactionError") || se.HasErrorLabel("RetryableWriteError") {
+		return true
+	}
+
+	return slices.ContainsFunc(retryableCodes, se.HasErrorCode)
+}
+
+// RetryParams holds the parameters of the RetryConnector.
+type RetryParams struct {
+	// MaxAttempts is the maximum number of attempts including the first one, defaults to 5.
+	MaxAttempts int
+	// MinBackoff and MaxBackoff bound the exponential backoff between the attempts, default to 100ms and 5s.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// Jitter is the maximum fraction randomly subtracted from each backoff, defaults to 0.5, a negative value disables it.
+	Jitter float64
+	// MaxElapsed bounds the total time spent for retrying an operation, defaults to 30s.
+	// The retries are also stopped, if the context of the connector is done.
+	MaxElapsed time.Duration
+	// Retryable decides, whether an error is retried, defaults to IsRetryableError.
+	Retryable func(err error) bool
+	// RetryNonIdempotent retries also the operations, which might be applied twice, if the failed attempt reached
+	// the server: the inserts, updates, DeleteOne, FindOneAndDelete, WithTransaction, RunCommand, GetNextSeq and
+	// GetNextSeqRange.
+	RetryNonIdempotent bool
+	// OnRetry is called before each retry with the number of the failed attempt, its error and the backoff.
+	OnRetry func(attempt int, err error, backoff time.Duration)
+}
+
+// RetryConnector is a Connector decorator retrying operations failing with transient errors, using exponential
+// backoff with jitter. Cursors and change streams are not retried once they have been opened.
+// By default, only reads and writes, which can be safely applied multiple times, are retried, e.g. replacements,
+// DeleteMany and the index and collection management. A retried insert might fail with a duplicate key or insert a
+// second document, DeleteOne with a non-unique filter might delete a second document, updates might apply operators
+// like $inc twice and a transaction might be committed twice. These are attempted once unless RetryNonIdempotent is
+// set, the retryable writes of the driver cover most of these cases.
+type RetryConnector struct {
+	Connector
+	params  RetryParams
+	context context.Context
+}
+
+// NewRetryConnector wraps conn into a RetryConnector, the retries are stopped if the context of conn is done.
+func NewRetryConnector(conn Connector, params RetryParams) *RetryConnector {
+	if params.MaxAttempts <= 0 {
+		params.MaxAttempts = 5
+	}
+
+	if params.MinBackoff <= 0 {
+		params.MinBackoff = 100 * time.Millisecond
+	}
+
+	if params.MaxBackoff < params.MinBackoff {
+		params.MaxBackoff = max(5*time.Second, params.MinBackoff)
+	}
+
+	if params.Jitter == 0 {
+		params.Jitter = 0.5
+	}
+	params.Jitter = min(max(params.Jitter, 0), 1)
+
+	if params.MaxElapsed <= 0 {
+		params.MaxElapsed = 30 * time.Second
+	}
+
+	if params.Retryable == nil {
+		params.Retryable = IsRetryableError
+	}
+
+	return &RetryConnector{
+		Connector: conn,
+		params:    params,
+		context:   contextOf(conn),
+	}
+}
+
+// contextOf returns the context of the connector, if it exposes it like the StdConnector, otherwise the background context.
+func contextOf(conn Connector) context.Context {
+	if c, ok := conn.(interface{ Context() context.Context }); ok && c.Context() != nil {
+		return c.Context()
+	}
+
+	return context.Background()
+}
+
+// retry calls op until it succeeds, fails with a non-retryable error, or the retries are exhausted.
+// The result of the last attempt is returned.
+func retry[T any](c *RetryConnector, op func() (T, error)) (T, error) {
+	start := time.Now()
+	backoff := time.Duration(0)
+
+	for attempt := 1; ; attempt++ {
+		res, err := op()
+		if err == nil || attempt >= c.params.MaxAttempts || !c.params.Retryable(err) {
+			return res, err
+		}
+
+		backoff = nextBackoff(backoff, c.params.MinBackoff, c.params.MaxBackoff)
+		wait := backoff - time.Duration(rand.Float64()*c.params.Jitter*float64(backoff))
+
+		if time.Since(start)+wait > c.params.MaxElapsed {
+			return res, err
+		}
+
+		if c.params.OnRetry != nil {
+			c.params.OnRetry(attempt, err, wait)
+		}
+
+		select {
+		case <-c.context.Done():
+			return res, err
+		case <-time.After(wait):
+		}
+	}
+}
+
+// retryNonIdempotent retries op like retry, if RetryNonIdempotent is set, otherwise op is attempted once.
+func retryNonIdempotent[T any](c *RetryConnector, op func() (T, error)) (T, error) {
+	if !c.params.RetryNonIdempotent {
+		return op()
+	}
+
+	return retry(c, op)
+}
+
+// wrap returns a copy of the decorator around conn.
+func (c *RetryConnector) wrap(conn Connector) *RetryConnector {
+	newConn := *c
+	newConn.Connector = conn
+	return &newConn
+}
+
+// Context returns the context, which stops the retries if it is done.
+func (c *RetryConnector) Context() context.Context {
+	return c.context
+}
+
+// WithContext returns a copy of the connector with the specified context, the retries are stopped if it is done.
+func (c *RetryConnector) WithContext(ctx context.Context) Connector {
+	newConn := c.wrap(c.Connector.WithContext(ctx))
+	newConn.context = ctx
+	return newConn
+}
+
+// WithCollection returns a copy of the connector with the specified collection.
+func (c *RetryConnector) WithCollection(coll string, opts ...options.Lister[options.CollectionOptions]) Connector {
+	return c.wrap(c.Connector.WithCollection(coll, opts...))
+}
+
+// WithReadPreference returns a copy of the connector with the specified read preference.
+func (c *RetryConnector) WithReadPreference(rp *readpref.ReadPref) Connector {
+	return c.wrap(c.Connector.WithReadPreference(rp))
+}
+
+// WithCausalConsistency executes fn inside a causally consistent session, the connector passed to fn retries single operations.
+func (c *RetryConnector) WithCausalConsistency(fn func(conn Connector) error) error {
+	return c.Connector.WithCausalConsistency(func(conn Connector) error {
+		return fn(c.wrap(conn))
+	})
+}
+
+// singleResult retries operations returning a SingleResult.
+func (c *RetryConnector) singleResult(op func() *mongo.SingleResult) *mongo.SingleResult {
+	res, _ := retry(c, func() (*mongo.SingleResult, error) {
+		res := op()
+		if res == nil {
+			return nil, nil
+		}
+		return res, res.Err()
+	})
+
+	return res
+}
+
+// CreateCollection retries CreateCollection of the wrapped connector.
+func (c *RetryConnector) CreateCollection(name string, opts ...options.Lister[options.CreateCollectionOptions]) error {
+	_, err := retry(c, func() (struct{}, error) {
+		return struct{}{}, c.Connector.CreateCollection(name, opts...)
+	})
+	return err
+}
+
+// CreateView retries CreateView of the wrapped connector.
+func (c *RetryConnector) CreateView(name string, source string, pipeline interface{}, opts ...options.Lister[options.CreateViewOptions]) error {
+	_, err := retry(c, func() (struct{}, error) {
+		return struct{}{}, c.Connector.CreateView(name, source, pipeline, opts...)
+	})
+	return err
+}
+
+// ListCollections retries ListCollections of the wrapped connector.
+func (c *RetryConnector) ListCollections(filter interface{}, opts ...options.Lister[options.ListCollectionsOptions]) ([]mongo.CollectionSpecification, error) {
+	return retry(c, func() ([]mongo.CollectionSpecification, error) {
+		return c.Connector.ListCollections(filter, opts...)
+	})
+}
+
+// Find retries Find of the wrapped connector.
+func (c *RetryConnector) Find(filter interface{}, opts ...options.Lister[options.FindOptions]) (*mongo.Cursor, error) {
+	return retry(c, func() (*mongo.Cursor, error) {
+		return c.Connector.Find(filter, opts...)
+	})
+}
+
+// FindOne retries FindOne of the wrapped connector.
+func (c *RetryConnector) FindOne(filter interface{}, opts ...options.Lister[options.FindOneOptions]) *mongo.SingleResult {
+	return c.singleResult(func() *mongo.SingleResult {
+		return c.Connector.FindOne(filter, opts...)
+	})
+}
+
+// Count retries Count of the wrapped connector.
+func (c *RetryConnector) Count(filter interface{}, opts ...options.Lister[options.CountOptions]) (int64, error) {
+	return retry(c, func() (int64, error) {
+		return c.Connector.Count(filter, opts...)
+	})
+}
+
+// Distinct retries Distinct of the wrapped connector.
+func (c *RetryConnector) Distinct(fieldName string, filter interface{}, opts ...options.Lister[options.DistinctOptions]) (*mongo.DistinctResult, error) {
+	return retry(c, func() (*mongo.DistinctResult, error) {
+		return c.Connector.Distinct(fieldName, filter, opts...)
+	})
+}
+
+// FindOneAndDelete retries FindOneAndDelete of the wrapped connector, if RetryNonIdempotent is set.
+func (c *RetryConnector) FindOneAndDelete(filter interface{}, opts ...options.Lister[options.FindOneAndDeleteOptions]) *mongo.SingleResult {
+	if !c.params.RetryNonIdempotent {
+		return c.Connector.FindOneAndDelete(filter, opts...)
+	}
+
+	return c.singleResult(func() *mongo.SingleResult {
+		return c.Connector.FindOneAndDelete(filter, opts...)
+	})
+}
+
+// FindOneAndReplace retries FindOneAndReplace of the wrapped connector.
+func (c *RetryConnector) FindOneAndReplace(filter interface{}, replacement interface{}, opts ...options.Lister[options.FindOneAndReplaceOptions]) *mongo.SingleResult {
+	return c.singleResult(func() *mongo.SingleResult {
+		return c.Connector.FindOneAndReplace(filter, replacement, opts...)
+	})
+}
+
+// FindOneAndUpdate retries FindOneAndUpdate of the wrapped connector, if RetryNonIdempotent is set.
+func (c *RetryConnector) FindOneAndUpdate(filter interface{}, update interface{}, opts ...options.Lister[options.FindOneAndUpdateOptions]) *mongo.SingleResult {
+	if !c.params.RetryNonIdempotent {
+		return c.Connector.FindOneAndUpdate(filter, update, opts...)
+	}
+
+	return c.singleResult(func() *mongo.SingleResult {
+		return c.Connector.FindOneAndUpdate(filter, update, opts...)
+	})
+}
+
+// UpdateOne retries UpdateOne of the wrapped connector, if RetryNonIdempotent is set.
+func (c *RetryConnector) UpdateOne(filter interface{}, update interface{}, opts ...options.Lister[options.UpdateOneOptions]) (*mongo.UpdateResult, error) {
+	return retryNonIdempotent(c, func() (*mongo.UpdateResult, error) {
+		return c.Connector.UpdateOne(filter, update, opts...)
+	})
+}
+
+// UpdateMany retries UpdateMany of the wrapped connector, if RetryNonIdempotent is set.
+func (c *RetryConnector) UpdateMany(filter interface{}, update interface{}, opts ...options.Lister[options.UpdateManyOptions]) (*mongo.UpdateResult, error) {
+	return retryNonIdempotent(c, func() (*mongo.UpdateResult, error) {
+		return c.Connector.UpdateMany(filter, update, opts...)
+	})
+}
+
+// UpdateById retries UpdateById of the wrapped connector, if RetryNonIdempotent is set.
+func (c *RetryConnector) UpdateById(id interface{}, update interface{}, opts ...options.Lister[options.UpdateOneOptions]) (*mongo.UpdateResult, error) {
+	return retryNonIdempotent(c, func() (*mongo.UpdateResult, error) {
+		return c.Connector.UpdateById(id, update, opts...)
+	})
+}
+
+// ReplaceOne retries ReplaceOne of the wrapped connector.
+func (c *RetryConnector) ReplaceOne(filter interface{}, update interface{}, opts ...options.Lister[options.ReplaceOptions]) (*mongo.UpdateResult, error) {
+	return retry(c, func() (*mongo.UpdateResult, error) {
+		return c.Connector.ReplaceOne(filter, update, opts...)
+	})
+}
+
+// InsertOne retries InsertOne of the wrapped connector, if RetryNonIdempotent is set.
+func (c *RetryConnector) InsertOne(document interface{}, opts ...options.Lister[options.InsertOneOptions]) (*mongo.InsertOneResult, error) {
+	return retryNonIdempotent(c, func() (*mongo.InsertOneResult, error) {
+		return c.Connector.InsertOne(document, opts...)
+	})
+}
+
+// InsertMany retries InsertMany of the wrapped connector, if RetryNonIdempotent is set.
+func (c *RetryConnector) InsertMany(document []interface{}, opts ...options.Lister[options.InsertManyOptions]) (*mongo.InsertManyResult, error) {
+	return retryNonIdempotent(c, func() (*mongo.InsertManyResult, error) {
+		return c.Connector.InsertMany(document, opts...)
+	})
+}
+
+// DeleteOne retries DeleteOne of the wrapped connector, if RetryNonIdempotent is set.
+func (c *RetryConnector) DeleteOne(filter interface{}, opts ...options.Lister[options.DeleteOneOptions]) (*mongo.DeleteResult, error) {
+	return retryNonIdempotent(c, func() (*mongo.DeleteResult, error) {
+		return c.Connector.DeleteOne(filter, opts...)
+	})
+}
+
+// DeleteMany retries DeleteMany of the wrapped connector.
+func (c *RetryConnector) DeleteMany(filter interface{}, opts ...options.Lister[options.DeleteManyOptions]) (*mongo.DeleteResult, error) {
+	return retry(c, func() (*mongo.DeleteResult, error) {
+		return c.Connector.DeleteMany(filter, opts...)
+	})
+}
+
+// Aggregate retries Aggregate of the wrapped connector.
+func (c *RetryConnector) Aggregate(pipeline interface{}, opts ...options.Lister[options.AggregateOptions]) (*mongo.Cursor, error) {
+	return retry(c, func() (*mongo.Cursor, error) {
+		return c.Connector.Aggregate(pipeline, opts...)
+	})
+}
+
+// CreateIndex retries CreateIndex of the wrapped connector.
+func (c *RetryConnector) CreateIndex(model mongo.IndexModel, opts ...options.Lister[options.CreateIndexesOptions]) (string, error) {
+	return retry(c, func() (string, error) {
+		return c.Connector.CreateIndex(model, opts...)
+	})
+}
+
+// CreateSearchIndex retries CreateSearchIndex of the wrapped connector.
+func (c *RetryConnector) CreateSearchIndex(model mongo.SearchIndexModel, opts ...options.Lister[options.CreateSearchIndexesOptions]) (string, error) {
+	return retry(c, func() (string, error) {
+		return c.Connector.CreateSearchIndex(model, opts...)
+	})
+}
+
+// Drop retries Drop of the wrapped connector.
+func (c *RetryConnector) Drop() error {
+	_, err := retry(c, func() (struct{}, error) {
+		return struct{}{}, c.Connector.Drop()
+	})
+	return err
+}
+
+// SetValidator retries SetValidator of the wrapped connector.
+func (c *RetryConnector) SetValidator(validator interface{}, level string, action string) error {
+	_, err := retry(c, func() (struct{}, error) {
+		return struct{}{}, c.Connector.SetValidator(validator, level, action)
+	})
+	return err
+}
+
+// RunCommand retries RunCommand of the wrapped connector, if RetryNonIdempotent is set.
+func (c *RetryConnector) RunCommand(cmd interface{}, opts ...options.Lister[options.RunCmdOptions]) *mongo.SingleResult {
+	if !c.params.RetryNonIdempotent {
+		return c.Connector.RunCommand(cmd, opts...)
+	}
+
+	return c.singleResult(func() *mongo.SingleResult {
+		return c.Connector.RunCommand(cmd, opts...)
+	})
+}
+
+// WithTransaction retries the whole transaction, if RetryNonIdempotent is set, the connector passed to fn does not
+// retry single operations. A transaction, whose commit failed with an unknown result, might have been committed, the
+// driver already retries the transient transaction errors and the commit.
+func (c *RetryConnector) WithTransaction(fn func(conn Connector) error, opts ...options.Lister[options.TransactionOptions]) error {
+	_, err := retryNonIdempotent(c, func() (struct{}, error) {
+		return struct{}{}, c.Connector.WithTransaction(fn, opts...)
+	})
+	return err
+}
+
+// Watch retries Watch of the wrapped connector.
+func (c *RetryConnector) Watch(pipeline interface{}, opts ...options.Lister[options.ChangeStreamOptions]) (*mongo.ChangeStream, error) {
+	return retry(c, func() (*mongo.ChangeStream, error) {
+		return c.Connector.Watch(pipeline, opts...)
+	})
+}
+
+// WatchDatabase retries WatchDatabase of the wrapped connector.
+func (c *RetryConnector) WatchDatabase(pipeline interface{}, opts ...options.Lister[options.ChangeStreamOptions]) (*mongo.ChangeStream, error) {
+	return retry(c, func() (*mongo.ChangeStream, error) {
+		return c.Connector.WatchDatabase(pipeline, opts...)
+	})
+}
+
+// WatchDeployment retries WatchDeployment of the wrapped connector.
+func (c *RetryConnector) WatchDeployment(pipeline interface{}, opts ...options.Lister[options.ChangeStreamOptions]) (*mongo.ChangeStream, error) {
+	return retry(c, func() (*mongo.ChangeStream, error) {
+		return c.Connector.WatchDeployment(pipeline, opts...)
+	})
+}
+
+// GetNextSeq retries GetNextSeq of the wrapped connector, if RetryNonIdempotent is set.
+func (c *RetryConnector) GetNextSeq(name string, opts ...*SeqOptions) (int64, error) {
+	return retryNonIdempotent(c, func() (int64, error) {
+		return c.Connector.GetNextSeq(name, opts...)
+	})
+}
+
+// GetNextSeqRange retries GetNextSeqRange of the wrapped connector, if RetryNonIdempotent is set.
+func (c *RetryConnector) GetNextSeqRange(name string, n int64, opts ...*SeqOptions) (int64, error) {
+	return retryNonIdempotent(c, func() (int64, error) {
+		return c.Connector.GetNextSeqRange(name, n, opts...)
+	})
+}
+
+// CurrentSeq retries CurrentSeq of the wrapped connector.
+func (c *RetryConnector) CurrentSeq(name string, opts ...*SeqOptions) (int64, error) {
+	return retry(c, func() (int64, error) {
+		return c.Connector.CurrentSeq(name, opts...)
+	})
+}
+
+// ResetSeq retries ResetSeq of the wrapped connector.
+func (c *RetryConnector) ResetSeq(name string, opts ...*SeqOptions) error {
+	_, err := retry(c, func() (struct{}, error) {
+		return struct{}{}, c.Connector.ResetSeq(name, opts...)
+	})
+	return err
+}
+
+// SetSeq retries SetSeq of the wrapped connector.
+func (c *RetryConnector) SetSeq(name string, value int64, opts ...*SeqOptions) error {
+	_, err := retry(c, func() (struct{}, error) {
+		return struct{}{}, c.Connector.SetSeq(name, value, opts...)
+	})
+	return err
+}
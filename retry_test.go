@@ -0,0 +1,278 @@
+package mongodb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mbretter/go-mongodb/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+)
+
+var (
+	errWriteConflict = mongo.CommandError{Code: 112, Name: "WriteConflict"}
+	errTransient     = mongo.CommandError{Code: 251, Labels: []string{"TransientTransactionError"}}
+)
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		exp  bool
+	}{
+		{"nil", nil, false},
+		{"plain", errors.New("failed"), false},
+		{"no documents", mongo.ErrNoDocuments, false},
+		{"write conflict", errWriteConflict, true},
+		{"not writable primary", mongo.CommandError{Code: 10107}, true},
+		{"transient transaction", errTransient, true},
+		{"retryable write", mongo.WriteException{Labels: []string{"RetryableWriteError"}}, true},
+		{"write concern", mongo.WriteException{WriteConcernError: &mongo.WriteConcernError{Code: 189}}, true},
+		{"duplicate key", mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000}}}, false},
+		{"wrapped", errors.Join(errors.New("insert"), errWriteConflict), true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.exp, mongodb.IsRetryableError(test.err))
+		})
+	}
+}
+
+func TestRetryConnector_FindOne(t *testing.T) {
+	conn := NewConnectorMock(t)
+	conn.EXPECT().FindOne(bson.D{{"_id", "foo"}}).
+		Return(mongo.NewSingleResultFromDocument(bson.D{}, errWriteConflict, nil)).Twice()
+	conn.EXPECT().FindOne(bson.D{{"_id", "foo"}}).
+		Return(mongo.NewSingleResultFromDocument(bson.D{{"_id", "foo"}}, nil, nil)).Once()
+
+	var attempts []int
+	retryConn := mongodb.NewRetryConnector(conn, mongodb.RetryParams{
+		MinBackoff: time.Millisecond,
+		OnRetry: func(attempt int, err error, backoff time.Duration) {
+			assert.Equal(t, errWriteConflict, err)
+			assert.LessOrEqual(t, backoff, 2*time.Millisecond)
+			attempts = append(attempts, attempt)
+		},
+	})
+
+	var doc bson.M
+	err := retryConn.FindOne(bson.D{{"_id", "foo"}}).Decode(&doc)
+
+	assert.Nil(t, err)
+	assert.Equal(t, bson.M{"_id": "foo"}, doc)
+	assert.Equal(t, []int{1, 2}, attempts)
+}
+
+func TestRetryConnector_NotRetryable(t *testing.T) {
+	conn := NewConnectorMock(t)
+	conn.EXPECT().FindOne(bson.D{{"_id", "foo"}}).
+		Return(mongo.NewSingleResultFromDocument(bson.D{}, mongo.ErrNoDocuments, nil)).Once()
+
+	retryConn := mongodb.NewRetryConnector(conn, mongodb.RetryParams{
+		OnRetry: func(attempt int, err error, backoff time.Duration) {
+			t.Fatal("must not retry")
+		},
+	})
+
+	err := retryConn.FindOne(bson.D{{"_id", "foo"}}).Err()
+
+	assert.Equal(t, mongo.ErrNoDocuments, err)
+}
+
+func TestRetryConnector_MaxAttempts(t *testing.T) {
+	conn := NewConnectorMock(t)
+	conn.EXPECT().DeleteMany(bson.D{{"_id", "foo"}}).Return(nil, errTransient).Times(3)
+
+	retryConn := mongodb.NewRetryConnector(conn, mongodb.RetryParams{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		Jitter:      -1,
+	})
+
+	_, err := retryConn.DeleteMany(bson.D{{"_id", "foo"}})
+
+	assert.Equal(t, errTransient, err)
+}
+
+func TestRetryConnector_MaxElapsed(t *testing.T) {
+	conn := NewConnectorMock(t)
+	conn.EXPECT().Count(bson.D{}).Return(0, errWriteConflict).Once()
+
+	retryConn := mongodb.NewRetryConnector(conn, mongodb.RetryParams{
+		MinBackoff: time.Second,
+		MaxElapsed: 100 * time.Millisecond,
+	})
+
+	_, err := retryConn.Count(bson.D{})
+
+	assert.Equal(t, errWriteConflict, err)
+}
+
+func TestRetryConnector_ContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	conn := NewConnectorMock(t)
+	conn.EXPECT().WithContext(ctx).Return(conn)
+	conn.EXPECT().WithCollection("Users").Return(conn)
+	conn.EXPECT().DeleteMany(bson.D{}).Return(nil, errWriteConflict).Once()
+
+	retryConn := mongodb.NewRetryConnector(conn, mongodb.RetryParams{MinBackoff: time.Minute, MaxElapsed: time.Hour})
+
+	_, err := retryConn.WithContext(ctx).WithCollection("Users").DeleteMany(bson.D{})
+
+	assert.Equal(t, errWriteConflict, err)
+}
+
+// contextConnector exposes the context of the wrapped connector like the StdConnector.
+type contextConnector struct {
+	*ConnectorMock
+	ctx context.Context
+}
+
+func (c contextConnector) Context() context.Context {
+	return c.ctx
+}
+
+func TestRetryConnector_WrappedContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	conn := NewConnectorMock(t)
+	conn.EXPECT().Count(bson.D{}).Return(0, errWriteConflict).Once()
+
+	retryConn := mongodb.NewRetryConnector(contextConnector{conn, ctx}, mongodb.RetryParams{
+		MinBackoff: time.Minute,
+		MaxElapsed: time.Hour,
+	})
+
+	_, err := retryConn.Count(bson.D{})
+
+	assert.Equal(t, errWriteConflict, err)
+	assert.Equal(t, ctx, retryConn.Context())
+}
+
+func TestRetryConnector_NonIdempotent(t *testing.T) {
+	conn := NewConnectorMock(t)
+	conn.EXPECT().UpdateMany(bson.D{}, bson.D{}).Return(nil, errWriteConflict).Once()
+	conn.EXPECT().RunCommand(bson.D{{"ping", 1}}).
+		Return(mongo.NewSingleResultFromDocument(bson.D{}, errWriteConflict, nil)).Once()
+	conn.EXPECT().InsertOne(bson.D{}).Return(nil, errWriteConflict).Once()
+	conn.EXPECT().InsertMany([]interface{}{bson.D{}}).Return(nil, errWriteConflict).Once()
+	conn.EXPECT().DeleteOne(bson.D{}).Return(nil, errWriteConflict).Once()
+	conn.EXPECT().WithTransaction(mock.Anything).Return(errTransient).Once()
+
+	retryConn := mongodb.NewRetryConnector(conn, mongodb.RetryParams{
+		MinBackoff: time.Millisecond,
+		OnRetry: func(attempt int, err error, backoff time.Duration) {
+			t.Fatal("must not retry")
+		},
+	})
+
+	_, err := retryConn.UpdateMany(bson.D{}, bson.D{})
+	assert.Equal(t, errWriteConflict, err)
+
+	err = retryConn.RunCommand(bson.D{{"ping", 1}}).Err()
+	assert.Equal(t, errWriteConflict, err)
+
+	_, err = retryConn.InsertOne(bson.D{})
+	assert.Equal(t, errWriteConflict, err)
+
+	_, err = retryConn.InsertMany([]interface{}{bson.D{}})
+	assert.Equal(t, errWriteConflict, err)
+
+	_, err = retryConn.DeleteOne(bson.D{})
+	assert.Equal(t, errWriteConflict, err)
+
+	err = retryConn.WithTransaction(func(tx mongodb.Connector) error {
+		return nil
+	})
+	assert.Equal(t, errTransient, err)
+}
+
+func TestRetryConnector_RetryNonIdempotent(t *testing.T) {
+	conn := NewConnectorMock(t)
+	conn.EXPECT().GetNextSeq("Users").Return(0, errWriteConflict).Once()
+	conn.EXPECT().GetNextSeq("Users").Return(42, nil).Once()
+	conn.EXPECT().InsertOne(bson.D{}).Return(nil, errWriteConflict).Once()
+	conn.EXPECT().InsertOne(bson.D{}).Return(&mongo.InsertOneResult{InsertedID: 1}, nil).Once()
+	conn.EXPECT().DeleteOne(bson.D{}).Return(nil, errWriteConflict).Once()
+	conn.EXPECT().DeleteOne(bson.D{}).Return(&mongo.DeleteResult{DeletedCount: 1}, nil).Once()
+
+	retryConn := mongodb.NewRetryConnector(conn, mongodb.RetryParams{
+		MinBackoff:         time.Millisecond,
+		RetryNonIdempotent: true,
+	})
+
+	seq, err := retryConn.GetNextSeq("Users")
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(42), seq)
+
+	insRes, err := retryConn.InsertOne(bson.D{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, insRes.InsertedID)
+
+	delRes, err := retryConn.DeleteOne(bson.D{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), delRes.DeletedCount)
+}
+
+func TestRetryConnector_SetValidator(t *testing.T) {
+	conn := NewConnectorMock(t)
+	conn.EXPECT().SetValidator(bson.D{}, mongodb.ValidationLevelStrict, mongodb.ValidationActionError).
+		Return(errWriteConflict).Once()
+	conn.EXPECT().SetValidator(bson.D{}, mongodb.ValidationLevelStrict, mongodb.ValidationActionError).
+		Return(nil).Once()
+
+	retryConn := mongodb.NewRetryConnector(conn, mongodb.RetryParams{MinBackoff: time.Millisecond})
+
+	err := retryConn.SetValidator(bson.D{}, mongodb.ValidationLevelStrict, mongodb.ValidationActionError)
+
+	assert.Nil(t, err)
+}
+
+func TestRetryConnector_WithTransaction(t *testing.T) {
+	conn := NewConnectorMock(t)
+	conn.EXPECT().WithTransaction(mock.Anything).Return(errTransient).Once()
+	conn.EXPECT().WithTransaction(mock.Anything).Return(nil).Once()
+
+	retryConn := mongodb.NewRetryConnector(conn, mongodb.RetryParams{
+		MinBackoff:         time.Millisecond,
+		RetryNonIdempotent: true,
+	})
+
+	err := retryConn.WithTransaction(func(tx mongodb.Connector) error {
+		return nil
+	})
+
+	assert.Nil(t, err)
+}
+
+func TestRetryConnector_WithCausalConsistency(t *testing.T) {
+	conn := NewConnectorMock(t)
+	conn.EXPECT().WithCausalConsistency(mock.Anything).RunAndReturn(func(fn func(conn mongodb.Connector) error) error {
+		return fn(conn)
+	}).Once()
+	conn.EXPECT().WithReadPreference(readpref.SecondaryPreferred()).Return(conn)
+	conn.EXPECT().Count(bson.D{}).Return(0, errTransient).Once()
+	conn.EXPECT().Count(bson.D{}).Return(1, nil).Once()
+
+	retryConn := mongodb.NewRetryConnector(conn, mongodb.RetryParams{MinBackoff: time.Millisecond})
+
+	err := retryConn.WithCausalConsistency(func(sess mongodb.Connector) error {
+		cnt, err := sess.WithReadPreference(readpref.SecondaryPreferred()).Count(bson.D{})
+		assert.Equal(t, int64(1), cnt)
+		return err
+	})
+
+	assert.Nil(t, err)
+}
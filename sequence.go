@@ -0,0 +1,475 @@
+package mongodb
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mbretter/go-mongodb/v2/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var (
+	ErrInvalidSeqCount = errors.New("number of sequence numbers must be greater than zero")
+	ErrInvalidSeqStep  = errors.New("sequence step must be greater than zero")
+	ErrSeqOverflow     = errors.New("sequence overflow")
+	ErrSeqType         = errors.New("unsupported sequence value")
+)
+
+// SeqOptions holds the options of the sequence functions, the setters can be chained:
+//
+//	conn.GetNextSeq("Users", mongodb.Seq().SetCollection("Counters").SetStart(1000))
+//
+// If multiple options are passed, the values set later take precedence.
+type SeqOptions struct {
+	// Collection where the sequences are stored, defaults to "Sequences".
+	Collection *string
+	// Field holding the current number, defaults to "Current".
+	Field *string
+	// Start is the first number of a new sequence, defaults to 1.
+	Start *int64
+	// Step is the increment between two numbers, defaults to 1.
+	Step *int64
+	// Session executes the sequence operations within the given session.
+	Session *mongo.Session
+	// SeedCollection and SeedField seed a new sequence from the current maximum of the field in the collection.
+	SeedCollection *string
+	SeedField      *string
+}
+
+// Seq creates a new SeqOptions instance.
+func Seq() *SeqOptions {
+	return &SeqOptions{}
+}
+
+// SetCollection sets the collection where the sequences are stored.
+func (o *SeqOptions) SetCollection(collection string) *SeqOptions {
+	o.Collection = &collection
+	return o
+}
+
+// SetField sets the field holding the current number.
+func (o *SeqOptions) SetField(field string) *SeqOptions {
+	o.Field = &field
+	return o
+}
+
+// SetStart sets the first number of a new sequence.
+func (o *SeqOptions) SetStart(start int64) *SeqOptions {
+	o.Start = &start
+	return o
+}
+
+// SetStep sets the increment between two numbers.
+func (o *SeqOptions) SetStep(step int64) *SeqOptions {
+	o.Step = &step
+	return o
+}
+
+// SetSession sets the session the sequence operations are executed in.
+func (o *SeqOptions) SetSession(sess *mongo.Session) *SeqOptions {
+	o.Session = sess
+	return o
+}
+
+// SetSeedFrom seeds a new sequence from the maximum of field in collection, so the first number is the maximum plus step.
+// It is used for introducing a sequence on a collection, which already contains numbered documents.
+func (o *SeqOptions) SetSeedFrom(collection string, field string) *SeqOptions {
+	o.SeedCollection = &collection
+	o.SeedField = &field
+	return o
+}
+
+// seqParams holds the merged sequence options.
+type seqParams struct {
+	conn           Connector
+	name           string
+	collection     string
+	field          string
+	start          int64
+	step           int64
+	seedCollection string
+	seedField      string
+}
+
+// mergeSeqOptions merges the options into the parameters including the defaults.
+func mergeSeqOptions(opts []*SeqOptions) (p seqParams, sess *mongo.Session) {
+	p = seqParams{
+		collection: "Sequences",
+		field:      "Current",
+		start:      1,
+		step:       1,
+	}
+
+	for _, o := range opts {
+		if o == nil {
+			continue
+		}
+		if o.Collection != nil && len(*o.Collection) > 0 {
+			p.collection = *o.Collection
+		}
+		if o.Field != nil && len(*o.Field) > 0 {
+			p.field = *o.Field
+		}
+		if o.Start != nil {
+			p.start = *o.Start
+		}
+		if o.Step != nil {
+			p.step = *o.Step
+		}
+		if o.Session != nil {
+			sess = o.Session
+		}
+		if o.SeedCollection != nil && o.SeedField != nil {
+			p.seedCollection, p.seedField = *o.SeedCollection, *o.SeedField
+		}
+	}
+
+	return p, sess
+}
+
+// seqParams merges the options and resolves the name of the sequence, which defaults to the name of the collection.
+func (conn *StdConnector) seqParams(name string, opts []*SeqOptions) (seqParams, error) {
+	p, sess := mergeSeqOptions(opts)
+	if p.step < 1 {
+		return p, ErrInvalidSeqStep
+	}
+
+	p.name = name
+	if len(p.name) == 0 {
+		if conn.collection == nil {
+			return p, ErrNoCollectionSet
+		}
+
+		p.name = conn.collection.Name()
+	}
+
+	var c Connector = conn
+	if sess != nil {
+		c = conn.WithContext(mongo.NewSessionContext(conn.context, sess))
+	}
+	p.conn = c.WithCollection(p.collection)
+
+	return p, nil
+}
+
+// GetNextSeq increments and retrieves the next sequence number for a given name within the specified collection.
+// If no name is given, the name of the current collection is used.
+func (conn *StdConnector) GetNextSeq(name string, opts ...*SeqOptions) (seq int64, err error) {
+	return conn.GetNextSeqRange(name, 1, opts...)
+}
+
+// GetNextSeqRange reserves a block of n sequence numbers in one round trip and returns the first number of the block,
+// the numbers first, first+step, ... first+(n-1)*step belong to the caller.
+// The type of the stored number is kept, ErrSeqOverflow is returned without changing the sequence, if the next
+// numbers exceed the range of the type, e.g. int32.
+func (conn *StdConnector) GetNextSeqRange(name string, n int64, opts ...*SeqOptions) (first int64, err error) {
+	if n < 1 {
+		return 0, ErrInvalidSeqCount
+	}
+
+	p, err := conn.seqParams(name, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	if n > math.MaxInt64/p.step {
+		return 0, ErrSeqOverflow
+	}
+	inc := n * p.step
+
+	initial, err := p.initial()
+	if err != nil {
+		return 0, err
+	}
+
+	var prev bson.Raw
+	err = p.conn.FindOneAndUpdate(
+		bson.D{{"_id", p.name}},
+		seqIncrement(p.field, initial, inc),
+		options.FindOneAndUpdate().SetUpsert(true),
+		options.FindOneAndUpdate().SetReturnDocument(options.Before),
+		options.FindOneAndUpdate().SetProjection(bson.D{{p.field, 1}})).Decode(&prev)
+
+	// no document or no field means, that the sequence has been initialized
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, err
+	}
+
+	cur, limit := initial, int64(math.MaxInt64)
+	if val := prev.Lookup(p.field); err == nil && val.Type != 0 {
+		if val.Type == bson.TypeInt32 {
+			limit = math.MaxInt32
+		}
+
+		cur, err = seqNumber(val)
+		if err != nil {
+			return 0, fmt.Errorf("sequence %s: %w", p.name, err)
+		}
+	}
+
+	if cur > limit-inc {
+		return 0, fmt.Errorf("sequence %s: %w", p.name, ErrSeqOverflow)
+	}
+
+	return cur + p.step, nil
+}
+
+// CurrentSeq returns the current number of the sequence without incrementing it, 0 if the sequence does not exist.
+func (conn *StdConnector) CurrentSeq(name string, opts ...*SeqOptions) (seq int64, err error) {
+	p, err := conn.seqParams(name, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	var doc bson.Raw
+	err = p.conn.FindOne(bson.D{{"_id", p.name}}, options.FindOne().SetProjection(bson.D{{p.field, 1}})).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	val := doc.Lookup(p.field)
+	if val.Type == 0 {
+		return 0, nil
+	}
+
+	seq, err = seqNumber(val)
+	if err != nil {
+		return 0, fmt.Errorf("sequence %s: %w", p.name, err)
+	}
+
+	return seq, nil
+}
+
+// SetSeq sets the current number of the sequence, the next call of GetNextSeq returns value+step.
+// The numeric type of an existing sequence is kept, an int32 sequence is widened to int64, if value exceeds its range.
+func (conn *StdConnector) SetSeq(name string, value int64, opts ...*SeqOptions) error {
+	p, err := conn.seqParams(name, opts)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.conn.UpdateOne(
+		bson.D{{"_id", p.name}},
+		seqValue(p.field, value),
+		options.UpdateOne().SetUpsert(true))
+
+	return err
+}
+
+// ResetSeq resets the sequence, the next call of GetNextSeq returns the start value.
+func (conn *StdConnector) ResetSeq(name string, opts ...*SeqOptions) error {
+	p, err := conn.seqParams(name, opts)
+	if err != nil {
+		return err
+	}
+
+	return conn.SetSeq(name, p.start-p.step, opts...)
+}
+
+// initial returns the number, a new sequence is initialized with, before it is incremented.
+func (p seqParams) initial() (int64, error) {
+	if len(p.seedCollection) == 0 {
+		return p.start - p.step, nil
+	}
+
+	// the seed is only needed, if the sequence does not exist yet
+	err := p.conn.FindOne(bson.D{{"_id", p.name}}, options.FindOne().SetProjection(bson.D{{"_id", 1}})).Err()
+	if err == nil {
+		return 0, nil
+	}
+
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, err
+	}
+
+	var doc bson.Raw
+	err = p.conn.WithCollection(p.seedCollection).FindOne(
+		bson.D{{p.seedField, bson.D{{"$type", "number"}}}},
+		options.FindOne().SetSort(bson.D{{p.seedField, -1}}).SetProjection(bson.D{{p.seedField, 1}})).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return p.start - p.step, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return seqNumber(doc.Lookup(p.seedField))
+}
+
+// seqIncrement returns the update pipeline incrementing the field by inc, a missing field is initialized with initial.
+// The type of the field is kept, if the result would exceed the range of int32 or int64, the field is not changed.
+func seqIncrement(field string, initial int64, inc int64) mongo.Pipeline {
+	add := bson.D{{"$add", bson.A{"$$cur", inc}}}
+
+	return mongo.Pipeline{{{"$set", bson.D{{field, bson.D{{"$let", bson.D{
+		{"vars", bson.D{{"cur", bson.D{{"$ifNull", bson.A{"$" + field, initial}}}}}},
+		{"in", bson.D{{"$switch", bson.D{
+			{"branches", bson.A{
+				bson.D{
+					{"case", bson.D{{"$eq", bson.A{bson.D{{"$type", "$$cur"}}, "int"}}}},
+					{"then", bson.D{{"$cond", bson.A{
+						bson.D{{"$gt", bson.A{"$$cur", int64(math.MaxInt32) - inc}}}, "$$cur", bson.D{{"$toInt", add}},
+					}}}},
+				},
+			}},
+			{"default", bson.D{{"$cond", bson.A{
+				bson.D{{"$gt", bson.A{"$$cur", math.MaxInt64 - inc}}}, "$$cur", add,
+			}}}},
+		}}}},
+	}}}}}}}}
+}
+
+// seqValue returns the update pipeline setting the field to value, the numeric type of the field is kept.
+// A missing field is set to an int64, an int32 field is widened to int64, if value exceeds the range of int32.
+func seqValue(field string, value int64) mongo.Pipeline {
+	isType := func(typ string) bson.D {
+		return bson.D{{"$eq", bson.A{bson.D{{"$type", "$" + field}}, typ}}}
+	}
+
+	branches := bson.A{
+		bson.D{{"case", isType("double")}, {"then", bson.D{{"$toDouble", value}}}},
+		bson.D{{"case", isType("decimal")}, {"then", bson.D{{"$toDecimal", value}}}},
+	}
+	if value >= math.MinInt32 && value <= math.MaxInt32 {
+		branches = append(branches, bson.D{{"case", isType("int")}, {"then", bson.D{{"$toInt", value}}}})
+	}
+
+	return mongo.Pipeline{{{"$set", bson.D{{field, bson.D{{"$switch", bson.D{
+		{"branches", branches},
+		{"default", value},
+	}}}}}}}}
+}
+
+// seqNumber converts the stored number of a sequence into an int64.
+func seqNumber(val bson.RawValue) (int64, error) {
+	n, err := types.BsonInt(val, 64)
+	if errors.Is(err, types.ErrOverflow) {
+		return 0, fmt.Errorf("%w: %w", ErrSeqOverflow, err)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrSeqType, err)
+	}
+
+	return n, nil
+}
+
+// SeqAllocator hands out sequence numbers from blocks reserved by GetNextSeqRange, so only the first number of each
+// block causes a round trip to the database. The numbers are unique, but not gap-free, the unused numbers of a block
+// are lost, when the allocator is discarded. Multiple allocators of the same sequence hand out interleaving blocks.
+type SeqAllocator struct {
+	conn      Connector
+	name      string
+	blockSize int64
+	step      int64
+	opts      []*SeqOptions
+	mu        sync.Mutex
+	reserved  bool
+	next      int64
+	last      int64
+}
+
+// NewSeqAllocator creates an allocator for the named sequence reserving blockSize numbers at once, defaults to 100.
+func NewSeqAllocator(conn Connector, name string, blockSize int64, opts ...*SeqOptions) *SeqAllocator {
+	if blockSize < 1 {
+		blockSize = 100
+	}
+
+	p, _ := mergeSeqOptions(opts)
+
+	return &SeqAllocator{
+		conn:      conn,
+		name:      name,
+		blockSize: blockSize,
+		step:      p.step,
+		opts:      opts,
+	}
+}
+
+// Next returns the next number of the current block, a new block is reserved if the current one is used up.
+// It is safe for concurrent use.
+func (a *SeqAllocator) Next() (int64, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.reserved || a.next > a.last {
+		first, err := a.conn.GetNextSeqRange(a.name, a.blockSize, a.opts...)
+		if err != nil {
+			return 0, err
+		}
+
+		a.next, a.last, a.reserved = first, first+(a.blockSize-1)*a.step, true
+	}
+
+	seq := a.next
+	a.next += a.step
+
+	return seq, nil
+}
+
+// SeqFormat describes formatted sequence ids like INV-2026-000123.
+type SeqFormat struct {
+	// Prefix is put in front of the number, e.g. INV.
+	Prefix string
+	// Width pads the number with leading zeros.
+	Width int
+	// Yearly uses a separate sequence per year, the year is part of the id.
+	Yearly bool
+	// Separator between prefix, year and number, defaults to "-".
+	Separator string
+}
+
+// SeqName returns the name of the sequence used at time t, yearly sequences are suffixed with the year.
+func (f SeqFormat) SeqName(name string, t time.Time) string {
+	if f.Yearly {
+		return fmt.Sprintf("%s-%d", name, t.Year())
+	}
+
+	return name
+}
+
+// Format formats the sequence number seq fetched at time t.
+func (f SeqFormat) Format(seq int64, t time.Time) string {
+	sep := f.Separator
+	if len(sep) == 0 {
+		sep = "-"
+	}
+
+	var parts []string
+	if len(f.Prefix) > 0 {
+		parts = append(parts, f.Prefix)
+	}
+	if f.Yearly {
+		parts = append(parts, fmt.Sprintf("%d", t.Year()))
+	}
+	parts = append(parts, fmt.Sprintf("%0*d", f.Width, seq))
+
+	return strings.Join(parts, sep)
+}
+
+// NextSeqId fetches the next number of the named sequence by using GetNextSeq and returns it formatted.
+// Yearly sequences require a name, because it is suffixed with the year.
+func NextSeqId(conn Connector, name string, format SeqFormat, opts ...*SeqOptions) (string, error) {
+	if format.Yearly && len(name) == 0 {
+		return "", errors.New("yearly sequences require a name")
+	}
+
+	now := time.Now().UTC()
+
+	seq, err := conn.GetNextSeq(format.SeqName(name, now), opts...)
+	if err != nil {
+		return "", err
+	}
+
+	return format.Format(seq, now), nil
+}
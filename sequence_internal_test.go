@@ -0,0 +1,105 @@
+package mongodb
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func rawValue(v interface{}) bson.RawValue {
+	typ, data, _ := bson.MarshalValue(v)
+	return bson.RawValue{Type: typ, Value: data}
+}
+
+func decimal(s string) bson.Decimal128 {
+	d, _ := bson.ParseDecimal128(s)
+	return d
+}
+
+func TestSeqNumber(t *testing.T) {
+	tests := []struct {
+		name string
+		val  interface{}
+		exp  int64
+		err  error
+	}{
+		{"int32", int32(42), 42, nil},
+		{"int64", int64(math.MaxInt64), math.MaxInt64, nil},
+		{"double", float64(42), 42, nil},
+		{"double fraction", 42.5, 0, ErrSeqType},
+		{"double overflow", 1e19, 0, ErrSeqOverflow},
+		{"decimal", decimal("42"), 42, nil},
+		{"decimal exponent", decimal("4.2E+1"), 42, nil},
+		{"decimal positive exponent", decimal("42E+2"), 4200, nil},
+		{"decimal fraction", decimal("42.5"), 0, ErrSeqType},
+		{"decimal overflow", decimal("1E+19"), 0, ErrSeqOverflow},
+		{"decimal nan", decimal("NaN"), 0, ErrSeqType},
+		{"string", "42", 0, ErrSeqType},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			n, err := seqNumber(rawValue(test.val))
+
+			assert.True(t, errors.Is(err, test.err), err)
+			assert.Equal(t, test.exp, n)
+		})
+	}
+}
+
+func TestMergeSeqOptions(t *testing.T) {
+	p, sess := mergeSeqOptions(nil)
+
+	assert.Nil(t, sess)
+	assert.Equal(t, seqParams{collection: "Sequences", field: "Current", start: 1, step: 1}, p)
+
+	p, _ = mergeSeqOptions([]*SeqOptions{
+		Seq().SetCollection("Counters").SetStart(1000),
+		nil,
+		Seq().SetField("Value").SetStep(5).SetSeedFrom("Invoices", "Number"),
+	})
+
+	assert.Equal(t, seqParams{
+		collection:     "Counters",
+		field:          "Value",
+		start:          1000,
+		step:           5,
+		seedCollection: "Invoices",
+		seedField:      "Number",
+	}, p)
+}
+
+func TestSeqIncrement(t *testing.T) {
+	pipeline := seqIncrement("Current", 0, 10)
+
+	raw, err := bson.Marshal(bson.D{{"p", pipeline}})
+	assert.Nil(t, err)
+
+	set := bson.Raw(raw).Lookup("p", "0", "$set", "Current", "$let")
+	assert.Equal(t, int64(0), set.Document().Lookup("vars", "cur", "$ifNull", "1").Int64())
+	assert.Equal(t, int64(math.MaxInt32-10),
+		set.Document().Lookup("in", "$switch", "branches", "0", "then", "$cond", "0", "$gt", "1").Int64())
+	assert.Equal(t, int64(math.MaxInt64-10),
+		set.Document().Lookup("in", "$switch", "default", "$cond", "0", "$gt", "1").Int64())
+}
+
+func TestSeqValue(t *testing.T) {
+	raw, err := bson.Marshal(bson.D{{"p", seqValue("Current", 0)}})
+	assert.Nil(t, err)
+
+	sw := bson.Raw(raw).Lookup("p", "0", "$set", "Current", "$switch").Document()
+	assert.Equal(t, "decimal", sw.Lookup("branches", "1", "case", "$eq", "1").StringValue())
+	assert.Equal(t, "int", sw.Lookup("branches", "2", "case", "$eq", "1").StringValue())
+	assert.Equal(t, int64(0), sw.Lookup("branches", "2", "then", "$toInt").Int64())
+	assert.Equal(t, int64(0), sw.Lookup("default").Int64())
+
+	// int32 sequences are widened
+	raw, err = bson.Marshal(bson.D{{"p", seqValue("Current", math.MaxInt32+1)}})
+	assert.Nil(t, err)
+
+	branches, _ := bson.Raw(raw).Lookup("p", "0", "$set", "Current", "$switch", "branches").Array().Values()
+	assert.Len(t, branches, 2)
+}
@@ -0,0 +1,129 @@
+package mongodb_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mbretter/go-mongodb/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeqAllocator_Next(t *testing.T) {
+	conn := NewConnectorMock(t)
+	conn.EXPECT().GetNextSeqRange("Users", int64(3)).Return(1, nil).Once()
+	conn.EXPECT().GetNextSeqRange("Users", int64(3)).Return(7, nil).Once()
+
+	alloc := mongodb.NewSeqAllocator(conn, "Users", 3)
+
+	var seqs []int64
+	for range 5 {
+		seq, err := alloc.Next()
+		assert.Nil(t, err)
+		seqs = append(seqs, seq)
+	}
+
+	assert.Equal(t, []int64{1, 2, 3, 7, 8}, seqs)
+}
+
+func TestSeqAllocator_NextConcurrent(t *testing.T) {
+	conn := NewConnectorMock(t)
+	conn.EXPECT().GetNextSeqRange("Users", int64(100), mongodb.Seq().SetCollection("Seqs")).Return(1, nil).Once()
+
+	alloc := mongodb.NewSeqAllocator(conn, "Users", 0, mongodb.Seq().SetCollection("Seqs"))
+
+	var mu sync.Mutex
+	seen := map[int64]bool{}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			for range 10 {
+				seq, err := alloc.Next()
+				assert.Nil(t, err)
+
+				mu.Lock()
+				seen[seq] = true
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+
+	assert.Len(t, seen, 100)
+}
+
+func TestSeqAllocator_NextStep(t *testing.T) {
+	opts := mongodb.Seq().SetStart(0).SetStep(10)
+
+	conn := NewConnectorMock(t)
+	conn.EXPECT().GetNextSeqRange("Users", int64(2), opts).Return(0, nil).Once()
+	conn.EXPECT().GetNextSeqRange("Users", int64(2), opts).Return(20, nil).Once()
+
+	alloc := mongodb.NewSeqAllocator(conn, "Users", 2, opts)
+
+	var seqs []int64
+	for range 3 {
+		seq, err := alloc.Next()
+		assert.Nil(t, err)
+		seqs = append(seqs, seq)
+	}
+
+	assert.Equal(t, []int64{0, 10, 20}, seqs)
+}
+
+func TestSeqAllocator_NextError(t *testing.T) {
+	seqErr := errors.New("failed")
+
+	conn := NewConnectorMock(t)
+	conn.EXPECT().GetNextSeqRange("Users", int64(10)).Return(0, seqErr)
+
+	seq, err := mongodb.NewSeqAllocator(conn, "Users", 10).Next()
+
+	assert.Equal(t, seqErr, err)
+	assert.Equal(t, int64(0), seq)
+}
+
+func TestSeqFormat_Format(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		format mongodb.SeqFormat
+		seq    int64
+		exp    string
+	}{
+		{"plain", mongodb.SeqFormat{}, 123, "123"},
+		{"prefix", mongodb.SeqFormat{Prefix: "CUST"}, 5, "CUST-5"},
+		{"padded", mongodb.SeqFormat{Prefix: "CUST", Width: 4}, 5, "CUST-0005"},
+		{"overflow", mongodb.SeqFormat{Width: 2}, 12345, "12345"},
+		{"yearly", mongodb.SeqFormat{Prefix: "INV", Width: 6, Yearly: true}, 123, "INV-2026-000123"},
+		{"separator", mongodb.SeqFormat{Prefix: "INV", Width: 3, Yearly: true, Separator: "/"}, 7, "INV/2026/007"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.exp, test.format.Format(test.seq, now))
+		})
+	}
+}
+
+func TestNextSeqId(t *testing.T) {
+	name := fmt.Sprintf("Invoices-%d", time.Now().UTC().Year())
+
+	conn := NewConnectorMock(t)
+	conn.EXPECT().GetNextSeq(name).Return(123, nil)
+
+	id, err := mongodb.NextSeqId(conn, "Invoices", mongodb.SeqFormat{Prefix: "INV", Width: 6, Yearly: true})
+
+	assert.Nil(t, err)
+	assert.Equal(t, fmt.Sprintf("INV-%d-000123", time.Now().UTC().Year()), id)
+}
+
+func TestNextSeqId_YearlyWithoutName(t *testing.T) {
+	_, err := mongodb.NextSeqId(NewConnectorMock(t), "", mongodb.SeqFormat{Yearly: true})
+
+	assert.NotNil(t, err)
+}
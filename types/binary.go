@@ -6,6 +6,8 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
+
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
@@ -83,3 +85,94 @@ func (b *Binary) UnmarshalBSONValue(typ byte, data []byte) error {
 
 	return nil
 }
+
+// BinarySubtype selects the BSON binary subtype of BinaryOf, it is implemented by the Subtype* types.
+type BinarySubtype interface {
+	Subtype() byte
+}
+
+// SubtypeUserDefined selects the user defined binary subtype 0x80.
+type SubtypeUserDefined struct{}
+
+// Subtype returns bson.TypeBinaryUserDefined.
+func (SubtypeUserDefined) Subtype() byte { return bson.TypeBinaryUserDefined }
+
+// SubtypeMD5 selects the binary subtype 5 for MD5 hashes.
+type SubtypeMD5 struct{}
+
+// Subtype returns bson.TypeBinaryMD5.
+func (SubtypeMD5) Subtype() byte { return bson.TypeBinaryMD5 }
+
+// SubtypeEncrypted selects the binary subtype 6 for encrypted values.
+type SubtypeEncrypted struct{}
+
+// Subtype returns bson.TypeBinaryEncrypted.
+func (SubtypeEncrypted) Subtype() byte { return bson.TypeBinaryEncrypted }
+
+// SubtypeVector selects the binary subtype 9 for vectors.
+type SubtypeVector struct{}
+
+// Subtype returns bson.TypeBinaryVector.
+func (SubtypeVector) Subtype() byte { return bson.TypeBinaryVector }
+
+// BinaryOf is a binary like Binary, which is stored with the binary subtype selected by S,
+// e.g. BinaryOf[SubtypeMD5]. Decoding rejects any other subtype.
+type BinaryOf[S BinarySubtype] []byte
+
+// Aliases of the binary types of the common subtypes.
+type (
+	UserDefinedBinary = BinaryOf[SubtypeUserDefined]
+	MD5Binary         = BinaryOf[SubtypeMD5]
+	EncryptedBinary   = BinaryOf[SubtypeEncrypted]
+	VectorBinary      = BinaryOf[SubtypeVector]
+)
+
+// Subtype returns the binary subtype of the binary.
+func (b BinaryOf[S]) Subtype() byte {
+	var s S
+	return s.Subtype()
+}
+
+// MarshalJSON serializes the binary as a base64-encoded string or null if empty.
+func (b BinaryOf[S]) MarshalJSON() ([]byte, error) {
+	return Binary(b).MarshalJSON()
+}
+
+// UnmarshalJSON decodes a base64-encoded JSON string into the binary.
+func (b *BinaryOf[S]) UnmarshalJSON(data []byte) error {
+	return (*Binary)(b).UnmarshalJSON(data)
+}
+
+// MarshalBSONValue serializes the binary into a BSON binary of the subtype S, an empty binary is rendered to BSON null.
+func (b BinaryOf[S]) MarshalBSONValue() (byte, []byte, error) {
+	if len(b) == 0 {
+		return byte(bson.TypeNull), nil, nil
+	}
+
+	return marshalBsonValue(bson.Binary{Data: b, Subtype: b.Subtype()})
+}
+
+// UnmarshalBSONValue decodes a BSON binary of the subtype S into the binary, BSON null results in an empty binary.
+func (b *BinaryOf[S]) UnmarshalBSONValue(typ byte, data []byte) error {
+	if isBsonNull(typ) {
+		*b = nil
+		return nil
+	}
+
+	if bson.Type(typ) != bson.TypeBinary {
+		return errors.New("wrong bson type expected binary")
+	}
+
+	prim := bson.Binary{}
+	if err := bson.UnmarshalValue(bson.TypeBinary, data, &prim); err != nil {
+		return err
+	}
+
+	if prim.Subtype != b.Subtype() {
+		return fmt.Errorf("wrong bson subtype %#02x expected %#02x", prim.Subtype, b.Subtype())
+	}
+
+	*b = prim.Data
+
+	return nil
+}
@@ -143,3 +143,49 @@ func TestBinary_UnmarshalBSONInvalidSubType(t *testing.T) {
 		assert.Equal(t, "error decoding key data: wrong bson subtype expected generic", err.Error())
 	}
 }
+
+func TestBinaryOf_BSON(t *testing.T) {
+	type doc struct {
+		Hash types.MD5Binary `bson:"hash"`
+	}
+
+	data, err := bson.Marshal(doc{Hash: types.MD5Binary{0x01, 0x02}})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	sub, bin := bson.Raw(data).Lookup("hash").Binary()
+	assert.Equal(t, bson.TypeBinaryMD5, sub)
+	assert.Equal(t, []byte{0x01, 0x02}, bin)
+
+	var d doc
+	if assert.Nil(t, bson.Unmarshal(data, &d)) {
+		assert.Equal(t, types.MD5Binary{0x01, 0x02}, d.Hash)
+	}
+
+	var u struct {
+		Hash types.UserDefinedBinary `bson:"hash"`
+	}
+	assert.ErrorContains(t, bson.Unmarshal(data, &u), "wrong bson subtype 0x05 expected 0x80")
+}
+
+func TestBinaryOf_Subtypes(t *testing.T) {
+	assert.Equal(t, bson.TypeBinaryUserDefined, types.UserDefinedBinary{}.Subtype())
+	assert.Equal(t, bson.TypeBinaryMD5, types.MD5Binary{}.Subtype())
+	assert.Equal(t, bson.TypeBinaryEncrypted, types.EncryptedBinary{}.Subtype())
+	assert.Equal(t, bson.TypeBinaryVector, types.VectorBinary{}.Subtype())
+}
+
+func TestBinaryOf_JSON(t *testing.T) {
+	var b types.EncryptedBinary
+
+	if assert.Nil(t, json.Unmarshal([]byte(`"`+binaryTestImage+`"`), &b)) {
+		assert.Equal(t, types.EncryptedBinary(binaryTestData), b)
+	}
+
+	j, _ := json.Marshal(b)
+	assert.Equal(t, `"`+binaryTestImage+`"`, string(j))
+
+	j, _ = json.Marshal(types.EncryptedBinary(nil))
+	assert.Equal(t, `null`, string(j))
+}
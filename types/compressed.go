@@ -0,0 +1,231 @@
+package types
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// CompressionCodec identifies the compression of a CompressedBinary, it is stored as first byte of the payload.
+type CompressionCodec byte
+
+const (
+	// CodecNone stores the data uncompressed.
+	CodecNone CompressionCodec = iota
+	// CodecZstd compresses using zstd, which is the default.
+	CodecZstd
+	// CodecSnappy compresses using snappy, which is faster, but compresses less.
+	CodecSnappy
+	// CodecGzip compresses using gzip.
+	CodecGzip
+)
+
+var (
+	ErrUnknownCodec      = errors.New("unknown compression codec")
+	ErrDecompressedLimit = errors.New("decompressed data exceeds the maximum size")
+)
+
+var (
+	compressionCodec     = CodecZstd
+	compressionThreshold = 1024
+	maxDecompressedSize  = 64 << 20
+)
+
+// SetBinaryCompression sets the codec used by CompressedBinary and the size in bytes, from which data is compressed,
+// defaults to CodecZstd and 1024 bytes. Data is decompressed using the codec stored with it, regardless of this setting.
+func SetBinaryCompression(codec CompressionCodec, threshold int) {
+	compressionCodec = codec
+	compressionThreshold = threshold
+}
+
+// SetMaxDecompressedSize sets the maximum size in bytes of decompressed data, defaults to 64 MiB. Larger data
+// is rejected with ErrDecompressedLimit, which protects against decompression bombs.
+func SetMaxDecompressedSize(size int) {
+	maxDecompressedSize = size
+}
+
+var zstdEncoder = sync.OnceValues(func() (*zstd.Encoder, error) {
+	return zstd.NewWriter(nil)
+})
+
+// zstdDecoders holds the zstd decoder of the current maximum size.
+var zstdDecoders struct {
+	sync.Mutex
+	dec     *zstd.Decoder
+	maxSize int
+}
+
+// zstdDecoder returns a decoder limited to maxSize bytes, it is recreated if the maximum size has been changed.
+func zstdDecoder(maxSize int) (*zstd.Decoder, error) {
+	zstdDecoders.Lock()
+	defer zstdDecoders.Unlock()
+
+	if zstdDecoders.dec == nil || zstdDecoders.maxSize != maxSize {
+		dec, err := zstd.NewReader(nil, zstd.WithDecoderMaxMemory(uint64(maxSize)))
+		if err != nil {
+			return nil, err
+		}
+
+		zstdDecoders.dec, zstdDecoders.maxSize = dec, maxSize
+	}
+
+	return zstdDecoders.dec, nil
+}
+
+// compress compresses data using codec.
+func compress(codec CompressionCodec, data []byte) ([]byte, error) {
+	switch codec {
+	case CodecNone:
+		return data, nil
+	case CodecZstd:
+		enc, err := zstdEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return enc.EncodeAll(data, nil), nil
+	case CodecSnappy:
+		return snappy.Encode(nil, data), nil
+	case CodecGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	return nil, fmt.Errorf("%w: %d", ErrUnknownCodec, codec)
+}
+
+// decompress decompresses data, which has been compressed using codec, into at most maxDecompressedSize bytes.
+func decompress(codec CompressionCodec, data []byte) ([]byte, error) {
+	maxSize := maxDecompressedSize
+
+	switch codec {
+	case CodecNone:
+		return data, nil
+	case CodecZstd:
+		dec, err := zstdDecoder(maxSize)
+		if err != nil {
+			return nil, err
+		}
+		v, err := dec.DecodeAll(data, nil)
+		if errors.Is(err, zstd.ErrDecoderSizeExceeded) || errors.Is(err, zstd.ErrWindowSizeExceeded) {
+			return nil, fmt.Errorf("%w: %d bytes", ErrDecompressedLimit, maxSize)
+		}
+		return v, err
+	case CodecSnappy:
+		n, err := snappy.DecodedLen(data)
+		if err != nil {
+			return nil, err
+		}
+		if n > maxSize {
+			return nil, fmt.Errorf("%w: %d bytes", ErrDecompressedLimit, maxSize)
+		}
+		return snappy.Decode(nil, data)
+	case CodecGzip:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		v, err := io.ReadAll(io.LimitReader(r, int64(maxSize)+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(v) > maxSize {
+			return nil, fmt.Errorf("%w: %d bytes", ErrDecompressedLimit, maxSize)
+		}
+		return v, nil
+	}
+
+	return nil, fmt.Errorf("%w: %d", ErrUnknownCodec, codec)
+}
+
+// CompressedBinary is a binary, which is transparently compressed when stored, if its size reaches the threshold
+// set by SetBinaryCompression. It is stored with the user defined subtype 0x80, the first byte of the payload is the
+// CompressionCodec followed by the data. Binaries of the generic subtype, e.g. written by Binary, are decoded as well.
+// The subtype 0x80 is reserved for CompressedBinary, fields holding other user defined binaries must not be decoded
+// into it, because their first byte would be taken as codec.
+// The JSON representation is the uncompressed data as base64.
+type CompressedBinary []byte
+
+// MarshalJSON serializes the binary as a base64-encoded string or null if empty.
+func (b CompressedBinary) MarshalJSON() ([]byte, error) {
+	return Binary(b).MarshalJSON()
+}
+
+// UnmarshalJSON decodes a base64-encoded JSON string into the binary.
+func (b *CompressedBinary) UnmarshalJSON(data []byte) error {
+	return (*Binary)(b).UnmarshalJSON(data)
+}
+
+// MarshalBSONValue compresses the binary and serializes it into a BSON binary, an empty binary is rendered to BSON null.
+func (b CompressedBinary) MarshalBSONValue() (byte, []byte, error) {
+	if len(b) == 0 {
+		return byte(bson.TypeNull), nil, nil
+	}
+
+	codec := compressionCodec
+	if len(b) < compressionThreshold {
+		codec = CodecNone
+	}
+
+	data, err := compress(codec, b)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	payload := make([]byte, 0, len(data)+1)
+	payload = append(payload, byte(codec))
+	payload = append(payload, data...)
+
+	return marshalBsonValue(bson.Binary{Data: payload, Subtype: bson.TypeBinaryUserDefined})
+}
+
+// UnmarshalBSONValue decodes a BSON binary into the binary and decompresses it, BSON null results in an empty binary.
+func (b *CompressedBinary) UnmarshalBSONValue(typ byte, data []byte) error {
+	if isBsonNull(typ) {
+		*b = nil
+		return nil
+	}
+
+	if bson.Type(typ) != bson.TypeBinary {
+		return errors.New("wrong bson type expected binary")
+	}
+
+	prim := bson.Binary{}
+	if err := bson.UnmarshalValue(bson.TypeBinary, data, &prim); err != nil {
+		return err
+	}
+
+	switch prim.Subtype {
+	case bson.TypeBinaryGeneric:
+		*b = prim.Data
+		return nil
+	case bson.TypeBinaryUserDefined:
+	default:
+		return errors.New("wrong bson subtype expected user defined or generic")
+	}
+
+	if len(prim.Data) == 0 {
+		return errors.New("missing compression codec")
+	}
+
+	v, err := decompress(CompressionCodec(prim.Data[0]), prim.Data[1:])
+	if err != nil {
+		return err
+	}
+
+	*b = v
+	return nil
+}
@@ -0,0 +1,142 @@
+package types_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mbretter/go-mongodb/v2/types"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type CompressedTest struct {
+	Data types.CompressedBinary `bson:"data"`
+}
+
+func TestCompressedBinary_BSON(t *testing.T) {
+	defer types.SetBinaryCompression(types.CodecZstd, 1024)
+
+	large := bytes.Repeat([]byte("compressible "), 200)
+
+	tests := []struct {
+		name  string
+		codec types.CompressionCodec
+		data  []byte
+		want  types.CompressionCodec
+	}{
+		{"zstd", types.CodecZstd, large, types.CodecZstd},
+		{"snappy", types.CodecSnappy, large, types.CodecSnappy},
+		{"gzip", types.CodecGzip, large, types.CodecGzip},
+		{"none", types.CodecNone, large, types.CodecNone},
+		{"below threshold", types.CodecZstd, []byte("small"), types.CodecNone},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			types.SetBinaryCompression(test.codec, 1024)
+
+			data, err := bson.Marshal(CompressedTest{Data: test.data})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			sub, payload := bson.Raw(data).Lookup("data").Binary()
+			assert.Equal(t, bson.TypeBinaryUserDefined, sub)
+			assert.Equal(t, byte(test.want), payload[0])
+			if test.want != types.CodecNone {
+				assert.Less(t, len(payload), len(test.data))
+			}
+
+			// the codec is taken from the payload
+			types.SetBinaryCompression(types.CodecNone, 0)
+
+			var s CompressedTest
+			if assert.Nil(t, bson.Unmarshal(data, &s)) {
+				assert.Equal(t, types.CompressedBinary(test.data), s.Data)
+			}
+		})
+	}
+}
+
+func TestCompressedBinary_BSONNull(t *testing.T) {
+	data, err := bson.Marshal(CompressedTest{})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Equal(t, bson.TypeNull, bson.Raw(data).Lookup("data").Type)
+
+	s := CompressedTest{Data: []byte{1}}
+	if assert.Nil(t, bson.Unmarshal(data, &s)) {
+		assert.Nil(t, s.Data)
+	}
+}
+
+func TestCompressedBinary_UnmarshalGeneric(t *testing.T) {
+	data, _ := bson.Marshal(BinaryTest{Data: binaryTestData})
+
+	var s CompressedTest
+	if assert.Nil(t, bson.Unmarshal(data, &s)) {
+		assert.Equal(t, types.CompressedBinary(binaryTestData), s.Data)
+	}
+}
+
+func TestCompressedBinary_UnmarshalInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		val  interface{}
+		err  string
+	}{
+		{"type", "foo", "wrong bson type expected binary"},
+		{"subtype", bson.Binary{Subtype: bson.TypeBinaryMD5, Data: []byte{1}}, "wrong bson subtype expected user defined or generic"},
+		{"codec", bson.Binary{Subtype: bson.TypeBinaryUserDefined, Data: []byte{9, 1}}, "unknown compression codec: 9"},
+		{"empty", bson.Binary{Subtype: bson.TypeBinaryUserDefined, Data: []byte{}}, "missing compression codec"},
+		{"corrupt", bson.Binary{Subtype: bson.TypeBinaryUserDefined, Data: []byte{byte(types.CodecGzip), 1, 2}}, "unexpected EOF"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, _ := bson.Marshal(bson.D{{"data", test.val}})
+
+			var s CompressedTest
+			assert.ErrorContains(t, bson.Unmarshal(data, &s), test.err)
+		})
+	}
+}
+
+func TestCompressedBinary_DecompressedLimit(t *testing.T) {
+	defer types.SetBinaryCompression(types.CodecZstd, 1024)
+	defer types.SetMaxDecompressedSize(64 << 20)
+
+	large := bytes.Repeat([]byte{0}, 1<<20)
+
+	for _, codec := range []types.CompressionCodec{types.CodecZstd, types.CodecSnappy, types.CodecGzip} {
+		types.SetBinaryCompression(codec, 1024)
+
+		data, err := bson.Marshal(CompressedTest{Data: large})
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		var s CompressedTest
+		types.SetMaxDecompressedSize(1 << 20)
+		assert.Nil(t, bson.Unmarshal(data, &s), codec)
+		assert.Len(t, s.Data, 1<<20)
+
+		types.SetMaxDecompressedSize(1<<20 - 1)
+		err = bson.Unmarshal(data, &s)
+		assert.True(t, errors.Is(err, types.ErrDecompressedLimit), codec, err)
+	}
+}
+
+func TestCompressedBinary_JSON(t *testing.T) {
+	j, _ := json.Marshal(types.CompressedBinary(binaryTestData))
+	assert.Equal(t, `"`+binaryTestImage+`"`, string(j))
+
+	var b types.CompressedBinary
+	if assert.Nil(t, json.Unmarshal(j, &b)) {
+		assert.Equal(t, types.CompressedBinary(binaryTestData), b)
+	}
+}
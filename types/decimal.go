@@ -0,0 +1,451 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var (
+	// ErrDecimalOverflow is returned, if a value exceeds the range of a decimal128.
+	ErrDecimalOverflow = errors.New("decimal out of range")
+	// ErrDivisionByZero is returned by Div, if the divisor is zero.
+	ErrDivisionByZero = errors.New("division by zero")
+	// ErrInvalidDecimal is returned, if a decimal is NaN or infinite, e.g. converted from a bson.Decimal128.
+	ErrInvalidDecimal = errors.New("invalid decimal")
+)
+
+// maxDecimalDigits is the number of significant digits of a decimal128.
+const maxDecimalDigits = 34
+
+// RoundingMode defines how a Decimal is rounded.
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to the nearest neighbour, ties away from zero, e.g. 2.5 to 3 and -2.5 to -3.
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to the nearest neighbour, ties to the even neighbour, e.g. 2.5 to 2 and 3.5 to 4.
+	RoundHalfEven
+	// RoundDown rounds towards zero.
+	RoundDown
+	// RoundUp rounds away from zero.
+	RoundUp
+	// RoundFloor rounds towards negative infinity.
+	RoundFloor
+	// RoundCeiling rounds towards positive infinity.
+	RoundCeiling
+)
+
+var decimalJsonNumber = false
+
+// SetDecimalJsonNumber renders decimals as JSON numbers instead of JSON strings, which is the default,
+// because JavaScript parses JSON numbers into floats. Both representations are decoded regardless of this setting.
+func SetDecimalJsonNumber(enabled bool) {
+	decimalJsonNumber = enabled
+}
+
+// Decimal is an exact decimal number backed by a bson.Decimal128, it is stored as BSON decimal, so it can be
+// aggregated by the server without losing precision. The zero value is 0. Results of the arithmetic are rounded
+// to 34 significant digits like by the server, ErrDecimalOverflow is returned if the exponent exceeds the range of decimal128.
+// Decimals must not be compared using ==, because the same number can have different representations, e.g. 1.0 and 1.00,
+// use Cmp or Equal instead.
+// NaN and infinity are not created by this package, but can be converted from a bson.Decimal128, the arithmetic,
+// Cmp and the marshalling return ErrInvalidDecimal for them.
+type Decimal bson.Decimal128
+
+// NewDecimal returns the decimal value * 10^exp, e.g. NewDecimal(1999, -2) is 19.99.
+func NewDecimal(value int64, exp int) (Decimal, error) {
+	return decimalFromBig(big.NewInt(value), exp)
+}
+
+// DecimalFromFloat returns the decimal of the shortest representation of f, which is parsed back into f.
+func DecimalFromFloat(f float64) (Decimal, error) {
+	return ParseDecimal(strconv.FormatFloat(f, 'g', -1, 64))
+}
+
+// ParseDecimal parses a decimal number like "19.99", "-1e3" or "1.5E-2", NaN and infinity are rejected.
+func ParseDecimal(s string) (Decimal, error) {
+	d, err := bson.ParseDecimal128(strings.TrimSpace(s))
+	if err != nil {
+		return Decimal{}, err
+	}
+
+	if d.IsNaN() || d.IsInf() != 0 {
+		return Decimal{}, fmt.Errorf("%w: %s", ErrInvalidDecimal, s)
+	}
+
+	return Decimal(d), nil
+}
+
+// MustParseDecimal is like ParseDecimal, but panics if s can not be parsed, it is meant for constants.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+// Decimal128 returns the underlying bson.Decimal128.
+func (d Decimal) Decimal128() bson.Decimal128 {
+	return bson.Decimal128(d)
+}
+
+// isFinite reports whether d is neither NaN nor infinite.
+func (d Decimal) isFinite() bool {
+	return !bson.Decimal128(d).IsNaN() && bson.Decimal128(d).IsInf() == 0
+}
+
+// big returns the coefficient and the exponent of the decimal, the zero value is 0*10^0.
+// ErrInvalidDecimal is returned for NaN and infinity.
+func (d Decimal) big() (*big.Int, int, error) {
+	if bson.Decimal128(d).IsZero() {
+		return new(big.Int), 0, nil
+	}
+
+	if !d.isFinite() {
+		return nil, 0, fmt.Errorf("%w: %s", ErrInvalidDecimal, bson.Decimal128(d))
+	}
+
+	coef, exp, err := bson.Decimal128(d).BigInt()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return coef, exp, nil
+}
+
+// decimalFromBig creates the decimal coef*10^exp, rounding coef to the precision of decimal128.
+func decimalFromBig(coef *big.Int, exp int) (Decimal, error) {
+	if digits := len(new(big.Int).Abs(coef).String()); digits > maxDecimalDigits {
+		coef = roundQuo(coef, pow10(digits-maxDecimalDigits), RoundHalfEven)
+		exp += digits - maxDecimalDigits
+		return decimalFromBig(coef, exp)
+	}
+
+	if exp < bson.MinDecimal128Exp {
+		coef = roundQuo(coef, pow10(bson.MinDecimal128Exp-exp), RoundHalfEven)
+		exp = bson.MinDecimal128Exp
+	}
+
+	d, ok := bson.ParseDecimal128FromBigInt(coef, exp)
+	if !ok {
+		return Decimal{}, fmt.Errorf("%w: %se%d", ErrDecimalOverflow, coef, exp)
+	}
+
+	return Decimal(d), nil
+}
+
+// pow10 returns 10^n.
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// roundQuo returns num/den rounded using the given mode.
+func roundQuo(num *big.Int, den *big.Int, mode RoundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+
+	// sign of the exact result, q is truncated towards zero
+	sign := num.Sign() * den.Sign()
+	half := new(big.Int).Abs(r)
+	half.Lsh(half, 1)
+	cmpHalf := half.Cmp(new(big.Int).Abs(den))
+
+	var away bool
+	switch mode {
+	case RoundHalfUp:
+		away = cmpHalf >= 0
+	case RoundHalfEven:
+		away = cmpHalf > 0 || cmpHalf == 0 && q.Bit(0) == 1
+	case RoundDown:
+		away = false
+	case RoundUp:
+		away = true
+	case RoundFloor:
+		away = sign < 0
+	case RoundCeiling:
+		away = sign > 0
+	}
+
+	if away {
+		q.Add(q, big.NewInt(int64(sign)))
+	}
+
+	return q
+}
+
+// bigs returns the coefficients and the exponents of a and b.
+func bigs(a Decimal, b Decimal) (*big.Int, int, *big.Int, int, error) {
+	ca, ea, err := a.big()
+	if err != nil {
+		return nil, 0, nil, 0, err
+	}
+
+	cb, eb, err := b.big()
+	if err != nil {
+		return nil, 0, nil, 0, err
+	}
+
+	return ca, ea, cb, eb, nil
+}
+
+// align returns the coefficients of a and b scaled to the smaller exponent.
+func align(a Decimal, b Decimal) (*big.Int, *big.Int, int, error) {
+	ca, ea, cb, eb, err := bigs(a, b)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	exp := min(ea, eb)
+	ca.Mul(ca, pow10(ea-exp))
+	cb.Mul(cb, pow10(eb-exp))
+
+	return ca, cb, exp, nil
+}
+
+// Add returns d+o.
+func (d Decimal) Add(o Decimal) (Decimal, error) {
+	a, b, exp, err := align(d, o)
+	if err != nil {
+		return Decimal{}, err
+	}
+
+	return decimalFromBig(a.Add(a, b), exp)
+}
+
+// Sub returns d-o.
+func (d Decimal) Sub(o Decimal) (Decimal, error) {
+	a, b, exp, err := align(d, o)
+	if err != nil {
+		return Decimal{}, err
+	}
+
+	return decimalFromBig(a.Sub(a, b), exp)
+}
+
+// Mul returns d*o.
+func (d Decimal) Mul(o Decimal) (Decimal, error) {
+	ca, ea, cb, eb, err := bigs(d, o)
+	if err != nil {
+		return Decimal{}, err
+	}
+
+	return decimalFromBig(ca.Mul(ca, cb), ea+eb)
+}
+
+// Div returns d/o rounded to the given number of decimal places using mode, ErrDivisionByZero is returned if o is zero.
+func (d Decimal) Div(o Decimal, places int, mode RoundingMode) (Decimal, error) {
+	ca, ea, cb, eb, err := bigs(d, o)
+	if err != nil {
+		return Decimal{}, err
+	}
+
+	if cb.Sign() == 0 {
+		return Decimal{}, ErrDivisionByZero
+	}
+
+	// d/o = ca/cb * 10^(ea-eb), scaled to the exponent -places
+	if k := ea - eb + places; k >= 0 {
+		ca.Mul(ca, pow10(k))
+	} else {
+		cb.Mul(cb, pow10(-k))
+	}
+
+	return decimalFromBig(roundQuo(ca, cb, mode), -places)
+}
+
+// Round returns d rounded to the given number of decimal places using mode, the result has exactly
+// this number of decimal places, e.g. 1.5 rounded to 2 places is 1.50. Negative places round to tens, hundreds...
+func (d Decimal) Round(places int, mode RoundingMode) (Decimal, error) {
+	coef, exp, err := d.big()
+	if err != nil {
+		return Decimal{}, err
+	}
+
+	if exp >= -places {
+		return decimalFromBig(coef.Mul(coef, pow10(exp+places)), -places)
+	}
+
+	return decimalFromBig(roundQuo(coef, pow10(-places-exp), mode), -places)
+}
+
+// Neg returns -d, NaN is returned unchanged.
+func (d Decimal) Neg() Decimal {
+	coef, exp, err := d.big()
+	if err != nil {
+		if bson.Decimal128(d).IsNaN() {
+			return d
+		}
+
+		// the sign of infinity is the highest bit
+		h, l := bson.Decimal128(d).GetBytes()
+		return Decimal(bson.NewDecimal128(h^1<<63, l))
+	}
+
+	// the coefficient and the exponent of d are in range
+	n, _ := decimalFromBig(coef.Neg(coef), exp)
+	return n
+}
+
+// Abs returns the absolute value of d.
+func (d Decimal) Abs() Decimal {
+	if d.Sign() < 0 {
+		return d.Neg()
+	}
+
+	return d
+}
+
+// Cmp compares d and o and returns -1 if d < o, 0 if d == o and +1 if d > o.
+func (d Decimal) Cmp(o Decimal) (int, error) {
+	a, b, _, err := align(d, o)
+	if err != nil {
+		return 0, err
+	}
+
+	return a.Cmp(b), nil
+}
+
+// Equal reports whether d and o are the same number, regardless of their representation, NaN and infinity are
+// not equal to any decimal.
+func (d Decimal) Equal(o Decimal) bool {
+	c, err := d.Cmp(o)
+	return err == nil && c == 0
+}
+
+// Sign returns -1 if d < 0, 0 if d is zero or NaN and +1 if d > 0.
+func (d Decimal) Sign() int {
+	coef, _, err := d.big()
+	if err != nil {
+		return bson.Decimal128(d).IsInf()
+	}
+
+	return coef.Sign()
+}
+
+// IsZero reports whether d is zero.
+func (d Decimal) IsZero() bool {
+	return d.isFinite() && d.Sign() == 0
+}
+
+// Float64 returns the nearest float64 of d.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(bson.Decimal128(d).String(), 64)
+	return f
+}
+
+// String returns d in plain notation without exponent, e.g. "1000" instead of "1E+3", NaN and infinity are
+// rendered like by bson.Decimal128, e.g. "NaN" and "-Infinity".
+func (d Decimal) String() string {
+	coef, exp, err := d.big()
+	if err != nil {
+		return bson.Decimal128(d).String()
+	}
+
+	s := new(big.Int).Abs(coef).String()
+	if exp >= 0 {
+		s += strings.Repeat("0", exp)
+	} else {
+		if len(s) <= -exp {
+			s = strings.Repeat("0", -exp-len(s)+1) + s
+		}
+		s = s[:len(s)+exp] + "." + s[len(s)+exp:]
+	}
+
+	if coef.Sign() < 0 {
+		return "-" + s
+	}
+
+	return s
+}
+
+// MarshalJSON serializes the decimal as JSON string, or as JSON number if enabled by SetDecimalJsonNumber.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	if !d.isFinite() {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidDecimal, bson.Decimal128(d))
+	}
+
+	if decimalJsonNumber {
+		return []byte(d.String()), nil
+	}
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON deserializes a JSON string or number into the decimal, JSON null results in 0.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		*d = Decimal{}
+		return nil
+	}
+
+	if len(s) > 0 && s[0] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+
+	v, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+
+	*d = v
+	return nil
+}
+
+// MarshalBSONValue serializes the decimal to a BSON decimal.
+func (d Decimal) MarshalBSONValue() (byte, []byte, error) {
+	coef, exp, err := d.big()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	v, err := decimalFromBig(coef, exp)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return marshalBsonValue(bson.Decimal128(v))
+}
+
+// UnmarshalBSONValue deserializes a BSON decimal, int32, int64 or double into the decimal, BSON null results in 0.
+// Doubles are converted using their shortest representation.
+func (d *Decimal) UnmarshalBSONValue(typ byte, data []byte) error {
+	val := bson.RawValue{Type: bson.Type(typ), Value: data}
+
+	switch val.Type {
+	case bson.TypeNull, bson.TypeUndefined:
+		*d = Decimal{}
+	case bson.TypeDecimal128:
+		dec := val.Decimal128()
+		if dec.IsNaN() || dec.IsInf() != 0 {
+			return fmt.Errorf("%w: %s", ErrInvalidDecimal, dec)
+		}
+		*d = Decimal(dec)
+	// integers are always in range
+	case bson.TypeInt32:
+		*d, _ = NewDecimal(int64(val.Int32()), 0)
+	case bson.TypeInt64:
+		*d, _ = NewDecimal(val.Int64(), 0)
+	case bson.TypeDouble:
+		v, err := DecimalFromFloat(val.Double())
+		if err != nil {
+			return err
+		}
+		*d = v
+	default:
+		return fmt.Errorf("wrong bson type %s expected decimal", val.Type)
+	}
+
+	return nil
+}
@@ -0,0 +1,219 @@
+package types_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mbretter/go-mongodb/v2/types"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type DecimalTest struct {
+	Price types.Decimal `json:"price" bson:"price"`
+}
+
+func dec(s string) types.Decimal {
+	return types.MustParseDecimal(s)
+}
+
+// str returns the string of the decimal result, which must not fail.
+func str(t *testing.T) func(d types.Decimal, err error) string {
+	return func(d types.Decimal, err error) string {
+		assert.Nil(t, err)
+		return d.String()
+	}
+}
+
+func TestDecimal_Parse(t *testing.T) {
+	tests := []struct {
+		in  string
+		exp string
+	}{
+		{"19.99", "19.99"},
+		{"-0.05", "-0.05"},
+		{"1E+3", "1000"},
+		{"1.5e-3", "0.0015"},
+		{" 42 ", "42"},
+		{"0.10", "0.10"},
+	}
+
+	for _, test := range tests {
+		d, err := types.ParseDecimal(test.in)
+		assert.Nil(t, err, test.in)
+		assert.Equal(t, test.exp, d.String(), test.in)
+	}
+
+	for _, in := range []string{"", "abc", "NaN", "Infinity", "1.2.3"} {
+		_, err := types.ParseDecimal(in)
+		assert.NotNil(t, err, in)
+	}
+
+	assert.Panics(t, func() { types.MustParseDecimal("x") })
+	assert.Equal(t, "19.99", str(t)(types.NewDecimal(1999, -2)))
+
+	_, err := types.NewDecimal(1, 7000)
+	assert.True(t, errors.Is(err, types.ErrDecimalOverflow))
+	assert.Equal(t, "0", types.Decimal{}.String())
+}
+
+func TestDecimal_Arithmetic(t *testing.T) {
+	assert.Equal(t, "0.3", str(t)(dec("0.1").Add(dec("0.2"))))
+	assert.Equal(t, "-0.10", str(t)(dec("0.1").Sub(dec("0.20"))))
+	assert.Equal(t, "2.49750", str(t)(dec("19.98").Mul(dec("0.125"))))
+	assert.Equal(t, "0.33", str(t)(dec("1").Div(dec("3"), 2, types.RoundHalfUp)))
+	assert.Equal(t, "66.67", str(t)(dec("200").Div(dec("3"), 2, types.RoundHalfUp)))
+	assert.Equal(t, "-5", str(t)(dec("-10").Neg().Neg().Div(dec("2"), 0, types.RoundDown)))
+	assert.Equal(t, "10.5", dec("-10.5").Abs().String())
+	assert.Equal(t, "5", str(t)(types.Decimal{}.Add(dec("5"))))
+
+	_, err := dec("1").Div(types.Decimal{}, 2, types.RoundHalfUp)
+	assert.True(t, errors.Is(err, types.ErrDivisionByZero))
+
+	// rounded to 34 significant digits
+	assert.Equal(t, "1234567890123456789012345678901234",
+		str(t)(dec("1234567890123456789012345678901234").Add(dec("0.5"))))
+
+	// exceeding the range of decimal128
+	_, err = dec("9.999999999999999999999999999999999E+6144").Mul(dec("10"))
+	assert.True(t, errors.Is(err, types.ErrDecimalOverflow))
+	_, err = dec("9.999999999999999999999999999999999E+6144").Add(dec("9.999999999999999999999999999999999E+6144"))
+	assert.True(t, errors.Is(err, types.ErrDecimalOverflow))
+	_, err = dec("1E+6000").Div(dec("1E-6000"), 0, types.RoundHalfUp)
+	assert.True(t, errors.Is(err, types.ErrDecimalOverflow))
+}
+
+func TestDecimal_Round(t *testing.T) {
+	tests := []struct {
+		in   string
+		mode types.RoundingMode
+		exp  []string // rounded to 0 places of in, -in
+	}{
+		{"2.5", types.RoundHalfUp, []string{"3", "-3"}},
+		{"2.5", types.RoundHalfEven, []string{"2", "-2"}},
+		{"3.5", types.RoundHalfEven, []string{"4", "-4"}},
+		{"2.51", types.RoundHalfEven, []string{"3", "-3"}},
+		{"2.4", types.RoundHalfUp, []string{"2", "-2"}},
+		{"2.9", types.RoundDown, []string{"2", "-2"}},
+		{"2.1", types.RoundUp, []string{"3", "-3"}},
+		{"2.1", types.RoundFloor, []string{"2", "-3"}},
+		{"2.1", types.RoundCeiling, []string{"3", "-2"}},
+		{"2", types.RoundUp, []string{"2", "-2"}},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.exp[0], str(t)(dec(test.in).Round(0, test.mode)), test.in)
+		assert.Equal(t, test.exp[1], str(t)(dec(test.in).Neg().Round(0, test.mode)), test.in)
+	}
+
+	assert.Equal(t, "1.50", str(t)(dec("1.5").Round(2, types.RoundHalfUp)))
+	assert.Equal(t, "1.235", str(t)(dec("1.2345").Round(3, types.RoundHalfUp)))
+	assert.Equal(t, "1200", str(t)(dec("1249").Round(-2, types.RoundHalfUp)))
+}
+
+// cmp returns the result of the comparison, which must not fail.
+func cmp(t *testing.T) func(c int, err error) int {
+	return func(c int, err error) int {
+		assert.Nil(t, err)
+		return c
+	}
+}
+
+func TestDecimal_Cmp(t *testing.T) {
+	assert.Equal(t, 0, cmp(t)(dec("1.0").Cmp(dec("1.00"))))
+	assert.True(t, dec("1.0").Equal(dec("1")))
+	assert.Equal(t, -1, cmp(t)(dec("-2").Cmp(dec("1"))))
+	assert.Equal(t, 1, cmp(t)(dec("0.01").Cmp(types.Decimal{})))
+	assert.True(t, types.Decimal{}.IsZero())
+	assert.True(t, dec("0.00").IsZero())
+	assert.Equal(t, -1, dec("-0.1").Sign())
+	assert.Equal(t, 19.99, dec("19.99").Float64())
+}
+
+func TestDecimal_Invalid(t *testing.T) {
+	nan := types.Decimal(bson.NewDecimal128(0x7c00000000000000, 0))
+	inf := types.Decimal(bson.NewDecimal128(0x7800000000000000, 0))
+
+	for _, d := range []types.Decimal{nan, inf} {
+		_, err := d.Add(dec("1"))
+		assert.True(t, errors.Is(err, types.ErrInvalidDecimal))
+		_, err = dec("1").Sub(d)
+		assert.True(t, errors.Is(err, types.ErrInvalidDecimal))
+		_, err = d.Mul(dec("1"))
+		assert.True(t, errors.Is(err, types.ErrInvalidDecimal))
+		_, err = dec("1").Div(d, 2, types.RoundHalfUp)
+		assert.True(t, errors.Is(err, types.ErrInvalidDecimal))
+		_, err = d.Round(2, types.RoundHalfUp)
+		assert.True(t, errors.Is(err, types.ErrInvalidDecimal))
+		_, err = d.Cmp(dec("1"))
+		assert.True(t, errors.Is(err, types.ErrInvalidDecimal))
+		_, err = json.Marshal(d)
+		assert.True(t, errors.Is(err, types.ErrInvalidDecimal))
+		_, err = bson.Marshal(DecimalTest{Price: d})
+		assert.True(t, errors.Is(err, types.ErrInvalidDecimal))
+
+		assert.False(t, d.Equal(d))
+		assert.False(t, d.IsZero())
+	}
+
+	assert.Equal(t, "NaN", nan.String())
+	assert.Equal(t, "NaN", nan.Neg().String())
+	assert.Equal(t, 0, nan.Sign())
+	assert.Equal(t, "Infinity", inf.String())
+	assert.Equal(t, "-Infinity", inf.Neg().String())
+	assert.Equal(t, "Infinity", inf.Neg().Abs().String())
+	assert.Equal(t, -1, inf.Neg().Sign())
+}
+
+func TestDecimal_Json(t *testing.T) {
+	j, _ := json.Marshal(DecimalTest{Price: dec("19.90")})
+	assert.Equal(t, `{"price":"19.90"}`, string(j))
+
+	types.SetDecimalJsonNumber(true)
+	defer types.SetDecimalJsonNumber(false)
+
+	j, _ = json.Marshal(DecimalTest{Price: dec("19.90")})
+	assert.Equal(t, `{"price":19.90}`, string(j))
+
+	for _, in := range []string{`{"price":"19.90"}`, `{"price":19.90}`, `{"price":1.990e1}`} {
+		var s DecimalTest
+		assert.Nil(t, json.Unmarshal([]byte(in), &s), in)
+		assert.True(t, dec("19.9").Equal(s.Price), in)
+	}
+
+	s := DecimalTest{Price: dec("1")}
+	assert.Nil(t, json.Unmarshal([]byte(`{"price":null}`), &s))
+	assert.True(t, s.Price.IsZero())
+	assert.NotNil(t, json.Unmarshal([]byte(`{"price":"abc"}`), &s))
+}
+
+func TestDecimal_Bson(t *testing.T) {
+	s := DecimalTest{Price: dec("19.90")}
+
+	b, _ := bson.Marshal(s)
+	d128, _ := bson.ParseDecimal128("19.90")
+	exp, _ := bson.Marshal(bson.D{{"price", d128}})
+	assert.Equal(t, exp, b)
+
+	var fromBson DecimalTest
+	assert.Nil(t, bson.Unmarshal(b, &fromBson))
+	assert.Equal(t, s, fromBson)
+
+	b, _ = bson.Marshal(DecimalTest{})
+	zero, _ := bson.ParseDecimal128("0")
+	exp, _ = bson.Marshal(bson.D{{"price", zero}})
+	assert.Equal(t, exp, b)
+
+	for _, v := range []any{int32(42), int64(42), 42.0} {
+		b, _ = bson.Marshal(bson.D{{"price", v}})
+		assert.Nil(t, bson.Unmarshal(b, &fromBson))
+		assert.Equal(t, "42", fromBson.Price.String())
+	}
+
+	b, _ = bson.Marshal(bson.D{{"price", bson.NewDecimal128(0x7c00000000000000, 0)}}) // NaN
+	assert.NotNil(t, bson.Unmarshal(b, &fromBson))
+
+	b, _ = bson.Marshal(bson.D{{"price", "42"}})
+	assert.NotNil(t, bson.Unmarshal(b, &fromBson))
+}
@@ -0,0 +1,570 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ErrInvalidGeometry is returned, if a GeoJSON geometry is not valid, e.g. a ring of a polygon is not closed.
+var ErrInvalidGeometry = errors.New("invalid geometry")
+
+// GeoJSON geometry types
+const (
+	GeometryPoint          = "Point"
+	GeometryLineString     = "LineString"
+	GeometryPolygon        = "Polygon"
+	GeometryMultiPolygon   = "MultiPolygon"
+	GeometryCollectionType = "GeometryCollection"
+)
+
+// Geometry is implemented by the GeoJSON types, it can be used with the geospatial query operators.
+// JSON null and BSON null are decoded into the zero value of a geometry. Except of the Point, the zero values are
+// invalid and can not be encoded, use pointers for optional geometries.
+type Geometry interface {
+	GeometryType() string
+	Validate() error
+}
+
+// Position is a GeoJSON position, longitude first, followed by latitude. Additional elements of decoded positions,
+// like the altitude, are ignored.
+type Position [2]float64
+
+// setPosition sets the longitude and latitude of the decoded elements, additional elements are ignored.
+func (p *Position) setPosition(values []float64) error {
+	if len(values) < 2 {
+		return fmt.Errorf("%w: position requires at least 2 elements", ErrInvalidGeometry)
+	}
+
+	*p = Position{values[0], values[1]}
+	return nil
+}
+
+// UnmarshalJSON deserializes a JSON array of at least two numbers into the position.
+func (p *Position) UnmarshalJSON(data []byte) error {
+	var values []float64
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	return p.setPosition(values)
+}
+
+// UnmarshalBSONValue deserializes a BSON array of at least two numbers into the position.
+func (p *Position) UnmarshalBSONValue(typ byte, data []byte) error {
+	if bson.Type(typ) != bson.TypeArray {
+		return errors.New("wrong bson type expected array")
+	}
+
+	elems, err := bson.RawArray(data).Values()
+	if err != nil {
+		return err
+	}
+
+	values := make([]float64, len(elems))
+	for i, elem := range elems {
+		f, ok := elem.AsFloat64OK()
+		if !ok {
+			return fmt.Errorf("wrong bson type %s of position element %d", elem.Type, i)
+		}
+		values[i] = f
+	}
+
+	return p.setPosition(values)
+}
+
+// Lng returns the longitude.
+func (p Position) Lng() float64 {
+	return p[0]
+}
+
+// Lat returns the latitude.
+func (p Position) Lat() float64 {
+	return p[1]
+}
+
+// Validate returns ErrInvalidGeometry, if the longitude is not within -180 and 180 or the latitude is not within -90 and 90.
+func (p Position) Validate() error {
+	if math.IsNaN(p[0]) || p[0] < -180 || p[0] > 180 {
+		return fmt.Errorf("%w: longitude %v out of range", ErrInvalidGeometry, p[0])
+	}
+
+	if math.IsNaN(p[1]) || p[1] < -90 || p[1] > 90 {
+		return fmt.Errorf("%w: latitude %v out of range", ErrInvalidGeometry, p[1])
+	}
+
+	return nil
+}
+
+// Point is a GeoJSON point. The zero point is the valid position 0,0 and is encoded as such, use a *Point for
+// optional locations.
+type Point struct {
+	Coordinates Position
+}
+
+// NewPoint returns the point at the given longitude and latitude.
+func NewPoint(lng float64, lat float64) Point {
+	return Point{Coordinates: Position{lng, lat}}
+}
+
+// GeometryType returns "Point".
+func (p Point) GeometryType() string {
+	return GeometryPoint
+}
+
+// Validate validates the position of the point.
+func (p Point) Validate() error {
+	return p.Coordinates.Validate()
+}
+
+// LineString is a GeoJSON line string of at least two positions.
+type LineString struct {
+	Coordinates []Position
+}
+
+// GeometryType returns "LineString".
+func (l LineString) GeometryType() string {
+	return GeometryLineString
+}
+
+// Validate returns ErrInvalidGeometry, if the line string has less than two positions or a position is out of range.
+func (l LineString) Validate() error {
+	if len(l.Coordinates) < 2 {
+		return fmt.Errorf("%w: line string requires at least 2 positions", ErrInvalidGeometry)
+	}
+
+	return validatePositions(l.Coordinates)
+}
+
+// Polygon is a GeoJSON polygon, the first ring is the exterior ring, any others are holes.
+// Each ring is closed, the last position equals the first one.
+type Polygon struct {
+	Coordinates [][]Position
+}
+
+// NewPolygon returns a polygon of the given rings, rings not being closed are closed by appending the first position.
+func NewPolygon(rings ...[]Position) Polygon {
+	p := Polygon{Coordinates: make([][]Position, len(rings))}
+	for i, ring := range rings {
+		if len(ring) > 0 && ring[0] != ring[len(ring)-1] {
+			ring = append(ring[:len(ring):len(ring)], ring[0])
+		}
+		p.Coordinates[i] = ring
+	}
+
+	return p
+}
+
+// GeometryType returns "Polygon".
+func (p Polygon) GeometryType() string {
+	return GeometryPolygon
+}
+
+// Validate returns ErrInvalidGeometry, if the polygon has no ring, a ring has less than four positions,
+// a ring is not closed or a position is out of range.
+func (p Polygon) Validate() error {
+	if len(p.Coordinates) == 0 {
+		return fmt.Errorf("%w: polygon requires at least 1 ring", ErrInvalidGeometry)
+	}
+
+	for i, ring := range p.Coordinates {
+		if len(ring) < 4 {
+			return fmt.Errorf("%w: ring %d requires at least 4 positions", ErrInvalidGeometry, i)
+		}
+
+		if ring[0] != ring[len(ring)-1] {
+			return fmt.Errorf("%w: ring %d is not closed", ErrInvalidGeometry, i)
+		}
+
+		if err := validatePositions(ring); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MultiPolygon is a GeoJSON multi polygon.
+type MultiPolygon struct {
+	Coordinates [][][]Position
+}
+
+// GeometryType returns "MultiPolygon".
+func (m MultiPolygon) GeometryType() string {
+	return GeometryMultiPolygon
+}
+
+// Validate validates each polygon, it returns ErrInvalidGeometry if there is no polygon.
+func (m MultiPolygon) Validate() error {
+	if len(m.Coordinates) == 0 {
+		return fmt.Errorf("%w: multi polygon requires at least 1 polygon", ErrInvalidGeometry)
+	}
+
+	for _, rings := range m.Coordinates {
+		if err := (Polygon{Coordinates: rings}).Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GeometryCollection is a GeoJSON geometry collection of any of the geometries of this package.
+type GeometryCollection struct {
+	Geometries []Geometry
+}
+
+// GeometryType returns "GeometryCollection".
+func (c GeometryCollection) GeometryType() string {
+	return GeometryCollectionType
+}
+
+// Validate validates each geometry of the collection.
+func (c GeometryCollection) Validate() error {
+	for _, g := range c.Geometries {
+		if g == nil {
+			return fmt.Errorf("%w: nil geometry", ErrInvalidGeometry)
+		}
+
+		if err := g.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validatePositions validates each position.
+func validatePositions(positions []Position) error {
+	for _, p := range positions {
+		if err := p.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// geometry is the GeoJSON object of the geometries having coordinates.
+type geometry[C any] struct {
+	Type        string `bson:"type" json:"type"`
+	Coordinates C      `bson:"coordinates" json:"coordinates"`
+}
+
+// geometryCollection is the GeoJSON object of a geometry collection, the geometries are decoded into R.
+type geometryCollection[R any] struct {
+	Type       string `bson:"type" json:"type"`
+	Geometries []R    `bson:"geometries" json:"geometries"`
+}
+
+// marshalGeometry validates g and encodes it with the coordinates using enc, which is json.Marshal or bson.Marshal.
+func marshalGeometry[C any](g Geometry, coordinates C, enc func(any) ([]byte, error)) ([]byte, error) {
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+
+	return enc(geometry[C]{Type: g.GeometryType(), Coordinates: coordinates})
+}
+
+// unmarshalGeometry decodes the data using dec, which is json.Unmarshal or bson.Unmarshal, into the coordinates
+// and checks the type.
+func unmarshalGeometry[C any](typ string, data []byte, dec func([]byte, any) error, coordinates *C) error {
+	var g geometry[C]
+	if err := dec(data, &g); err != nil {
+		return err
+	}
+
+	if g.Type != typ {
+		return fmt.Errorf("%w: type %q expected %q", ErrInvalidGeometry, g.Type, typ)
+	}
+
+	*coordinates = g.Coordinates
+	return nil
+}
+
+// decodeGeometry decodes the data of a geometry of any type using dec.
+func decodeGeometry(data []byte, dec func([]byte, any) error) (Geometry, error) {
+	var head struct {
+		Type string `bson:"type" json:"type"`
+	}
+	if err := dec(data, &head); err != nil {
+		return nil, err
+	}
+
+	var g Geometry
+	var err error
+	switch head.Type {
+	case GeometryPoint:
+		var p Point
+		err = dec(data, &p)
+		g = p
+	case GeometryLineString:
+		var l LineString
+		err = dec(data, &l)
+		g = l
+	case GeometryPolygon:
+		var p Polygon
+		err = dec(data, &p)
+		g = p
+	case GeometryMultiPolygon:
+		var m MultiPolygon
+		err = dec(data, &m)
+		g = m
+	case GeometryCollectionType:
+		var c GeometryCollection
+		err = dec(data, &c)
+		g = c
+	default:
+		return nil, fmt.Errorf("%w: unsupported type %q", ErrInvalidGeometry, head.Type)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// MarshalJSON validates the point and serializes it as GeoJSON.
+func (p Point) MarshalJSON() ([]byte, error) {
+	return marshalGeometry(p, p.Coordinates, json.Marshal)
+}
+
+// UnmarshalJSON deserializes a GeoJSON point and validates it, JSON null results in a zero point.
+func (p *Point) UnmarshalJSON(data []byte) error {
+	*p = Point{}
+	if string(data) == "null" {
+		return nil
+	}
+
+	if err := unmarshalGeometry(GeometryPoint, data, json.Unmarshal, &p.Coordinates); err != nil {
+		return err
+	}
+
+	return p.Validate()
+}
+
+// MarshalBSON validates the point and serializes it as GeoJSON document.
+func (p Point) MarshalBSON() ([]byte, error) {
+	return marshalGeometry(p, p.Coordinates, bson.Marshal)
+}
+
+// UnmarshalBSON deserializes a GeoJSON document into the point and validates it, BSON null results in a zero point.
+func (p *Point) UnmarshalBSON(data []byte) error {
+	// BSON null is passed as empty value
+	if len(data) == 0 {
+		*p = Point{}
+		return nil
+	}
+
+	if err := unmarshalGeometry(GeometryPoint, data, bson.Unmarshal, &p.Coordinates); err != nil {
+		return err
+	}
+
+	return p.Validate()
+}
+
+// MarshalJSON validates the line string and serializes it as GeoJSON.
+func (l LineString) MarshalJSON() ([]byte, error) {
+	return marshalGeometry(l, l.Coordinates, json.Marshal)
+}
+
+// UnmarshalJSON deserializes a GeoJSON line string and validates it, JSON null results in an empty line string.
+func (l *LineString) UnmarshalJSON(data []byte) error {
+	*l = LineString{}
+	if string(data) == "null" {
+		return nil
+	}
+
+	if err := unmarshalGeometry(GeometryLineString, data, json.Unmarshal, &l.Coordinates); err != nil {
+		return err
+	}
+
+	return l.Validate()
+}
+
+// MarshalBSON validates the line string and serializes it as GeoJSON document.
+func (l LineString) MarshalBSON() ([]byte, error) {
+	return marshalGeometry(l, l.Coordinates, bson.Marshal)
+}
+
+// UnmarshalBSON deserializes a GeoJSON document into the line string and validates it,
+// BSON null results in an empty line string.
+func (l *LineString) UnmarshalBSON(data []byte) error {
+	// BSON null is passed as empty value
+	if len(data) == 0 {
+		*l = LineString{}
+		return nil
+	}
+
+	if err := unmarshalGeometry(GeometryLineString, data, bson.Unmarshal, &l.Coordinates); err != nil {
+		return err
+	}
+
+	return l.Validate()
+}
+
+// MarshalJSON validates the polygon and serializes it as GeoJSON.
+func (p Polygon) MarshalJSON() ([]byte, error) {
+	return marshalGeometry(p, p.Coordinates, json.Marshal)
+}
+
+// UnmarshalJSON deserializes a GeoJSON polygon and validates it, JSON null results in an empty polygon.
+func (p *Polygon) UnmarshalJSON(data []byte) error {
+	*p = Polygon{}
+	if string(data) == "null" {
+		return nil
+	}
+
+	if err := unmarshalGeometry(GeometryPolygon, data, json.Unmarshal, &p.Coordinates); err != nil {
+		return err
+	}
+
+	return p.Validate()
+}
+
+// MarshalBSON validates the polygon and serializes it as GeoJSON document.
+func (p Polygon) MarshalBSON() ([]byte, error) {
+	return marshalGeometry(p, p.Coordinates, bson.Marshal)
+}
+
+// UnmarshalBSON deserializes a GeoJSON document into the polygon and validates it,
+// BSON null results in an empty polygon.
+func (p *Polygon) UnmarshalBSON(data []byte) error {
+	// BSON null is passed as empty value
+	if len(data) == 0 {
+		*p = Polygon{}
+		return nil
+	}
+
+	if err := unmarshalGeometry(GeometryPolygon, data, bson.Unmarshal, &p.Coordinates); err != nil {
+		return err
+	}
+
+	return p.Validate()
+}
+
+// MarshalJSON validates the multi polygon and serializes it as GeoJSON.
+func (m MultiPolygon) MarshalJSON() ([]byte, error) {
+	return marshalGeometry(m, m.Coordinates, json.Marshal)
+}
+
+// UnmarshalJSON deserializes a GeoJSON multi polygon and validates it, JSON null results in an empty multi polygon.
+func (m *MultiPolygon) UnmarshalJSON(data []byte) error {
+	*m = MultiPolygon{}
+	if string(data) == "null" {
+		return nil
+	}
+
+	if err := unmarshalGeometry(GeometryMultiPolygon, data, json.Unmarshal, &m.Coordinates); err != nil {
+		return err
+	}
+
+	return m.Validate()
+}
+
+// MarshalBSON validates the multi polygon and serializes it as GeoJSON document.
+func (m MultiPolygon) MarshalBSON() ([]byte, error) {
+	return marshalGeometry(m, m.Coordinates, bson.Marshal)
+}
+
+// UnmarshalBSON deserializes a GeoJSON document into the multi polygon and validates it,
+// BSON null results in an empty multi polygon.
+func (m *MultiPolygon) UnmarshalBSON(data []byte) error {
+	// BSON null is passed as empty value
+	if len(data) == 0 {
+		*m = MultiPolygon{}
+		return nil
+	}
+
+	if err := unmarshalGeometry(GeometryMultiPolygon, data, bson.Unmarshal, &m.Coordinates); err != nil {
+		return err
+	}
+
+	return m.Validate()
+}
+
+// MarshalJSON validates the geometry collection and serializes it as GeoJSON.
+func (c GeometryCollection) MarshalJSON() ([]byte, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(geometryCollection[Geometry]{Type: GeometryCollectionType, Geometries: c.Geometries})
+}
+
+// UnmarshalJSON deserializes a GeoJSON geometry collection and validates it,
+// JSON null results in an empty geometry collection.
+func (c *GeometryCollection) UnmarshalJSON(data []byte) error {
+	*c = GeometryCollection{}
+	if string(data) == "null" {
+		return nil
+	}
+
+	var v geometryCollection[json.RawMessage]
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	geometries, err := decodeGeometries(v.Type, v.Geometries, json.Unmarshal)
+	if err != nil {
+		return err
+	}
+
+	c.Geometries = geometries
+	return nil
+}
+
+// MarshalBSON validates the geometry collection and serializes it as GeoJSON document.
+func (c GeometryCollection) MarshalBSON() ([]byte, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	return bson.Marshal(geometryCollection[Geometry]{Type: GeometryCollectionType, Geometries: c.Geometries})
+}
+
+// UnmarshalBSON deserializes a GeoJSON document into the geometry collection and validates it,
+// BSON null results in an empty geometry collection.
+func (c *GeometryCollection) UnmarshalBSON(data []byte) error {
+	// BSON null is passed as empty value
+	if len(data) == 0 {
+		*c = GeometryCollection{}
+		return nil
+	}
+
+	var v geometryCollection[bson.Raw]
+	if err := bson.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	geometries, err := decodeGeometries(v.Type, v.Geometries, bson.Unmarshal)
+	if err != nil {
+		return err
+	}
+
+	c.Geometries = geometries
+	return nil
+}
+
+// decodeGeometries decodes the raw geometries of a geometry collection using dec, they are validated by their unmarshalers.
+func decodeGeometries[R ~[]byte](typ string, raw []R, dec func([]byte, any) error) ([]Geometry, error) {
+	if typ != GeometryCollectionType {
+		return nil, fmt.Errorf("%w: type %q expected %q", ErrInvalidGeometry, typ, GeometryCollectionType)
+	}
+
+	geometries := make([]Geometry, 0, len(raw))
+	for _, data := range raw {
+		g, err := decodeGeometry(data, dec)
+		if err != nil {
+			return nil, err
+		}
+
+		geometries = append(geometries, g)
+	}
+
+	return geometries, nil
+}
@@ -0,0 +1,221 @@
+package types_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/mbretter/go-mongodb/v2/types"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var geoTestSquare = []types.Position{{16, 48}, {17, 48}, {17, 49}, {16, 49}}
+
+func TestGeo_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		geometry types.Geometry
+		err      string
+	}{
+		{"point", types.NewPoint(16.37, 48.21), ""},
+		{"longitude", types.NewPoint(180.5, 0), "invalid geometry: longitude 180.5 out of range"},
+		{"latitude", types.NewPoint(0, -91), "invalid geometry: latitude -91 out of range"},
+		{"nan", types.NewPoint(math.NaN(), 0), "longitude NaN out of range"},
+		{"line string", types.LineString{Coordinates: []types.Position{{0, 0}, {1, 1}}}, ""},
+		{"short line string", types.LineString{Coordinates: []types.Position{{0, 0}}}, "line string requires at least 2 positions"},
+		{"polygon", types.NewPolygon(geoTestSquare), ""},
+		{"open ring", types.Polygon{Coordinates: [][]types.Position{append(geoTestSquare, types.Position{16, 48.5})}}, "ring 0 is not closed"},
+		{"short ring", types.Polygon{Coordinates: [][]types.Position{{{0, 0}, {1, 1}, {0, 0}}}}, "ring 0 requires at least 4 positions"},
+		{"no ring", types.Polygon{}, "polygon requires at least 1 ring"},
+		{"multi polygon", types.MultiPolygon{Coordinates: [][][]types.Position{types.NewPolygon(geoTestSquare).Coordinates}}, ""},
+		{"empty multi polygon", types.MultiPolygon{}, "multi polygon requires at least 1 polygon"},
+		{"collection", types.GeometryCollection{Geometries: []types.Geometry{types.NewPoint(1, 2), types.NewPolygon(geoTestSquare)}}, ""},
+		{"invalid collection", types.GeometryCollection{Geometries: []types.Geometry{types.NewPoint(1, 200)}}, "latitude 200 out of range"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.geometry.Validate()
+			if len(test.err) == 0 {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorIs(t, err, types.ErrInvalidGeometry)
+				assert.ErrorContains(t, err, test.err)
+			}
+		})
+	}
+}
+
+func TestNewPolygon(t *testing.T) {
+	p := types.NewPolygon(geoTestSquare)
+
+	assert.Equal(t, append(geoTestSquare[:4:4], geoTestSquare[0]), p.Coordinates[0])
+	assert.Len(t, geoTestSquare, 4)
+
+	// closed rings are kept
+	assert.Equal(t, p, types.NewPolygon(p.Coordinates[0]))
+}
+
+func TestPosition(t *testing.T) {
+	p := types.NewPoint(16.37, 48.21)
+
+	assert.Equal(t, 16.37, p.Coordinates.Lng())
+	assert.Equal(t, 48.21, p.Coordinates.Lat())
+}
+
+type GeoTest struct {
+	Location types.Point              `bson:"location" json:"location"`
+	Route    types.LineString         `bson:"route" json:"route"`
+	Area     types.Polygon            `bson:"area" json:"area"`
+	Regions  types.MultiPolygon       `bson:"regions" json:"regions"`
+	Shapes   types.GeometryCollection `bson:"shapes" json:"shapes"`
+}
+
+func geoTestDoc() GeoTest {
+	square := types.NewPolygon(geoTestSquare)
+
+	return GeoTest{
+		Location: types.NewPoint(16.37, 48.21),
+		Route:    types.LineString{Coordinates: []types.Position{{16, 48}, {16.5, 48.5}}},
+		Area:     square,
+		Regions:  types.MultiPolygon{Coordinates: [][][]types.Position{square.Coordinates}},
+		Shapes:   types.GeometryCollection{Geometries: []types.Geometry{types.NewPoint(1, 2), square}},
+	}
+}
+
+func TestGeo_BSON(t *testing.T) {
+	s := geoTestDoc()
+
+	data, err := bson.Marshal(s)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	raw := bson.Raw(data)
+	assert.Equal(t, "Point", raw.Lookup("location", "type").StringValue())
+	assert.Equal(t, 48.21, raw.Lookup("location", "coordinates", "1").Double())
+	assert.Equal(t, "GeometryCollection", raw.Lookup("shapes", "type").StringValue())
+	assert.Equal(t, "Polygon", raw.Lookup("shapes", "geometries", "1", "type").StringValue())
+
+	var d GeoTest
+	if assert.Nil(t, bson.Unmarshal(data, &d)) {
+		assert.Equal(t, s, d)
+	}
+}
+
+func TestGeo_JSON(t *testing.T) {
+	j, err := json.Marshal(types.NewPoint(16.37, 48.21))
+	if assert.Nil(t, err) {
+		assert.Equal(t, `{"type":"Point","coordinates":[16.37,48.21]}`, string(j))
+	}
+
+	s := geoTestDoc()
+
+	j, err = json.Marshal(s)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	var d GeoTest
+	if assert.Nil(t, json.Unmarshal(j, &d)) {
+		assert.Equal(t, s, d)
+	}
+}
+
+func TestGeo_JSONNull(t *testing.T) {
+	d := geoTestDoc()
+
+	err := json.Unmarshal([]byte(`{"location":null,"route":null,"area":null,"regions":null,"shapes":null}`), &d)
+	if assert.Nil(t, err) {
+		assert.Equal(t, GeoTest{}, d)
+	}
+}
+
+func TestGeo_BSONNull(t *testing.T) {
+	data, _ := bson.Marshal(bson.D{{"location", nil}, {"route", nil}, {"area", nil}, {"regions", nil}, {"shapes", nil}})
+
+	d := geoTestDoc()
+	if assert.Nil(t, bson.Unmarshal(data, &d)) {
+		assert.Equal(t, GeoTest{}, d)
+	}
+}
+
+func TestGeo_Altitude(t *testing.T) {
+	exp := GeoTest{
+		Location: types.NewPoint(16.37, 48.21),
+		Route:    types.LineString{Coordinates: []types.Position{{16, 48}, {16.5, 48.5}}},
+	}
+	j := `{"location":{"type":"Point","coordinates":[16.37,48.21,171.5]},` +
+		`"route":{"type":"LineString","coordinates":[[16,48,100],[16.5,48.5]]}}`
+
+	var d GeoTest
+	if assert.Nil(t, json.Unmarshal([]byte(j), &d)) {
+		assert.Equal(t, exp, d)
+	}
+
+	var doc bson.D
+	if !assert.Nil(t, bson.UnmarshalExtJSON([]byte(j), false, &doc)) {
+		return
+	}
+	data, _ := bson.Marshal(doc)
+
+	d = GeoTest{}
+	if assert.Nil(t, bson.Unmarshal(data, &d)) {
+		assert.Equal(t, exp, d)
+	}
+}
+
+func TestGeo_ZeroPoint(t *testing.T) {
+	j, err := json.Marshal(types.Point{})
+	if assert.Nil(t, err) {
+		assert.Equal(t, `{"type":"Point","coordinates":[0,0]}`, string(j))
+	}
+
+	_, err = json.Marshal(types.LineString{})
+	assert.ErrorIs(t, err, types.ErrInvalidGeometry)
+}
+
+func TestGeo_MarshalInvalid(t *testing.T) {
+	_, err := bson.Marshal(GeoTest{Location: types.NewPoint(200, 0)})
+	assert.ErrorIs(t, err, types.ErrInvalidGeometry)
+
+	_, err = json.Marshal(types.NewPoint(0, 100))
+	assert.ErrorIs(t, err, types.ErrInvalidGeometry)
+
+	_, err = json.Marshal(types.GeometryCollection{Geometries: []types.Geometry{nil}})
+	assert.ErrorContains(t, err, "nil geometry")
+}
+
+func TestGeo_UnmarshalInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		err  string
+	}{
+		{"type", `{"location":{"type":"LineString","coordinates":[1,2]}}`, `type "LineString" expected "Point"`},
+		{"range", `{"location":{"type":"Point","coordinates":[1,200]}}`, "latitude 200 out of range"},
+		{"short position", `{"location":{"type":"Point","coordinates":[1]}}`, "position requires at least 2 elements"},
+		{"open ring", `{"area":{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,1]]]}}`, "ring 0 is not closed"},
+		{"collection type", `{"shapes":{"type":"Point","geometries":[]}}`, `type "Point" expected "GeometryCollection"`},
+		{"collection member", `{"shapes":{"type":"GeometryCollection","geometries":[{"type":"Circle"}]}}`, `unsupported type "Circle"`},
+		{"collection invalid", `{"shapes":{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":[181,0]}]}}`, "longitude 181 out of range"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var d GeoTest
+			err := json.Unmarshal([]byte(test.json), &d)
+			assert.ErrorIs(t, err, types.ErrInvalidGeometry)
+			assert.ErrorContains(t, err, test.err)
+
+			// the same document decoded from BSON
+			var doc bson.D
+			if !assert.Nil(t, bson.UnmarshalExtJSON([]byte(test.json), false, &doc)) {
+				return
+			}
+			data, _ := bson.Marshal(doc)
+			assert.ErrorContains(t, bson.Unmarshal(data, &d), test.err)
+		})
+	}
+}
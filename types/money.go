@@ -0,0 +1,218 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var (
+	ErrInvalidCurrency  = errors.New("invalid ISO 4217 currency code")
+	ErrCurrencyMismatch = errors.New("currencies do not match")
+)
+
+// currencies maps the active ISO 4217 currency codes to their number of minor units.
+var currencies = map[string]int{
+	"AED": 2, "AFN": 2, "ALL": 2, "AMD": 2, "ANG": 2, "AOA": 2, "ARS": 2, "AUD": 2, "AWG": 2, "AZN": 2,
+	"BAM": 2, "BBD": 2, "BDT": 2, "BGN": 2, "BHD": 3, "BIF": 0, "BMD": 2, "BND": 2, "BOB": 2, "BOV": 2,
+	"BRL": 2, "BSD": 2, "BTN": 2, "BWP": 2, "BYN": 2, "BZD": 2, "CAD": 2, "CDF": 2, "CHE": 2, "CHF": 2,
+	"CHW": 2, "CLF": 4, "CLP": 0, "CNY": 2, "COP": 2, "COU": 2, "CRC": 2, "CUP": 2, "CVE": 2, "CZK": 2,
+	"DJF": 0, "DKK": 2, "DOP": 2, "DZD": 2, "EGP": 2, "ERN": 2, "ETB": 2, "EUR": 2, "FJD": 2, "FKP": 2,
+	"GBP": 2, "GEL": 2, "GHS": 2, "GIP": 2, "GMD": 2, "GNF": 0, "GTQ": 2, "GYD": 2, "HKD": 2, "HNL": 2,
+	"HTG": 2, "HUF": 2, "IDR": 2, "ILS": 2, "INR": 2, "IQD": 3, "IRR": 2, "ISK": 0, "JMD": 2, "JOD": 3,
+	"JPY": 0, "KES": 2, "KGS": 2, "KHR": 2, "KMF": 0, "KPW": 2, "KRW": 0, "KWD": 3, "KYD": 2, "KZT": 2,
+	"LAK": 2, "LBP": 2, "LKR": 2, "LRD": 2, "LSL": 2, "LYD": 3, "MAD": 2, "MDL": 2, "MGA": 2, "MKD": 2,
+	"MMK": 2, "MNT": 2, "MOP": 2, "MRU": 2, "MUR": 2, "MVR": 2, "MWK": 2, "MXN": 2, "MXV": 2, "MYR": 2,
+	"MZN": 2, "NAD": 2, "NGN": 2, "NIO": 2, "NOK": 2, "NPR": 2, "NZD": 2, "OMR": 3, "PAB": 2, "PEN": 2,
+	"PGK": 2, "PHP": 2, "PKR": 2, "PLN": 2, "PYG": 0, "QAR": 2, "RON": 2, "RSD": 2, "RUB": 2, "RWF": 0,
+	"SAR": 2, "SBD": 2, "SCR": 2, "SDG": 2, "SEK": 2, "SGD": 2, "SHP": 2, "SLE": 2, "SOS": 2, "SRD": 2,
+	"SSP": 2, "STN": 2, "SVC": 2, "SYP": 2, "SZL": 2, "THB": 2, "TJS": 2, "TMT": 2, "TND": 3, "TOP": 2,
+	"TRY": 2, "TTD": 2, "TWD": 2, "TZS": 2, "UAH": 2, "UGX": 0, "USD": 2, "USN": 2, "UYI": 0, "UYU": 2,
+	"UYW": 4, "UZS": 2, "VED": 2, "VES": 2, "VND": 0, "VUV": 0, "WST": 2, "XAF": 0, "XCD": 2, "XCG": 2,
+	"XOF": 0, "XPF": 0, "YER": 2, "ZAR": 2, "ZMW": 2, "ZWG": 2,
+}
+
+// CurrencyMinorUnits returns the number of decimal places of the ISO 4217 currency code, e.g. 2 for EUR and 0 for JPY.
+func CurrencyMinorUnits(code string) (int, bool) {
+	units, ok := currencies[code]
+	return units, ok
+}
+
+// Money is an amount of an ISO 4217 currency. The zero value has no currency, it is rendered to JSON null,
+// a zero amount without currency is decoded into the zero value.
+type Money struct {
+	Amount   Decimal `bson:"amount" json:"amount"`
+	Currency string  `bson:"currency" json:"currency"`
+}
+
+// NewMoney returns the amount of the currency, the currency code is converted to upper case.
+// ErrInvalidCurrency is returned, if the currency is not an active ISO 4217 code.
+func NewMoney(amount Decimal, currency string) (Money, error) {
+	m := Money{Amount: amount, Currency: strings.ToUpper(currency)}
+	if err := m.Validate(); err != nil {
+		return Money{}, err
+	}
+
+	return m, nil
+}
+
+// Validate returns ErrInvalidCurrency, if the currency is not an active ISO 4217 code.
+func (m Money) Validate() error {
+	if _, ok := currencies[m.Currency]; !ok {
+		return fmt.Errorf("%w: %q", ErrInvalidCurrency, m.Currency)
+	}
+
+	return nil
+}
+
+// Add returns m+o, ErrCurrencyMismatch is returned if the currencies differ.
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf("%w: %s %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+
+	amount, err := m.Amount.Add(o.Amount)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{Amount: amount, Currency: m.Currency}, nil
+}
+
+// Sub returns m-o, ErrCurrencyMismatch is returned if the currencies differ.
+func (m Money) Sub(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf("%w: %s %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+
+	amount, err := m.Amount.Sub(o.Amount)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{Amount: amount, Currency: m.Currency}, nil
+}
+
+// Mul returns the amount multiplied by factor, e.g. a quantity or a tax rate, the result is not rounded.
+func (m Money) Mul(factor Decimal) (Money, error) {
+	amount, err := m.Amount.Mul(factor)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{Amount: amount, Currency: m.Currency}, nil
+}
+
+// Round rounds the amount to the minor units of the currency using mode, e.g. 2 decimal places for EUR.
+func (m Money) Round(mode RoundingMode) (Money, error) {
+	units, ok := currencies[m.Currency]
+	if !ok {
+		units = 2
+	}
+
+	amount, err := m.Amount.Round(units, mode)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{Amount: amount, Currency: m.Currency}, nil
+}
+
+// Cmp compares the amounts of m and o, ErrCurrencyMismatch is returned if the currencies differ.
+func (m Money) Cmp(o Money) (int, error) {
+	if m.Currency != o.Currency {
+		return 0, fmt.Errorf("%w: %s %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+
+	return m.Amount.Cmp(o.Amount)
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m.Amount.IsZero()
+}
+
+// String returns the amount followed by the currency code, e.g. "19.99 EUR".
+func (m Money) String() string {
+	return m.Amount.String() + " " + m.Currency
+}
+
+// isEmpty reports whether m is the zero value, a zero amount without currency.
+func (m Money) isEmpty() bool {
+	return len(m.Currency) == 0 && m.Amount.IsZero()
+}
+
+// validated validates the decoded money, a zero amount without currency results in the zero value.
+func (m Money) validated() (Money, error) {
+	if m.isEmpty() {
+		return Money{}, nil
+	}
+
+	if err := m.Validate(); err != nil {
+		return Money{}, err
+	}
+
+	return m, nil
+}
+
+// MarshalJSON serializes the money as JSON object, the zero value is rendered to null.
+func (m Money) MarshalJSON() ([]byte, error) {
+	type money Money
+
+	if m.isEmpty() {
+		return json.Marshal(nil)
+	}
+
+	return json.Marshal(money(m))
+}
+
+// UnmarshalJSON deserializes a JSON object into the money and validates the currency, JSON null results in zero money.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	type money Money
+
+	if string(data) == "null" {
+		*m = Money{}
+		return nil
+	}
+
+	var v money
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	valid, err := Money(v).validated()
+	if err != nil {
+		return err
+	}
+
+	*m = valid
+	return nil
+}
+
+// UnmarshalBSON deserializes a BSON document into the money and validates the currency,
+// BSON null results in zero money.
+func (m *Money) UnmarshalBSON(data []byte) error {
+	type money Money
+
+	// BSON null is passed as empty value
+	if len(data) == 0 {
+		*m = Money{}
+		return nil
+	}
+
+	var v money
+	if err := bson.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	valid, err := Money(v).validated()
+	if err != nil {
+		return err
+	}
+
+	*m = valid
+	return nil
+}
@@ -0,0 +1,122 @@
+package types_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mbretter/go-mongodb/v2/types"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestMoney_New(t *testing.T) {
+	m, err := types.NewMoney(dec("19.99"), "eur")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "19.99 EUR", m.String())
+
+	_, err = types.NewMoney(dec("1"), "EURO")
+	assert.True(t, errors.Is(err, types.ErrInvalidCurrency))
+
+	units, ok := types.CurrencyMinorUnits("JPY")
+	assert.True(t, ok)
+	assert.Equal(t, 0, units)
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	a, _ := types.NewMoney(dec("19.99"), "EUR")
+	b, _ := types.NewMoney(dec("0.01"), "EUR")
+	usd, _ := types.NewMoney(dec("1"), "USD")
+
+	sum, err := a.Add(b)
+	assert.Nil(t, err)
+	assert.Equal(t, "20.00 EUR", sum.String())
+
+	diff, err := a.Sub(b)
+	assert.Nil(t, err)
+	assert.Equal(t, "19.98 EUR", diff.String())
+
+	_, err = a.Add(usd)
+	assert.True(t, errors.Is(err, types.ErrCurrencyMismatch))
+	_, err = a.Sub(usd)
+	assert.True(t, errors.Is(err, types.ErrCurrencyMismatch))
+
+	cmp, err := a.Cmp(b)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, cmp)
+	_, err = a.Cmp(usd)
+	assert.NotNil(t, err)
+
+	tax, err := a.Mul(dec("0.2"))
+	assert.Nil(t, err)
+	assert.Equal(t, "3.998 EUR", tax.String())
+
+	rounded, err := tax.Round(types.RoundHalfUp)
+	assert.Nil(t, err)
+	assert.Equal(t, "4.00 EUR", rounded.String())
+
+	_, err = a.Mul(dec("1E+6144"))
+	assert.True(t, errors.Is(err, types.ErrDecimalOverflow))
+
+	yen, _ := types.NewMoney(dec("1234.5"), "JPY")
+	rounded, err = yen.Round(types.RoundHalfEven)
+	assert.Nil(t, err)
+	assert.Equal(t, "1234 JPY", rounded.String())
+	assert.False(t, yen.IsZero())
+}
+
+func TestMoney_RoundTrip(t *testing.T) {
+	m, _ := types.NewMoney(dec("19.99"), "EUR")
+
+	j, _ := json.Marshal(m)
+	b, _ := bson.Marshal(m)
+	d128, _ := bson.ParseDecimal128("19.99")
+	exp, _ := bson.Marshal(bson.D{{"amount", d128}, {"currency", "EUR"}})
+
+	assert.Equal(t, `{"amount":"19.99","currency":"EUR"}`, string(j))
+	assert.Equal(t, exp, b)
+
+	var fromJson, fromBson types.Money
+	assert.Nil(t, json.Unmarshal(j, &fromJson))
+	assert.Nil(t, bson.Unmarshal(b, &fromBson))
+	assert.Equal(t, m, fromJson)
+	assert.Equal(t, m, fromBson)
+
+	err := json.Unmarshal([]byte(`{"amount":"1","currency":"XYZ"}`), &fromJson)
+	assert.True(t, errors.Is(err, types.ErrInvalidCurrency))
+	assert.Nil(t, json.Unmarshal([]byte(`null`), &fromJson))
+	assert.Equal(t, types.Money{}, fromJson)
+
+	invalid, _ := bson.Marshal(bson.D{{"amount", d128}, {"currency", "XYZ"}})
+	err = bson.Unmarshal(invalid, &fromBson)
+	assert.True(t, errors.Is(err, types.ErrInvalidCurrency))
+}
+
+type MoneyTest struct {
+	Price types.Money `json:"price" bson:"price"`
+}
+
+func TestMoney_ZeroRoundTrip(t *testing.T) {
+	j, err := json.Marshal(MoneyTest{})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"price":null}`, string(j))
+
+	b, err := bson.Marshal(MoneyTest{})
+	assert.Nil(t, err)
+
+	fromJson, fromBson := MoneyTest{Price: types.Money{Currency: "EUR"}}, MoneyTest{Price: types.Money{Currency: "EUR"}}
+	assert.Nil(t, json.Unmarshal(j, &fromJson))
+	assert.Nil(t, bson.Unmarshal(b, &fromBson))
+	assert.Equal(t, MoneyTest{}, fromJson)
+	assert.Equal(t, MoneyTest{}, fromBson)
+
+	// zero amounts without currency, e.g. written before
+	assert.Nil(t, json.Unmarshal([]byte(`{"price":{"amount":"0","currency":""}}`), &fromJson))
+	assert.Equal(t, MoneyTest{}, fromJson)
+
+	fromBson = MoneyTest{Price: types.Money{Currency: "EUR"}}
+	b, _ = bson.Marshal(bson.D{{"price", nil}})
+	assert.Nil(t, bson.Unmarshal(b, &fromBson))
+	assert.Equal(t, MoneyTest{}, fromBson)
+}
@@ -0,0 +1,161 @@
+package types
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Null is a nullable value of any type like sql.Null, Valid is false if the value is null.
+// Unlike NullString and the null number types, zero values are kept, only an invalid Null is encoded to null.
+type Null[T any] struct {
+	V     T
+	Valid bool
+}
+
+// NullOf returns a valid Null holding v.
+func NullOf[T any](v T) Null[T] {
+	return Null[T]{V: v, Valid: true}
+}
+
+// NullFromPtr returns a Null holding the value of p, it is null if p is nil.
+func NullFromPtr[T any](p *T) Null[T] {
+	if p == nil {
+		return Null[T]{}
+	}
+
+	return NullOf(*p)
+}
+
+// Ptr returns a pointer to the value, or nil if the value is null.
+func (n Null[T]) Ptr() *T {
+	if !n.Valid {
+		return nil
+	}
+
+	v := n.V
+	return &v
+}
+
+// IsNull reports whether the value is null.
+func (n Null[T]) IsNull() bool {
+	return !n.Valid
+}
+
+// MarshalJSON serializes the value to JSON, a null value is marshaled to JSON null.
+func (n Null[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return json.Marshal(nil)
+	}
+	return json.Marshal(n.V)
+}
+
+// UnmarshalJSON deserializes JSON data into the value, JSON null results in a null value.
+func (n *Null[T]) UnmarshalJSON(data []byte) error {
+	*n = Null[T]{}
+	if string(data) == "null" {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &n.V); err != nil {
+		return err
+	}
+
+	n.Valid = true
+	return nil
+}
+
+// MarshalBSONValue serializes the value to BSON, a null value is marshaled to BSON null.
+func (n Null[T]) MarshalBSONValue() (byte, []byte, error) {
+	if !n.Valid {
+		return byte(bson.TypeNull), nil, nil
+	}
+	return marshalBsonValue(n.V)
+}
+
+// UnmarshalBSONValue deserializes a BSON value into the value, BSON null or undefined result in a null value.
+func (n *Null[T]) UnmarshalBSONValue(typ byte, data []byte) error {
+	*n = Null[T]{}
+	if isBsonNull(typ) {
+		return nil
+	}
+
+	if err := bson.UnmarshalValue(bson.Type(typ), data, &n.V); err != nil {
+		return err
+	}
+
+	n.Valid = true
+	return nil
+}
+
+// Scan implements the sql.Scanner interface, SQL NULL results in a null value.
+func (n *Null[T]) Scan(src any) error {
+	var sn sql.Null[T]
+	if err := sn.Scan(src); err != nil {
+		return err
+	}
+
+	n.V, n.Valid = sn.V, sn.Valid
+	return nil
+}
+
+// Value implements the driver.Valuer interface, a null value results in SQL NULL.
+func (n Null[T]) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(n.V)
+}
+
+// Optional is a value, which is either absent, null or set. The zero value is absent.
+// Absent values are skipped by utils.Flatten and omitted by bson "omitempty" and json "omitzero" tags,
+// null values are encoded to null. When decoding, a missing field leaves the Optional absent.
+type Optional[T any] struct {
+	Null[T]
+	Present bool
+}
+
+// OptionalOf returns an Optional set to v.
+func OptionalOf[T any](v T) Optional[T] {
+	return Optional[T]{Null: NullOf(v), Present: true}
+}
+
+// OptionalNull returns a present Optional, which is null.
+func OptionalNull[T any]() Optional[T] {
+	return Optional[T]{Present: true}
+}
+
+// IsAbsent reports whether the value is absent.
+func (o Optional[T]) IsAbsent() bool {
+	return !o.Present
+}
+
+// IsNull reports whether the value is present and null.
+func (o Optional[T]) IsNull() bool {
+	return o.Present && !o.Valid
+}
+
+// IsZero reports whether the value is absent, it is used by the bson "omitempty" and json "omitzero" tags.
+func (o Optional[T]) IsZero() bool {
+	return !o.Present
+}
+
+// UnmarshalJSON deserializes JSON data into the value, JSON null results in a present null value.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Present = true
+	return o.Null.UnmarshalJSON(data)
+}
+
+// UnmarshalBSONValue deserializes a BSON value into the value, BSON null results in a present null value.
+func (o *Optional[T]) UnmarshalBSONValue(typ byte, data []byte) error {
+	o.Present = true
+	return o.Null.UnmarshalBSONValue(typ, data)
+}
+
+// Scan implements the sql.Scanner interface, SQL NULL results in a present null value.
+func (o *Optional[T]) Scan(src any) error {
+	o.Present = true
+	return o.Null.Scan(src)
+}
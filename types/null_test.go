@@ -0,0 +1,123 @@
+package types_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mbretter/go-mongodb/v2/types"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type NullTest struct {
+	Count types.Null[int32]  `json:"count" bson:"count"`
+	Name  types.Null[string] `json:"name" bson:"name"`
+}
+
+type OptionalTest struct {
+	Count types.Optional[int32]  `json:"count,omitzero" bson:"count,omitempty"`
+	Name  types.Optional[string] `json:"name,omitzero" bson:"name,omitempty"`
+}
+
+func TestNull_Marshal(t *testing.T) {
+	s := NullTest{Count: types.NullOf[int32](0)}
+
+	j, _ := json.Marshal(s)
+	b, _ := bson.Marshal(s)
+	exp, _ := bson.Marshal(bson.D{{"count", int32(0)}, {"name", nil}})
+
+	assert.Equal(t, `{"count":0,"name":null}`, string(j))
+	assert.Equal(t, exp, b)
+}
+
+func TestNull_Unmarshal(t *testing.T) {
+	var fromJson, fromBson NullTest
+
+	b, _ := bson.Marshal(bson.D{{"count", int32(0)}, {"name", nil}})
+
+	assert.Nil(t, json.Unmarshal([]byte(`{"count":0,"name":null}`), &fromJson))
+	assert.Nil(t, bson.Unmarshal(b, &fromBson))
+	assert.Equal(t, NullTest{Count: types.NullOf[int32](0)}, fromJson)
+	assert.Equal(t, NullTest{Count: types.NullOf[int32](0)}, fromBson)
+
+	b, _ = bson.Marshal(bson.D{{"name", int32(1)}})
+	assert.NotNil(t, bson.Unmarshal(b, &fromBson))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"name":1}`), &fromJson))
+}
+
+func TestNull_Ptr(t *testing.T) {
+	s := "foo"
+
+	assert.Nil(t, types.Null[string]{}.Ptr())
+	assert.Equal(t, &s, types.NullOf(s).Ptr())
+	assert.Equal(t, types.NullOf(s), types.NullFromPtr(&s))
+	assert.Equal(t, types.Null[string]{}, types.NullFromPtr[string](nil))
+}
+
+func TestNull_Sql(t *testing.T) {
+	var n types.Null[int64]
+
+	assert.Nil(t, n.Scan(int64(42)))
+	assert.Equal(t, types.NullOf[int64](42), n)
+
+	assert.Nil(t, n.Scan(nil))
+	assert.True(t, n.IsNull())
+
+	v, err := types.NullOf[int32](42).Value()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(42), v)
+
+	v, err = types.Null[int32]{}.Value()
+	assert.Nil(t, err)
+	assert.Nil(t, v)
+}
+
+func TestOptional_Marshal(t *testing.T) {
+	tests := []struct {
+		name string
+		in   OptionalTest
+		json string
+		bson bson.D
+	}{
+		{"absent", OptionalTest{}, `{}`, bson.D{}},
+		{"null", OptionalTest{Name: types.OptionalNull[string]()}, `{"name":null}`, bson.D{{"name", nil}}},
+		{"zero", OptionalTest{Count: types.OptionalOf[int32](0)}, `{"count":0}`, bson.D{{"count", int32(0)}}},
+		{"value", OptionalTest{Count: types.OptionalOf[int32](1), Name: types.OptionalOf("foo")},
+			`{"count":1,"name":"foo"}`, bson.D{{"count", int32(1)}, {"name", "foo"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			j, _ := json.Marshal(test.in)
+			b, _ := bson.Marshal(test.in)
+			exp, _ := bson.Marshal(test.bson)
+
+			assert.Equal(t, test.json, string(j))
+			assert.Equal(t, exp, b)
+
+			var fromJson, fromBson OptionalTest
+			assert.Nil(t, json.Unmarshal(j, &fromJson))
+			assert.Nil(t, bson.Unmarshal(b, &fromBson))
+			assert.Equal(t, test.in, fromJson)
+			assert.Equal(t, test.in, fromBson)
+		})
+	}
+}
+
+func TestOptional_State(t *testing.T) {
+	var o types.Optional[string]
+	assert.True(t, o.IsAbsent())
+	assert.False(t, o.IsNull())
+
+	o = types.OptionalNull[string]()
+	assert.False(t, o.IsAbsent())
+	assert.True(t, o.IsNull())
+
+	o = types.OptionalOf("")
+	assert.False(t, o.IsAbsent())
+	assert.False(t, o.IsNull())
+
+	var s types.Optional[string]
+	assert.Nil(t, s.Scan(nil))
+	assert.Equal(t, types.OptionalNull[string](), s)
+}
@@ -24,6 +24,35 @@ func (v NullFloat32) MarshalBSONValue() (byte, []byte, error) {
 	return marshalBsonValue(float32(v))
 }
 
+// UnmarshalJSON deserializes JSON data into the NullFloat32, JSON null decodes into 0.
+// An error is returned if the number does not fit into float32.
+func (v *NullFloat32) UnmarshalJSON(data []byte) error {
+	var n float32
+	if err := unmarshalJsonNumber(data, &n); err != nil {
+		return err
+	}
+
+	*v = NullFloat32(n)
+	return nil
+}
+
+// UnmarshalBSONValue deserializes any BSON number into the NullFloat32, BSON null decodes into 0.
+// ErrOverflow is returned if the number does not fit into float32.
+func (v *NullFloat32) UnmarshalBSONValue(typ byte, data []byte) error {
+	if isBsonNull(typ) {
+		*v = 0
+		return nil
+	}
+
+	n, err := unmarshalBsonFloat(typ, data, 32)
+	if err != nil {
+		return err
+	}
+
+	*v = NullFloat32(float32(n))
+	return nil
+}
+
 type NullFloat64 float64
 
 // MarshalJSON customizes the JSON marshaling process for NullFloat64. It returns nil if the value is 0, otherwise it returns the float64 value.
@@ -42,6 +71,35 @@ func (v NullFloat64) MarshalBSONValue() (byte, []byte, error) {
 	return marshalBsonValue(float64(v))
 }
 
+// UnmarshalJSON deserializes JSON data into the NullFloat64, JSON null decodes into 0.
+// An error is returned if the number does not fit into float64.
+func (v *NullFloat64) UnmarshalJSON(data []byte) error {
+	var n float64
+	if err := unmarshalJsonNumber(data, &n); err != nil {
+		return err
+	}
+
+	*v = NullFloat64(n)
+	return nil
+}
+
+// UnmarshalBSONValue deserializes any BSON number into the NullFloat64, BSON null decodes into 0.
+// ErrOverflow is returned if the number does not fit into float64.
+func (v *NullFloat64) UnmarshalBSONValue(typ byte, data []byte) error {
+	if isBsonNull(typ) {
+		*v = 0
+		return nil
+	}
+
+	n, err := unmarshalBsonFloat(typ, data, 64)
+	if err != nil {
+		return err
+	}
+
+	*v = NullFloat64(n)
+	return nil
+}
+
 type NullInt32 int32
 
 // MarshalJSON serializes the NullInt32 value into JSON, encoding zero values as null.
@@ -60,6 +118,35 @@ func (v NullInt32) MarshalBSONValue() (byte, []byte, error) {
 	return marshalBsonValue(int32(v))
 }
 
+// UnmarshalJSON deserializes JSON data into the NullInt32, JSON null decodes into 0.
+// An error is returned if the number does not fit into int32.
+func (v *NullInt32) UnmarshalJSON(data []byte) error {
+	var n int32
+	if err := unmarshalJsonNumber(data, &n); err != nil {
+		return err
+	}
+
+	*v = NullInt32(n)
+	return nil
+}
+
+// UnmarshalBSONValue deserializes any BSON number into the NullInt32, BSON null decodes into 0.
+// ErrOverflow is returned if the number does not fit into int32, doubles and decimals must be integral.
+func (v *NullInt32) UnmarshalBSONValue(typ byte, data []byte) error {
+	if isBsonNull(typ) {
+		*v = 0
+		return nil
+	}
+
+	n, err := BsonInt(bson.RawValue{Type: bson.Type(typ), Value: data}, 32)
+	if err != nil {
+		return err
+	}
+
+	*v = NullInt32(n)
+	return nil
+}
+
 type NullInt64 int64
 
 // MarshalJSON marshals the NullInt64 value into JSON. If the value is zero, it marshals as null.
@@ -77,3 +164,32 @@ func (v NullInt64) MarshalBSONValue() (byte, []byte, error) {
 	}
 	return marshalBsonValue(int64(v))
 }
+
+// UnmarshalJSON deserializes JSON data into the NullInt64, JSON null decodes into 0.
+// An error is returned if the number does not fit into int64.
+func (v *NullInt64) UnmarshalJSON(data []byte) error {
+	var n int64
+	if err := unmarshalJsonNumber(data, &n); err != nil {
+		return err
+	}
+
+	*v = NullInt64(n)
+	return nil
+}
+
+// UnmarshalBSONValue deserializes any BSON number into the NullInt64, BSON null decodes into 0.
+// ErrOverflow is returned if the number does not fit into int64, doubles and decimals must be integral.
+func (v *NullInt64) UnmarshalBSONValue(typ byte, data []byte) error {
+	if isBsonNull(typ) {
+		*v = 0
+		return nil
+	}
+
+	n, err := BsonInt(bson.RawValue{Type: bson.Type(typ), Value: data}, 64)
+	if err != nil {
+		return err
+	}
+
+	*v = NullInt64(n)
+	return nil
+}
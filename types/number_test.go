@@ -2,9 +2,11 @@ package types_test
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/mbretter/go-mongodb/v2/types"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"math"
 	"testing"
 )
 
@@ -41,3 +43,145 @@ func TestNumber_MarshalNonEmpty(t *testing.T) {
 	assert.Equal(t, "A\x00\x00\x00\x01float32\x00\x00\x00\x00\xc0\xcc\xcc\xf4?\x01float64\x00333333\xd3?\x10int32\x00\xff\xff\xff\x7f\x12int64\x00\xff\xff\xff\xff\xff\xff\xff\x7f\x00", string(b))
 
 }
+
+func TestNumber_UnmarshalNull(t *testing.T) {
+	s := NumberTest{Float32: 1, Float64: 1, Int32: 1, Int64: 1}
+
+	err := json.Unmarshal([]byte(`{"float32":null,"float64":null,"int32":null,"int64":null}`), &s)
+	assert.Nil(t, err)
+	assert.Equal(t, NumberTest{}, s)
+
+	s = NumberTest{Float32: 1, Float64: 1, Int32: 1, Int64: 1}
+	b, _ := bson.Marshal(bson.D{{"float32", nil}, {"float64", nil}, {"int32", bson.Undefined{}}, {"int64", nil}})
+
+	err = bson.Unmarshal(b, &s)
+	assert.Nil(t, err)
+	assert.Equal(t, NumberTest{}, s)
+}
+
+func TestNumber_UnmarshalRoundTrip(t *testing.T) {
+	s := NumberTest{
+		Int32:   0x7FFFFFFF,
+		Int64:   0x7FFFFFFFFFFFFFFF,
+		Float32: 1.5,
+		Float64: 0.3,
+	}
+
+	var fromJson, fromBson NumberTest
+
+	j, _ := json.Marshal(s)
+	b, _ := bson.Marshal(s)
+
+	assert.Nil(t, json.Unmarshal(j, &fromJson))
+	assert.Nil(t, bson.Unmarshal(b, &fromBson))
+	assert.Equal(t, s, fromJson)
+	assert.Equal(t, s, fromBson)
+}
+
+func TestNumber_UnmarshalBsonTypes(t *testing.T) {
+	dec, _ := bson.ParseDecimal128("42")
+	decExp, _ := bson.ParseDecimal128("4.20E+1")
+
+	values := []struct {
+		name  string
+		value any
+	}{
+		{"int32", int32(42)},
+		{"int64", int64(42)},
+		{"double", float64(42)},
+		{"decimal", dec},
+		{"decimal exponent", decExp},
+	}
+
+	for _, v := range values {
+		t.Run(v.name, func(t *testing.T) {
+			b, _ := bson.Marshal(bson.D{{"float32", v.value}, {"float64", v.value}, {"int32", v.value}, {"int64", v.value}})
+
+			var s NumberTest
+			err := bson.Unmarshal(b, &s)
+
+			assert.Nil(t, err)
+			assert.Equal(t, NumberTest{Float32: 42, Float64: 42, Int32: 42, Int64: 42}, s)
+		})
+	}
+}
+
+func TestNumber_UnmarshalBsonErrors(t *testing.T) {
+	frac, _ := bson.ParseDecimal128("4.2")
+	huge, _ := bson.ParseDecimal128("1E+400")
+
+	tests := []struct {
+		name     string
+		field    string
+		value    any
+		overflow bool
+	}{
+		{"int64 into int32", "int32", int64(math.MaxInt32 + 1), true},
+		{"double into int32", "int32", float64(math.MinInt32 - 1), true},
+		{"double into int64", "int64", math.MaxFloat64, true},
+		{"decimal into int64", "int64", huge, true},
+		{"double into float32", "float32", math.MaxFloat64, true},
+		{"decimal into float64", "float64", huge, true},
+		{"fraction into int32", "int32", 4.2, false},
+		{"decimal fraction into int64", "int64", frac, false},
+		{"string into int64", "int64", "42", false},
+		{"string into float64", "float64", "42", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b, _ := bson.Marshal(bson.D{{test.field, test.value}})
+
+			var s NumberTest
+			err := bson.Unmarshal(b, &s)
+
+			assert.NotNil(t, err)
+			assert.Equal(t, test.overflow, errors.Is(err, types.ErrOverflow))
+		})
+	}
+}
+
+func TestNumber_UnmarshalJsonErrors(t *testing.T) {
+	tests := []string{
+		`{"int32":2147483648}`,
+		`{"int64":1.5}`,
+		`{"float32":1e39}`,
+		`{"float64":"1"}`,
+	}
+
+	for _, test := range tests {
+		var s NumberTest
+		assert.NotNil(t, json.Unmarshal([]byte(test), &s), test)
+	}
+}
+
+func TestBsonInt(t *testing.T) {
+	exp, _ := bson.ParseDecimal128("4.2E+1")
+
+	tests := []struct {
+		name  string
+		value any
+		bits  int
+		exp   int64
+		err   bool
+	}{
+		{"int32", int32(42), 32, 42, false},
+		{"int64", int64(math.MaxInt64), 64, math.MaxInt64, false},
+		{"double", float64(42), 32, 42, false},
+		{"decimal", exp, 64, 42, false},
+		{"int64 into int32", int64(math.MaxInt32 + 1), 32, 0, true},
+		{"fraction", 4.2, 64, 0, true},
+		{"string", "42", 64, 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			typ, data, _ := bson.MarshalValue(test.value)
+
+			n, err := types.BsonInt(bson.RawValue{Type: typ, Value: data}, test.bits)
+
+			assert.Equal(t, test.err, err != nil)
+			assert.Equal(t, test.exp, n)
+		})
+	}
+}
@@ -7,9 +7,14 @@
 package types
 
 import (
+	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
+
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
@@ -45,6 +50,56 @@ func ObjectIdFromHex(s string) (ObjectId, error) {
 	return ObjectId(oId.Hex()), nil
 }
 
+// ObjectIdFromTime returns an ObjectId containing the timestamp of t and zeros for the remaining bytes,
+// it is used for building time range filters on _id, see ObjectIdRange.
+func ObjectIdFromTime(t time.Time) ObjectId {
+	var oId bson.ObjectID
+	binary.BigEndian.PutUint32(oId[0:4], uint32(t.Unix()))
+
+	return ObjectId(oId.Hex())
+}
+
+// ObjectIdRange returns a filter matching the ObjectIds created at or after from and before to,
+// e.g. bson.D{{"_id", types.ObjectIdRange(from, to)}}. A zero time leaves the range open on this side.
+// The timestamp of an ObjectId has a precision of seconds.
+func ObjectIdRange(from time.Time, to time.Time) bson.D {
+	filter := bson.D{}
+	if !from.IsZero() {
+		filter = append(filter, bson.E{"$gte", ObjectIdFromTime(from)})
+	}
+	if !to.IsZero() {
+		filter = append(filter, bson.E{"$lt", ObjectIdFromTime(to)})
+	}
+
+	return filter
+}
+
+// Valid reports whether the ObjectId consists of 24 hex digits, an empty ObjectId is not valid.
+func (o ObjectId) Valid() bool {
+	if len(o) != 24 {
+		return false
+	}
+
+	_, err := hex.DecodeString(string(o))
+	return err == nil
+}
+
+// Timestamp returns the creation time embedded into the ObjectId, or the zero time if the ObjectId is not valid.
+func (o ObjectId) Timestamp() time.Time {
+	oId, err := bson.ObjectIDFromHex(string(o))
+	if err != nil {
+		return time.Time{}
+	}
+
+	return oId.Timestamp()
+}
+
+// Compare returns -1 if o is less than other, 0 if they are equal and +1 if o is greater than other.
+// ObjectIds are ordered by their bytes like by the server, an empty ObjectId is less than any other.
+func (o ObjectId) Compare(other ObjectId) int {
+	return strings.Compare(strings.ToLower(string(o)), strings.ToLower(string(other)))
+}
+
 // IsZero checks if the ObjectId is zero (an empty string) and returns true if it is, otherwise false.
 func (o ObjectId) IsZero() bool {
 	return len(o) == 0
@@ -95,6 +150,37 @@ func (o *ObjectId) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// MarshalText serializes the ObjectId to its hex representation for text based encoders, e.g. encoding/xml.
+// JSON map keys do not use it, because the string of the ObjectId is written as is. A zero ObjectId results in an empty text.
+func (o ObjectId) MarshalText() ([]byte, error) {
+	if len(o) == 0 || o == NilObjectID {
+		return []byte{}, nil
+	}
+
+	if !o.Valid() {
+		return nil, bson.ErrInvalidHex
+	}
+
+	return []byte(strings.ToLower(string(o))), nil
+}
+
+// UnmarshalText deserializes the hex representation of an ObjectId, e.g. from a JSON map key or a URL query.
+// An empty text results in a zero ObjectId.
+func (o *ObjectId) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*o = ""
+		return nil
+	}
+
+	oId, err := bson.ObjectIDFromHex(string(data))
+	if err != nil {
+		return err
+	}
+
+	*o = ObjectId(oId.Hex())
+	return nil
+}
+
 // MarshalBSONValue serializes the ObjectId to BSON. It returns BSON null type for zero values or in case of invalid ObjectId.
 func (o ObjectId) MarshalBSONValue() (byte, []byte, error) {
 	if len(o) == 0 {
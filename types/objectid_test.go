@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"testing"
+	"time"
 )
 
 type ObjectIdTest struct {
@@ -177,3 +178,74 @@ func TestObjectId_UnmarshalBSONWrongType(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Equal(t, "error decoding key _id: wrong bson type expected objectid", err.Error())
 }
+
+func TestObjectId_Timestamp(t *testing.T) {
+	oId, _ := types.ObjectIdFromHex("6555d2cc4fce49f464c2f683")
+
+	assert.Equal(t, time.Date(2023, 11, 16, 8, 29, 0, 0, time.UTC), oId.Timestamp().UTC())
+	assert.True(t, types.ObjectId("").Timestamp().IsZero())
+	assert.True(t, types.ObjectId("xxx").Timestamp().IsZero())
+}
+
+func TestObjectId_FromTime(t *testing.T) {
+	ts := time.Date(2023, 11, 16, 8, 29, 0, 0, time.UTC)
+	oId := types.ObjectIdFromTime(ts)
+
+	assert.Equal(t, types.ObjectId("6555d2cc0000000000000000"), oId)
+	assert.Equal(t, ts, oId.Timestamp().UTC())
+}
+
+func TestObjectId_Range(t *testing.T) {
+	from := time.Date(2023, 11, 16, 8, 29, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	assert.Equal(t, bson.D{{"$gte", types.ObjectIdFromTime(from)}, {"$lt", types.ObjectIdFromTime(to)}},
+		types.ObjectIdRange(from, to))
+	assert.Equal(t, bson.D{{"$lt", types.ObjectIdFromTime(to)}}, types.ObjectIdRange(time.Time{}, to))
+	assert.Equal(t, bson.D{}, types.ObjectIdRange(time.Time{}, time.Time{}))
+}
+
+func TestObjectId_Valid(t *testing.T) {
+	assert.True(t, types.ObjectId("6555d2cc4fce49f464c2f683").Valid())
+	assert.True(t, types.ObjectId("6555D2CC4FCE49F464C2F683").Valid())
+	assert.False(t, types.ObjectId("").Valid())
+	assert.False(t, types.ObjectId("6555d2cc4fce49f464c2f68").Valid())
+	assert.False(t, types.ObjectId("6555d2cc4fce49f464c2f68x").Valid())
+}
+
+func TestObjectId_Compare(t *testing.T) {
+	a := types.ObjectId("6555d2cc4fce49f464c2f683")
+	b := types.ObjectId("6555d2cc4fce49f464c2f6a0")
+
+	assert.Equal(t, -1, a.Compare(b))
+	assert.Equal(t, 1, b.Compare(a))
+	assert.Equal(t, 0, b.Compare("6555D2CC4FCE49F464C2F6A0"))
+	assert.Equal(t, -1, types.ObjectId("").Compare(a))
+}
+
+func TestObjectId_Text(t *testing.T) {
+	oId := types.ObjectId("6555d2cc4fce49f464c2f683")
+
+	j, err := json.Marshal(map[types.ObjectId]int{oId: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"6555d2cc4fce49f464c2f683":1}`, string(j))
+
+	var m map[types.ObjectId]int
+	assert.Nil(t, json.Unmarshal(j, &m))
+	assert.Equal(t, map[types.ObjectId]int{oId: 1}, m)
+
+	assert.NotNil(t, json.Unmarshal([]byte(`{"xxx":1}`), &m))
+
+	txt, err := types.ObjectId("").MarshalText()
+	assert.Nil(t, err)
+	assert.Equal(t, "", string(txt))
+
+	_, err = types.ObjectId("xxx").MarshalText()
+	assert.NotNil(t, err)
+
+	var o types.ObjectId
+	assert.Nil(t, o.UnmarshalText([]byte("6555D2CC4FCE49F464C2F683")))
+	assert.Equal(t, oId, o)
+	assert.Nil(t, o.UnmarshalText(nil))
+	assert.Zero(t, o)
+}
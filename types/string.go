@@ -3,6 +3,8 @@ package types
 
 import (
 	"encoding/json"
+	"fmt"
+
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
@@ -23,3 +25,37 @@ func (v NullString) MarshalBSONValue() (byte, []byte, error) {
 	}
 	return marshalBsonValue(string(v))
 }
+
+// UnmarshalJSON deserializes JSON data into the NullString, JSON null decodes into an empty string.
+func (v *NullString) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*v = ""
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	*v = NullString(s)
+	return nil
+}
+
+// UnmarshalBSONValue deserializes a BSON string or symbol into the NullString, BSON null decodes into an empty string.
+func (v *NullString) UnmarshalBSONValue(typ byte, data []byte) error {
+	val := bson.RawValue{Type: bson.Type(typ), Value: data}
+
+	switch {
+	case isBsonNull(typ):
+		*v = ""
+	case val.Type == bson.TypeString:
+		*v = NullString(val.StringValue())
+	case val.Type == bson.TypeSymbol:
+		*v = NullString(val.Symbol())
+	default:
+		return fmt.Errorf("wrong bson type %s expected string", val.Type)
+	}
+
+	return nil
+}
@@ -34,3 +34,28 @@ func TestString_MarshalNonEmpty(t *testing.T) {
 	assert.Equal(t, "\x13\x00\x00\x00\x02name\x00\x04\x00\x00\x00foo\x00\x00", string(b))
 
 }
+
+func TestString_Unmarshal(t *testing.T) {
+	s := StringTest{Name: "foo"}
+	assert.Nil(t, json.Unmarshal([]byte(`{"name":null}`), &s))
+	assert.Equal(t, StringTest{}, s)
+
+	assert.Nil(t, json.Unmarshal([]byte(`{"name":"bar"}`), &s))
+	assert.Equal(t, StringTest{Name: "bar"}, s)
+
+	b, _ := bson.Marshal(bson.D{{"name", nil}})
+	assert.Nil(t, bson.Unmarshal(b, &s))
+	assert.Equal(t, StringTest{}, s)
+
+	b, _ = bson.Marshal(bson.D{{"name", bson.Symbol("foo")}})
+	assert.Nil(t, bson.Unmarshal(b, &s))
+	assert.Equal(t, StringTest{Name: "foo"}, s)
+
+	b, _ = bson.Marshal(StringTest{Name: "baz"})
+	assert.Nil(t, bson.Unmarshal(b, &s))
+	assert.Equal(t, StringTest{Name: "baz"}, s)
+
+	b, _ = bson.Marshal(bson.D{{"name", int32(1)}})
+	assert.NotNil(t, bson.Unmarshal(b, &s))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"name":1}`), &s))
+}
@@ -0,0 +1,373 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// JSON formats of NullTime, besides any time layout like time.RFC3339.
+const (
+	TimeFormatEpochMillis  = "epochmillis"
+	TimeFormatEpochSeconds = "epochseconds"
+)
+
+// DateLayout is the layout of a Date, when formatted as string.
+const DateLayout = time.DateOnly
+
+var nullTimeJsonFormat = time.RFC3339Nano
+
+var dateBsonString = false
+
+// SetNullTimeJsonFormat sets the JSON format of NullTime, which is either a time layout, e.g. time.RFC3339,
+// or TimeFormatEpochMillis or TimeFormatEpochSeconds for numeric timestamps, defaults to time.RFC3339Nano.
+func SetNullTimeJsonFormat(format string) {
+	nullTimeJsonFormat = format
+}
+
+// SetDateBsonString stores dates as strings of the format YYYY-MM-DD instead of datetimes at UTC midnight.
+// Both representations are decoded regardless of this setting.
+func SetDateBsonString(enabled bool) {
+	dateBsonString = enabled
+}
+
+// NullTime holds a point in time, the zero time is encoded to null.
+// It is stored as BSON datetime, which has millisecond precision.
+type NullTime struct {
+	time.Time
+}
+
+// NewNullTime returns a NullTime holding t.
+func NewNullTime(t time.Time) NullTime {
+	return NullTime{Time: t}
+}
+
+// Ptr returns a pointer to the time, or nil if the time is zero.
+func (t NullTime) Ptr() *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	v := t.Time
+	return &v
+}
+
+// MarshalJSON serializes the NullTime using the format set by SetNullTimeJsonFormat, a zero time is rendered to null.
+func (t NullTime) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return json.Marshal(nil)
+	}
+
+	switch nullTimeJsonFormat {
+	case TimeFormatEpochMillis:
+		return json.Marshal(t.UnixMilli())
+	case TimeFormatEpochSeconds:
+		return json.Marshal(t.Unix())
+	}
+
+	return json.Marshal(t.Format(nullTimeJsonFormat))
+}
+
+// UnmarshalJSON deserializes a JSON string using the format set by SetNullTimeJsonFormat, or a JSON number,
+// which is interpreted as epoch seconds if the format is TimeFormatEpochSeconds, otherwise as epoch millis.
+// JSON null and empty strings result in a zero time.
+func (t *NullTime) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*t = NullTime{}
+		return nil
+	}
+
+	if len(data) > 0 && data[0] != '"' {
+		n, err := strconv.ParseInt(string(data), 10, 64)
+		if err != nil {
+			return err
+		}
+
+		if nullTimeJsonFormat == TimeFormatEpochSeconds {
+			*t = NullTime{time.Unix(n, 0).UTC()}
+		} else {
+			*t = NullTime{time.UnixMilli(n).UTC()}
+		}
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	if len(s) == 0 {
+		*t = NullTime{}
+		return nil
+	}
+
+	layout := nullTimeJsonFormat
+	if layout == TimeFormatEpochMillis || layout == TimeFormatEpochSeconds {
+		layout = time.RFC3339Nano
+	}
+
+	v, err := time.Parse(layout, s)
+	if err != nil {
+		return err
+	}
+
+	*t = NullTime{v}
+	return nil
+}
+
+// MarshalBSONValue serializes the NullTime to a BSON datetime, a zero time is rendered to BSON null.
+func (t NullTime) MarshalBSONValue() (byte, []byte, error) {
+	if t.IsZero() {
+		return byte(bson.TypeNull), nil, nil
+	}
+	return marshalBsonValue(bson.NewDateTimeFromTime(t.Time))
+}
+
+// UnmarshalBSONValue deserializes a BSON datetime into the NullTime, BSON null results in a zero time.
+func (t *NullTime) UnmarshalBSONValue(typ byte, data []byte) error {
+	if isBsonNull(typ) {
+		*t = NullTime{}
+		return nil
+	}
+
+	if bson.Type(typ) != bson.TypeDateTime {
+		return errors.New("wrong bson type expected datetime")
+	}
+
+	dt := bson.RawValue{Type: bson.TypeDateTime, Value: data}.DateTime()
+	*t = NullTime{time.UnixMilli(dt).UTC()}
+
+	return nil
+}
+
+// Date is a calendar date without time and time zone, the zero Date is encoded to null.
+// It is stored as BSON datetime at UTC midnight, or as string if enabled by SetDateBsonString.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// NewDate returns the date of the given year, month and day, values out of range are normalized like by time.Date,
+// e.g. October 32 is November 1.
+func NewDate(year int, month time.Month, day int) Date {
+	return DateOf(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+// DateOf returns the date of t in the location of t.
+func DateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{Year: y, Month: m, Day: d}
+}
+
+// ParseDate parses a date of the format YYYY-MM-DD.
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(DateLayout, s)
+	if err != nil {
+		return Date{}, err
+	}
+
+	return DateOf(t), nil
+}
+
+// IsZero reports whether the date is the zero Date.
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// String returns the date formatted as YYYY-MM-DD, or "null" if zero.
+func (d Date) String() string {
+	if d.IsZero() {
+		return "null"
+	}
+
+	return d.In(time.UTC).Format(DateLayout)
+}
+
+// In returns the time of midnight of the date in the given location.
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// AddDays returns the date n days later, n may be negative.
+func (d Date) AddDays(n int) Date {
+	return NewDate(d.Year, d.Month, d.Day+n)
+}
+
+// Before reports whether d is before o.
+func (d Date) Before(o Date) bool {
+	return d.In(time.UTC).Before(o.In(time.UTC))
+}
+
+// After reports whether d is after o.
+func (d Date) After(o Date) bool {
+	return d.In(time.UTC).After(o.In(time.UTC))
+}
+
+// MarshalJSON serializes the date as string of the format YYYY-MM-DD, a zero date is rendered to null.
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return json.Marshal(nil)
+	}
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON deserializes a JSON string of the format YYYY-MM-DD, JSON null and empty strings result in a zero date.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	if s == nil || len(*s) == 0 {
+		*d = Date{}
+		return nil
+	}
+
+	v, err := ParseDate(*s)
+	if err != nil {
+		return err
+	}
+
+	*d = v
+	return nil
+}
+
+// MarshalBSONValue serializes the date to a BSON datetime at UTC midnight, or to a string if enabled by SetDateBsonString.
+// A zero date is rendered to BSON null.
+func (d Date) MarshalBSONValue() (byte, []byte, error) {
+	if d.IsZero() {
+		return byte(bson.TypeNull), nil, nil
+	}
+
+	if dateBsonString {
+		return marshalBsonValue(d.String())
+	}
+
+	return marshalBsonValue(bson.NewDateTimeFromTime(d.In(time.UTC)))
+}
+
+// UnmarshalBSONValue deserializes a BSON datetime or string into the date, the date of a datetime is taken in UTC.
+// BSON null results in a zero date.
+func (d *Date) UnmarshalBSONValue(typ byte, data []byte) error {
+	val := bson.RawValue{Type: bson.Type(typ), Value: data}
+
+	switch {
+	case isBsonNull(typ):
+		*d = Date{}
+	case val.Type == bson.TypeDateTime:
+		*d = DateOf(time.UnixMilli(val.DateTime()).UTC())
+	case val.Type == bson.TypeString:
+		v, err := ParseDate(val.StringValue())
+		if err != nil {
+			return err
+		}
+		*d = v
+	default:
+		return errors.New("wrong bson type expected datetime or string")
+	}
+
+	return nil
+}
+
+// TimeOfDay is a time of day without date and time zone, it is stored as string of the format hh:mm:ss,
+// followed by the fractional seconds if not zero. Unlike the other types, the zero TimeOfDay is midnight
+// and not encoded to null, use Null[TimeOfDay] for a nullable time of day.
+type TimeOfDay struct {
+	Hour       int
+	Minute     int
+	Second     int
+	Nanosecond int
+}
+
+// NewTimeOfDay returns the time of day of the given hour, minute and second.
+func NewTimeOfDay(hour int, minute int, second int) TimeOfDay {
+	return TimeOfDay{Hour: hour, Minute: minute, Second: second}
+}
+
+// TimeOfDayOf returns the time of day of t in the location of t.
+func TimeOfDayOf(t time.Time) TimeOfDay {
+	return TimeOfDay{Hour: t.Hour(), Minute: t.Minute(), Second: t.Second(), Nanosecond: t.Nanosecond()}
+}
+
+// ParseTimeOfDay parses a time of day of the format hh:mm, hh:mm:ss, or hh:mm:ss with fractional seconds.
+func ParseTimeOfDay(s string) (TimeOfDay, error) {
+	for _, layout := range []string{time.TimeOnly, "15:04"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return TimeOfDayOf(t), nil
+		}
+	}
+
+	return TimeOfDay{}, fmt.Errorf("invalid time of day: %q", s)
+}
+
+// String returns the time of day formatted as hh:mm:ss, followed by the fractional seconds if not zero.
+func (t TimeOfDay) String() string {
+	return t.On(Date{Year: 1, Month: 1, Day: 1}, time.UTC).Format("15:04:05.999999999")
+}
+
+// Duration returns the duration since midnight.
+func (t TimeOfDay) Duration() time.Duration {
+	return time.Duration(t.Hour)*time.Hour + time.Duration(t.Minute)*time.Minute +
+		time.Duration(t.Second)*time.Second + time.Duration(t.Nanosecond)
+}
+
+// On returns the time of day at the given date in the given location.
+func (t TimeOfDay) On(d Date, loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, t.Hour, t.Minute, t.Second, t.Nanosecond, loc)
+}
+
+// MarshalJSON serializes the time of day as string.
+func (t TimeOfDay) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON deserializes a JSON string into the time of day, JSON null results in midnight.
+func (t *TimeOfDay) UnmarshalJSON(data []byte) error {
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	if s == nil {
+		*t = TimeOfDay{}
+		return nil
+	}
+
+	v, err := ParseTimeOfDay(*s)
+	if err != nil {
+		return err
+	}
+
+	*t = v
+	return nil
+}
+
+// MarshalBSONValue serializes the time of day to a BSON string.
+func (t TimeOfDay) MarshalBSONValue() (byte, []byte, error) {
+	return marshalBsonValue(t.String())
+}
+
+// UnmarshalBSONValue deserializes a BSON string into the time of day, BSON null results in midnight.
+func (t *TimeOfDay) UnmarshalBSONValue(typ byte, data []byte) error {
+	if isBsonNull(typ) {
+		*t = TimeOfDay{}
+		return nil
+	}
+
+	if bson.Type(typ) != bson.TypeString {
+		return errors.New("wrong bson type expected string")
+	}
+
+	v, err := ParseTimeOfDay(bson.RawValue{Type: bson.TypeString, Value: data}.StringValue())
+	if err != nil {
+		return err
+	}
+
+	*t = v
+	return nil
+}
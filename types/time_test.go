@@ -0,0 +1,163 @@
+package types_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mbretter/go-mongodb/v2/types"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type TimeTest struct {
+	At   types.NullTime  `json:"at" bson:"at"`
+	Day  types.Date      `json:"day" bson:"day"`
+	Time types.TimeOfDay `json:"time" bson:"time"`
+}
+
+func TestNullTime_MarshalNull(t *testing.T) {
+	s := TimeTest{}
+
+	j, _ := json.Marshal(s)
+	b, _ := bson.Marshal(s)
+	exp, _ := bson.Marshal(bson.D{{"at", nil}, {"day", nil}, {"time", "00:00:00"}})
+
+	assert.Equal(t, `{"at":null,"day":null,"time":"00:00:00"}`, string(j))
+	assert.Equal(t, exp, b)
+
+	var fromJson, fromBson TimeTest
+	assert.Nil(t, json.Unmarshal(j, &fromJson))
+	assert.Nil(t, bson.Unmarshal(b, &fromBson))
+	assert.Equal(t, s, fromJson)
+	assert.Equal(t, s, fromBson)
+}
+
+func TestNullTime_RoundTrip(t *testing.T) {
+	s := TimeTest{
+		At:   types.NewNullTime(time.Date(2024, 2, 29, 13, 14, 15, 123000000, time.UTC)),
+		Day:  types.NewDate(2024, 2, 29),
+		Time: types.TimeOfDay{Hour: 13, Minute: 14, Second: 15, Nanosecond: 500000000},
+	}
+
+	j, _ := json.Marshal(s)
+	b, _ := bson.Marshal(s)
+	exp, _ := bson.Marshal(bson.D{
+		{"at", bson.NewDateTimeFromTime(s.At.Time)},
+		{"day", bson.NewDateTimeFromTime(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC))},
+		{"time", "13:14:15.5"},
+	})
+
+	assert.Equal(t, `{"at":"2024-02-29T13:14:15.123Z","day":"2024-02-29","time":"13:14:15.5"}`, string(j))
+	assert.Equal(t, exp, b)
+
+	var fromJson, fromBson TimeTest
+	assert.Nil(t, json.Unmarshal(j, &fromJson))
+	assert.Nil(t, bson.Unmarshal(b, &fromBson))
+	assert.Equal(t, s, fromJson)
+	assert.Equal(t, s, fromBson)
+}
+
+func TestNullTime_JsonFormat(t *testing.T) {
+	defer types.SetNullTimeJsonFormat(time.RFC3339Nano)
+
+	at := types.NewNullTime(time.Date(2024, 2, 29, 13, 14, 15, 0, time.UTC))
+
+	types.SetNullTimeJsonFormat(types.TimeFormatEpochMillis)
+	j, _ := json.Marshal(at)
+	assert.Equal(t, `1709212455000`, string(j))
+
+	var fromJson types.NullTime
+	assert.Nil(t, json.Unmarshal(j, &fromJson))
+	assert.Equal(t, at, fromJson)
+
+	types.SetNullTimeJsonFormat(types.TimeFormatEpochSeconds)
+	j, _ = json.Marshal(at)
+	assert.Equal(t, `1709212455`, string(j))
+	assert.Nil(t, json.Unmarshal(j, &fromJson))
+	assert.Equal(t, at, fromJson)
+
+	types.SetNullTimeJsonFormat(time.RFC1123)
+	j, _ = json.Marshal(at)
+	assert.Equal(t, `"Thu, 29 Feb 2024 13:14:15 UTC"`, string(j))
+
+	assert.NotNil(t, json.Unmarshal([]byte(`"2024-02-29"`), &fromJson))
+	assert.NotNil(t, json.Unmarshal([]byte(`1.5`), &fromJson))
+}
+
+func TestNullTime_UnmarshalBSONWrongType(t *testing.T) {
+	b, _ := bson.Marshal(bson.D{{"at", "2024-02-29"}})
+
+	var s TimeTest
+	err := bson.Unmarshal(b, &s)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "error decoding key at: wrong bson type expected datetime", err.Error())
+}
+
+func TestNullTime_Ptr(t *testing.T) {
+	now := time.Now()
+
+	assert.Nil(t, types.NullTime{}.Ptr())
+	assert.Equal(t, &now, types.NewNullTime(now).Ptr())
+}
+
+func TestDate(t *testing.T) {
+	d := types.NewDate(2024, 12, 32)
+
+	assert.Equal(t, types.Date{Year: 2025, Month: time.January, Day: 1}, d)
+	assert.Equal(t, "2025-01-01", d.String())
+	assert.Equal(t, "null", types.Date{}.String())
+	assert.Equal(t, types.NewDate(2024, 12, 31), d.AddDays(-1))
+	assert.True(t, d.AddDays(-1).Before(d))
+	assert.True(t, d.After(d.AddDays(-1)))
+	assert.Equal(t, types.NewDate(2024, 3, 1),
+		types.DateOf(time.Date(2024, 2, 29, 23, 0, 0, 0, time.UTC).In(time.FixedZone("CET", 3600))))
+
+	parsed, err := types.ParseDate("2024-02-29")
+	assert.Nil(t, err)
+	assert.Equal(t, types.NewDate(2024, 2, 29), parsed)
+
+	_, err = types.ParseDate("2023-02-29")
+	assert.NotNil(t, err)
+}
+
+func TestDate_BsonString(t *testing.T) {
+	defer types.SetDateBsonString(false)
+	types.SetDateBsonString(true)
+
+	s := TimeTest{Day: types.NewDate(2024, 2, 29)}
+
+	b, _ := bson.Marshal(s)
+	exp, _ := bson.Marshal(bson.D{{"at", nil}, {"day", "2024-02-29"}, {"time", "00:00:00"}})
+	assert.Equal(t, exp, b)
+
+	var fromBson TimeTest
+	assert.Nil(t, bson.Unmarshal(b, &fromBson))
+	assert.Equal(t, s, fromBson)
+
+	b, _ = bson.Marshal(bson.D{{"day", int32(1)}})
+	assert.NotNil(t, bson.Unmarshal(b, &fromBson))
+}
+
+func TestTimeOfDay(t *testing.T) {
+	tod, err := types.ParseTimeOfDay("08:30")
+	assert.Nil(t, err)
+	assert.Equal(t, types.NewTimeOfDay(8, 30, 0), tod)
+	assert.Equal(t, "08:30:00", tod.String())
+	assert.Equal(t, 8*time.Hour+30*time.Minute, tod.Duration())
+	assert.Equal(t, time.Date(2024, 2, 29, 8, 30, 0, 0, time.UTC), tod.On(types.NewDate(2024, 2, 29), time.UTC))
+
+	tod, err = types.ParseTimeOfDay("23:59:59.999")
+	assert.Nil(t, err)
+	assert.Equal(t, types.TimeOfDay{Hour: 23, Minute: 59, Second: 59, Nanosecond: 999000000}, tod)
+
+	_, err = types.ParseTimeOfDay("24:00")
+	assert.NotNil(t, err)
+
+	var s TimeTest
+	assert.NotNil(t, json.Unmarshal([]byte(`{"time":"noon"}`), &s))
+
+	b, _ := bson.Marshal(bson.D{{"time", int32(1)}})
+	assert.NotNil(t, bson.Unmarshal(b, &s))
+}
@@ -1,8 +1,117 @@
 package types
 
-import "go.mongodb.org/mongo-driver/v2/bson"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ErrOverflow is returned, if a decoded number does not fit into the target type.
+var ErrOverflow = errors.New("number out of range")
 
 func marshalBsonValue(data any) (byte, []byte, error) {
 	typ, v, err := bson.MarshalValue(data)
 	return byte(typ), v, err
 }
+
+// unmarshalJsonNumber decodes JSON data into the number pointed to by v, JSON null decodes into 0.
+func unmarshalJsonNumber[T int32 | int64 | float32 | float64](data []byte, v *T) error {
+	if string(data) == "null" {
+		*v = 0
+		return nil
+	}
+
+	var n T
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+
+	*v = n
+	return nil
+}
+
+// isBsonNull reports whether the BSON type is null or undefined.
+func isBsonNull(typ byte) bool {
+	return bson.Type(typ) == bson.TypeNull || bson.Type(typ) == bson.TypeUndefined
+}
+
+// BsonInt converts any BSON number into an integer of the given bit size.
+// Doubles and decimals must not have a fractional part, values not fitting into the bit size return ErrOverflow.
+func BsonInt(val bson.RawValue, bits int) (int64, error) {
+	var i int64
+	switch val.Type {
+	case bson.TypeInt32:
+		i = int64(val.Int32())
+	case bson.TypeInt64:
+		i = val.Int64()
+	case bson.TypeDouble:
+		f := val.Double()
+		if f != math.Trunc(f) {
+			return 0, fmt.Errorf("can not decode %v into an integer", f)
+		}
+		if f < math.MinInt64 || f >= math.MaxInt64 {
+			return 0, fmt.Errorf("%w: %v", ErrOverflow, f)
+		}
+		i = int64(f)
+	case bson.TypeDecimal128:
+		d := val.Decimal128()
+		bi, exp, err := d.BigInt()
+		if err != nil {
+			return 0, err
+		}
+		scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(max(exp, -exp))), nil)
+		if exp >= 0 {
+			bi.Mul(bi, scale)
+		} else if _, rem := bi.QuoRem(bi, scale, new(big.Int)); rem.Sign() != 0 {
+			return 0, fmt.Errorf("can not decode %s into an integer", d)
+		}
+		if !bi.IsInt64() {
+			return 0, fmt.Errorf("%w: %s", ErrOverflow, d)
+		}
+		i = bi.Int64()
+	default:
+		return 0, fmt.Errorf("wrong bson type %s expected number", val.Type)
+	}
+
+	if bits == 32 && (i < math.MinInt32 || i > math.MaxInt32) {
+		return 0, fmt.Errorf("%w: %d", ErrOverflow, i)
+	}
+
+	return i, nil
+}
+
+// unmarshalBsonFloat decodes any BSON number into a float of the given bit size,
+// values exceeding the bit size return ErrOverflow.
+func unmarshalBsonFloat(typ byte, data []byte, bits int) (float64, error) {
+	val := bson.RawValue{Type: bson.Type(typ), Value: data}
+
+	var f float64
+	switch val.Type {
+	case bson.TypeInt32:
+		f = float64(val.Int32())
+	case bson.TypeInt64:
+		f = float64(val.Int64())
+	case bson.TypeDouble:
+		f = val.Double()
+	case bson.TypeDecimal128:
+		d := val.Decimal128()
+		var err error
+		f, err = strconv.ParseFloat(d.String(), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s", ErrOverflow, d)
+		}
+	default:
+		return 0, fmt.Errorf("wrong bson type %s expected number", val.Type)
+	}
+
+	if bits == 32 && !math.IsInf(f, 0) && math.Abs(f) > math.MaxFloat32 {
+		return 0, fmt.Errorf("%w: %v", ErrOverflow, f)
+	}
+
+	return f, nil
+}
@@ -4,27 +4,60 @@
 package types
 
 import (
+	"encoding/binary"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 type UUID string
 
+var ErrUnsupportedUuidVersion = errors.New("unsupported uuid version")
+
 var uuidGenerator = uuid.NewString
 
+// uuidGenerators holds the generators of the versions supported by SetUuidVersion.
+var uuidGenerators = map[int]func() string{
+	4: uuid.NewString,
+	6: func() string { return uuid.Must(uuid.NewV6()).String() },
+	7: func() string { return uuid.Must(uuid.NewV7()).String() },
+}
+
 // SetUuidGenerator sets a custom function for generating UUID strings.
 // This is mainly used for testing purposes.
 func SetUuidGenerator(g func() string) {
 	uuidGenerator = g
 }
 
+// SetUuidVersion sets the version of the UUIDs generated by NewUuid, which is either 4 (random, the default),
+// or the time-ordered versions 6 and 7. Time-ordered UUIDs keep the index on _id compact, if they are used as primary key.
+// It replaces a generator set by SetUuidGenerator.
+func SetUuidVersion(version int) error {
+	g, ok := uuidGenerators[version]
+	if !ok {
+		return fmt.Errorf("%w: %d", ErrUnsupportedUuidVersion, version)
+	}
+
+	uuidGenerator = g
+	return nil
+}
+
 // NewUuid generates a new UUID using the configured uuidGenerator function and returns it as a UUID type.
 func NewUuid() UUID {
 	return UUID(uuidGenerator())
 }
 
+// NewUuidV7 generates a new time-ordered UUID of version 7, regardless of the configured generator.
+func NewUuidV7() UUID {
+	return UUID(uuidGenerators[7]())
+}
+
 // String converts the UUID to its string representation.
 func (u UUID) String() string {
 	return string(u)
@@ -35,6 +68,55 @@ func (u UUID) IsZero() bool {
 	return len(u) == 0
 }
 
+// Version returns the version of the UUID, or 0 if the UUID is not valid.
+func (u UUID) Version() int {
+	uid, err := uuid.Parse(string(u))
+	if err != nil {
+		return 0
+	}
+
+	return int(uid.Version())
+}
+
+// Variant returns the variant of the UUID, e.g. "RFC4122", or "Invalid" if the UUID is not valid.
+func (u UUID) Variant() string {
+	uid, err := uuid.Parse(string(u))
+	if err != nil {
+		return uuid.Invalid.String()
+	}
+
+	return uid.Variant().String()
+}
+
+// Time returns the time embedded into UUIDs of the versions 1, 6 and 7, ok is false for other versions or invalid UUIDs.
+// The time of version 7 has a precision of milliseconds.
+func (u UUID) Time() (t time.Time, ok bool) {
+	uid, err := uuid.Parse(string(u))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	ts := uid.Time()
+	switch uid.Version() {
+	case 6:
+		// uuid.Time does not remove the version bits of version 6
+		b := binary.BigEndian.Uint64(uid[:8])
+		ts = uuid.Time(b>>16<<12 | b&0xfff)
+	case 1, 7:
+	default:
+		return time.Time{}, false
+	}
+
+	sec, nsec := ts.UnixTime()
+	return time.Unix(sec, nsec).UTC(), true
+}
+
+// Compare returns -1 if u is less than other, 0 if they are equal and +1 if u is greater than other.
+// UUIDs are ordered by their bytes like by the server, so UUIDs of version 6 and 7 are ordered by their time.
+func (u UUID) Compare(other UUID) int {
+	return strings.Compare(strings.ToLower(string(u)), strings.ToLower(string(other)))
+}
+
 // UuidFromString converts a string representation of a UUID to a UUID type. Returns an error if the string is not a valid UUID.
 func UuidFromString(id string) (UUID, error) {
 	u, err := uuid.Parse(id)
@@ -67,11 +149,21 @@ func (u *UUID) UnmarshalJSON(data []byte) error {
 }
 
 // MarshalBSONValue marshals the UUID into a BSON value. Returns BSON type, byte slice, and an error if any.
+// If enabled by SetUuidLegacyEncoding, the UUID is stored as subtype 3 using the legacy representation.
 func (u UUID) MarshalBSONValue() (byte, []byte, error) {
 	if u.IsZero() {
 		return byte(bson.TypeNull), nil, nil
 	}
 
+	if uuidLegacyEncoding && uuidLegacyRepresentation != UuidStandard {
+		data, err := u.LegacyBytes(uuidLegacyRepresentation)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		return marshalBsonValue(bson.Binary{Subtype: bson.TypeBinaryUUIDOld, Data: data})
+	}
+
 	uid, err := uuid.Parse(string(u))
 	if err != nil {
 		return 0, nil, err
@@ -90,6 +182,7 @@ func (u UUID) MarshalBSONValue() (byte, []byte, error) {
 }
 
 // UnmarshalBSONValue deserializes a BSON value into a UUID. Returns an error if the BSON type or subtype is incorrect.
+// The legacy subtype 3 is decoded using the representation set by SetUuidLegacyRepresentation.
 func (u *UUID) UnmarshalBSONValue(typ byte, data []byte) error {
 	t := bson.Type(typ)
 	if t == bson.TypeNull {
@@ -108,6 +201,16 @@ func (u *UUID) UnmarshalBSONValue(typ byte, data []byte) error {
 		return err
 	}
 
+	if bin.Subtype == bson.TypeBinaryUUIDOld && uuidLegacyRepresentation != UuidStandard {
+		legacy, err := UuidFromLegacyBytes(bin.Data, uuidLegacyRepresentation)
+		if err != nil {
+			return err
+		}
+
+		*u = legacy
+		return nil
+	}
+
 	if bin.Subtype != bson.TypeBinaryUUID {
 		return errors.New("wrong subtype")
 	}
@@ -121,3 +224,75 @@ func (u *UUID) UnmarshalBSONValue(typ byte, data []byte) error {
 
 	return nil
 }
+
+// UuidRepresentation defines the byte order of UUIDs stored with the legacy binary subtype 3 by older drivers.
+type UuidRepresentation int
+
+const (
+	// UuidStandard rejects the legacy subtype 3, UUIDs are stored with subtype 4.
+	UuidStandard UuidRepresentation = iota
+	// UuidJavaLegacy is the representation of the legacy Java driver, the bytes of both halves are reversed.
+	UuidJavaLegacy
+	// UuidCSharpLegacy is the representation of the legacy C# driver, the first three groups are little endian.
+	UuidCSharpLegacy
+	// UuidPythonLegacy is the representation of the legacy Python driver, the bytes are in standard order.
+	UuidPythonLegacy
+)
+
+var uuidLegacyRepresentation = UuidStandard
+
+var uuidLegacyEncoding = false
+
+// SetUuidLegacyRepresentation sets the representation used for decoding UUIDs with the legacy binary subtype 3,
+// with UuidStandard, which is the default, subtype 3 is rejected.
+func SetUuidLegacyRepresentation(r UuidRepresentation) {
+	uuidLegacyRepresentation = r
+}
+
+// SetUuidLegacyEncoding stores UUIDs with the legacy subtype 3 using the representation set by SetUuidLegacyRepresentation,
+// e.g. while other applications still read the data using a legacy driver.
+func SetUuidLegacyEncoding(enabled bool) {
+	uuidLegacyEncoding = enabled
+}
+
+// legacyByteOrder converts the bytes between the standard and the legacy representation, the conversion is symmetric.
+func legacyByteOrder(data []byte, r UuidRepresentation) []byte {
+	b := slices.Clone(data)
+
+	switch r {
+	case UuidJavaLegacy:
+		slices.Reverse(b[0:8])
+		slices.Reverse(b[8:16])
+	case UuidCSharpLegacy:
+		slices.Reverse(b[0:4])
+		slices.Reverse(b[4:6])
+		slices.Reverse(b[6:8])
+	}
+
+	return b
+}
+
+// UuidFromLegacyBytes converts the 16 bytes of a UUID stored with the legacy subtype 3 using the representation r,
+// UuidStandard takes the bytes in standard order.
+func UuidFromLegacyBytes(data []byte, r UuidRepresentation) (UUID, error) {
+	if len(data) != 16 {
+		return "", fmt.Errorf("invalid UUID length: %d", len(data))
+	}
+
+	uid, err := uuid.FromBytes(legacyByteOrder(data, r))
+	if err != nil {
+		return "", err
+	}
+
+	return UUID(uid.String()), nil
+}
+
+// LegacyBytes returns the 16 bytes of the UUID in the legacy representation r, UuidStandard returns the standard byte order.
+func (u UUID) LegacyBytes(r UuidRepresentation) ([]byte, error) {
+	uid, err := uuid.Parse(string(u))
+	if err != nil {
+		return nil, err
+	}
+
+	return legacyByteOrder(uid[:], r), nil
+}
@@ -1,11 +1,14 @@
 package types_test
 
 import (
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"github.com/mbretter/go-mongodb/v2/types"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"testing"
+	"time"
 )
 
 type UuidTest struct {
@@ -159,3 +162,129 @@ func TestUUID_UnmarshalBSONInvalidUuid(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Equal(t, "error decoding key uuid: invalid UUID (got 2 bytes)", err.Error())
 }
+
+func TestUUID_NewV7(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+	u := types.NewUuidV7()
+	u2 := types.NewUuidV7()
+
+	assert.Equal(t, 7, u.Version())
+	assert.Equal(t, "RFC4122", u.Variant())
+	assert.Equal(t, -1, u.Compare(u2))
+
+	ts, ok := u.Time()
+	assert.True(t, ok)
+	assert.False(t, ts.Before(before))
+	assert.False(t, ts.After(time.Now()))
+}
+
+func TestUUID_SetVersion(t *testing.T) {
+	defer types.SetUuidVersion(4)
+
+	for _, version := range []int{4, 6, 7} {
+		assert.Nil(t, types.SetUuidVersion(version))
+		assert.Equal(t, version, types.NewUuid().Version())
+	}
+
+	assert.True(t, errors.Is(types.SetUuidVersion(5), types.ErrUnsupportedUuidVersion))
+}
+
+func TestUUID_Time(t *testing.T) {
+	tests := []struct {
+		uuid types.UUID
+		time time.Time
+		ok   bool
+	}{
+		{"017f22e2-79b0-7cc3-98c4-dc0c0c07398f", time.UnixMilli(1645557742000).UTC(), true},
+		{"1ec9414c-232a-6b00-b3c8-9f6bdeced846", time.UnixMilli(1645557742000).UTC(), true},
+		{"c232ab00-9414-11ec-b3c8-9f6bdeced846", time.UnixMilli(1645557742000).UTC(), true},
+		{"f47ac10b-58cc-4372-8567-0e02b2c3d479", time.Time{}, false},
+		{"xxx", time.Time{}, false},
+	}
+
+	for _, test := range tests {
+		ts, ok := test.uuid.Time()
+		assert.Equal(t, test.ok, ok, test.uuid)
+		assert.Equal(t, test.time, ts, test.uuid)
+	}
+}
+
+func TestUUID_VersionVariant(t *testing.T) {
+	assert.Equal(t, 4, types.UUID("f47ac10b-58cc-4372-8567-0e02b2c3d479").Version())
+	assert.Equal(t, "RFC4122", types.UUID("f47ac10b-58cc-4372-8567-0e02b2c3d479").Variant())
+	assert.Equal(t, "Microsoft", types.UUID("f47ac10b-58cc-4372-c567-0e02b2c3d479").Variant())
+	assert.Equal(t, 0, types.UUID("").Version())
+	assert.Equal(t, "Invalid", types.UUID("xxx").Variant())
+}
+
+func TestUUID_Compare(t *testing.T) {
+	a := types.UUID("017f22e2-79b0-7cc3-98c4-dc0c0c07398f")
+	b := types.UUID("017f22e2-79b1-7cc3-98c4-dc0c0c07398f")
+
+	assert.Equal(t, -1, a.Compare(b))
+	assert.Equal(t, 1, b.Compare(a))
+	assert.Equal(t, 0, a.Compare("017F22E2-79B0-7CC3-98C4-DC0C0C07398F"))
+}
+
+func TestUUID_LegacyRepresentations(t *testing.T) {
+	u := types.UUID("00112233-4455-6677-8899-aabbccddeeff")
+
+	tests := []struct {
+		name string
+		r    types.UuidRepresentation
+		data string
+	}{
+		{"java", types.UuidJavaLegacy, "7766554433221100ffeeddccbbaa9988"},
+		{"csharp", types.UuidCSharpLegacy, "33221100554477668899aabbccddeeff"},
+		{"python", types.UuidPythonLegacy, "00112233445566778899aabbccddeeff"},
+	}
+
+	defer types.SetUuidLegacyRepresentation(types.UuidStandard)
+	defer types.SetUuidLegacyEncoding(false)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, _ := hex.DecodeString(test.data)
+
+			legacy, err := u.LegacyBytes(test.r)
+			assert.Nil(t, err)
+			assert.Equal(t, data, legacy)
+
+			fromLegacy, err := types.UuidFromLegacyBytes(data, test.r)
+			assert.Nil(t, err)
+			assert.Equal(t, u, fromLegacy)
+
+			b, _ := bson.Marshal(bson.D{{"uuid", bson.Binary{Subtype: bson.TypeBinaryUUIDOld, Data: data}}})
+
+			types.SetUuidLegacyRepresentation(test.r)
+			types.SetUuidLegacyEncoding(false)
+
+			var s UuidTest
+			assert.Nil(t, bson.Unmarshal(b, &s))
+			assert.Equal(t, u, s.Uid)
+
+			standard, _ := bson.Marshal(UuidTest{Uid: u})
+			assert.NotEqual(t, b, standard)
+
+			types.SetUuidLegacyEncoding(true)
+			encoded, _ := bson.Marshal(UuidTest{Uid: u})
+			assert.Equal(t, b, encoded)
+		})
+	}
+}
+
+func TestUUID_LegacyRejected(t *testing.T) {
+	data, _ := hex.DecodeString("00112233445566778899aabbccddeeff")
+	b, _ := bson.Marshal(bson.D{{"uuid", bson.Binary{Subtype: bson.TypeBinaryUUIDOld, Data: data}}})
+
+	var s UuidTest
+	err := bson.Unmarshal(b, &s)
+	assert.NotNil(t, err)
+	assert.Equal(t, "error decoding key uuid: wrong subtype", err.Error())
+
+	_, err = types.UuidFromLegacyBytes(data[:8], types.UuidJavaLegacy)
+	assert.NotNil(t, err)
+
+	_, err = types.UUID("xxx").LegacyBytes(types.UuidJavaLegacy)
+	assert.NotNil(t, err)
+}
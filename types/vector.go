@@ -0,0 +1,109 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Vector is a vector of float32 or int8 values, e.g. an embedding. It is stored as BSON binary of the vector subtype 9,
+// which is much more compact than an array and required by vector search indexes on quantized vectors.
+// The JSON representation is an array of numbers. For migration BSON arrays of numbers are decoded as well.
+type Vector[T float32 | int8] []T
+
+// MarshalJSON serializes the vector as array of numbers, an empty vector is rendered to null.
+func (v Vector[T]) MarshalJSON() ([]byte, error) {
+	if len(v) == 0 {
+		return json.Marshal(nil)
+	}
+
+	return json.Marshal([]T(v))
+}
+
+// UnmarshalJSON deserializes an array of numbers into the vector, JSON null results in an empty vector.
+func (v *Vector[T]) UnmarshalJSON(data []byte) error {
+	var values []T
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	*v = values
+	return nil
+}
+
+// MarshalBSONValue serializes the vector into a BSON binary of the vector subtype, an empty vector is rendered to BSON null.
+func (v Vector[T]) MarshalBSONValue() (byte, []byte, error) {
+	if len(v) == 0 {
+		return byte(bson.TypeNull), nil, nil
+	}
+
+	return marshalBsonValue(bson.NewVector([]T(v)).Binary())
+}
+
+// UnmarshalBSONValue deserializes a BSON binary of the vector subtype or a BSON array of numbers into the vector.
+// The dtype of the binary must match the element type of the vector. BSON null results in an empty vector.
+func (v *Vector[T]) UnmarshalBSONValue(typ byte, data []byte) error {
+	val := bson.RawValue{Type: bson.Type(typ), Value: data}
+
+	switch {
+	case isBsonNull(typ):
+		*v = nil
+		return nil
+	case val.Type == bson.TypeArray:
+		return v.unmarshalArray(val.Array())
+	case val.Type != bson.TypeBinary:
+		return errors.New("wrong bson type expected binary or array")
+	}
+
+	sub, bin := val.Binary()
+	if sub != bson.TypeBinaryVector {
+		return errors.New("wrong bson subtype expected vector")
+	}
+
+	vec, err := bson.NewVectorFromBinary(bson.Binary{Subtype: sub, Data: bin})
+	if err != nil {
+		return err
+	}
+
+	var ok bool
+	switch p := any(v).(type) {
+	case *Vector[float32]:
+		*p, ok = vec.Float32OK()
+	case *Vector[int8]:
+		*p, ok = vec.Int8OK()
+	}
+
+	if !ok {
+		return fmt.Errorf("wrong vector dtype %#02x", vec.Type())
+	}
+
+	return nil
+}
+
+// unmarshalArray decodes an array of numbers, int8 elements must be integers in range.
+func (v *Vector[T]) unmarshalArray(arr bson.RawArray) error {
+	elems, err := arr.Values()
+	if err != nil {
+		return err
+	}
+
+	values := make([]T, len(elems))
+	for i, elem := range elems {
+		f, ok := elem.AsFloat64OK()
+		if !ok {
+			return fmt.Errorf("wrong bson type %s of vector element %d", elem.Type, i)
+		}
+
+		if _, isInt8 := any(values[i]).(int8); isInt8 && (f != math.Trunc(f) || f < math.MinInt8 || f > math.MaxInt8) {
+			return fmt.Errorf("%w: vector element %d: %v", ErrOverflow, i, f)
+		}
+
+		values[i] = T(f)
+	}
+
+	*v = values
+	return nil
+}
@@ -0,0 +1,114 @@
+package types_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mbretter/go-mongodb/v2/types"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type VectorTest struct {
+	Embedding types.Vector[float32] `bson:"embedding" json:"embedding"`
+	Quantized types.Vector[int8]    `bson:"quantized" json:"quantized"`
+}
+
+func TestVector_BSON(t *testing.T) {
+	s := VectorTest{
+		Embedding: types.Vector[float32]{0.5, -1.25, 2},
+		Quantized: types.Vector[int8]{-128, 0, 127},
+	}
+
+	data, err := bson.Marshal(s)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	sub, bin := bson.Raw(data).Lookup("embedding").Binary()
+	assert.Equal(t, bson.TypeBinaryVector, sub)
+	// dtype float32, padding 0, followed by 3 little endian floats
+	assert.Equal(t, []byte{0x27, 0x00}, bin[:2])
+	assert.Len(t, bin, 2+3*4)
+
+	sub, bin = bson.Raw(data).Lookup("quantized").Binary()
+	assert.Equal(t, bson.TypeBinaryVector, sub)
+	assert.Equal(t, []byte{0x03, 0x00, 0x80, 0x00, 0x7f}, bin)
+
+	var d VectorTest
+	if assert.Nil(t, bson.Unmarshal(data, &d)) {
+		assert.Equal(t, s, d)
+	}
+}
+
+func TestVector_BSONNull(t *testing.T) {
+	data, err := bson.Marshal(VectorTest{})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Equal(t, bson.TypeNull, bson.Raw(data).Lookup("embedding").Type)
+
+	d := VectorTest{Embedding: types.Vector[float32]{1}}
+	if assert.Nil(t, bson.Unmarshal(data, &d)) {
+		assert.Nil(t, d.Embedding)
+	}
+}
+
+func TestVector_UnmarshalBSONArray(t *testing.T) {
+	data, _ := bson.Marshal(bson.D{
+		{"embedding", bson.A{0.5, int32(1), int64(-2)}},
+		{"quantized", bson.A{1.0, int32(-3)}},
+	})
+
+	var d VectorTest
+	if assert.Nil(t, bson.Unmarshal(data, &d)) {
+		assert.Equal(t, types.Vector[float32]{0.5, 1, -2}, d.Embedding)
+		assert.Equal(t, types.Vector[int8]{1, -3}, d.Quantized)
+	}
+}
+
+func TestVector_UnmarshalBSONInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		val  interface{}
+		err  string
+	}{
+		{"type", "foo", "wrong bson type expected binary or array"},
+		{"subtype", bson.Binary{Subtype: bson.TypeBinaryGeneric, Data: []byte{1}}, "wrong bson subtype expected vector"},
+		{"dtype", bson.NewVector([]float32{1}).Binary(), "wrong vector dtype 0x27"},
+		{"element", bson.A{"a"}, "wrong bson type string of vector element 0"},
+		{"overflow", bson.A{128}, "number out of range"},
+		{"fraction", bson.A{1.5}, "number out of range"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, _ := bson.Marshal(bson.D{{"quantized", test.val}})
+
+			var d VectorTest
+			assert.ErrorContains(t, bson.Unmarshal(data, &d), test.err)
+		})
+	}
+}
+
+func TestVector_JSON(t *testing.T) {
+	s := VectorTest{
+		Embedding: types.Vector[float32]{0.5, -1.25},
+		Quantized: types.Vector[int8]{-1, 2},
+	}
+
+	j, err := json.Marshal(s)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, `{"embedding":[0.5,-1.25],"quantized":[-1,2]}`, string(j))
+
+	var d VectorTest
+	if assert.Nil(t, json.Unmarshal(j, &d)) {
+		assert.Equal(t, s, d)
+	}
+
+	j, _ = json.Marshal(VectorTest{})
+	assert.Equal(t, `{"embedding":null,"quantized":null}`, string(j))
+}
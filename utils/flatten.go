@@ -11,6 +11,16 @@ import (
 // this code has been copied from https://github.com/chidiwilliams/flatbson
 // and modified to work with mongodb v2 driver
 
+// absenter is implemented by values, which can be absent, e.g. types.Optional.
+type absenter interface {
+	IsAbsent() bool
+}
+
+// nuller is implemented by nullable values, e.g. types.Null and types.Optional.
+type nuller interface {
+	IsNull() bool
+}
+
 // Flatten returns a map with keys and values corresponding to the field name
 // and values of struct v and its nested structs according to its BSON tags.
 // It iterates over each field recursively and sets fields that are not nil.
@@ -19,6 +29,8 @@ import (
 // https://godoc.org/go.mongodb.org/mongo-driver/bson/bsoncodec#StructTags
 // for definitions. The supported tags are name, skip, omitempty, and inline.
 //
+// Fields implementing IsAbsent, e.g. an absent types.Optional, are skipped.
+//
 // Flatten does not flatten structs with unexported fields, e.g. time.Time.
 // It returns an error if v is not a struct or a pointer to a struct, or if
 // the tags produce duplicate keys.
@@ -49,6 +61,44 @@ func Flatten(v interface{}) (map[string]interface{}, error) {
 	return m, nil
 }
 
+// FlattenUpdate flattens v like Flatten and returns an update document, the fields are set using $set,
+// except null fields implementing IsNull, e.g. types.Null or types.Optional, which are removed using $unset.
+//
+//	type A struct {
+//	  B types.Optional[string] `bson:"b"`
+//	  C types.Optional[string] `bson:"c"`
+//	  D types.Optional[string] `bson:"d"`
+//	}
+//
+//	FlattenUpdate(A{B: types.OptionalOf("x"), C: types.OptionalNull[string]()})
+//	// Returns:
+//	// bson.D{{"$set", bson.M{"b": types.OptionalOf("x")}}, {"$unset", bson.M{"c": ""}}}
+func FlattenUpdate(v interface{}) (bson.D, error) {
+	flat, err := Flatten(v)
+	if err != nil {
+		return nil, err
+	}
+
+	set, unset := bson.M{}, bson.M{}
+	for key, value := range flat {
+		if n, ok := value.(nuller); ok && n.IsNull() {
+			unset[key] = ""
+			continue
+		}
+		set[key] = value
+	}
+
+	update := bson.D{}
+	if len(set) > 0 {
+		update = append(update, bson.E{"$set", set})
+	}
+	if len(unset) > 0 {
+		update = append(update, bson.E{"$unset", unset})
+	}
+
+	return update, nil
+}
+
 // flattenFields recursively adds the values of v's fields to map m.
 func flattenFields(v reflect.Value, m map[string]interface{}, p string) error {
 	for i := 0; i < v.NumField(); i++ {
@@ -64,6 +114,10 @@ func flattenFields(v reflect.Value, m map[string]interface{}, p string) error {
 			continue
 		}
 
+		if a, ok := field.Interface().(absenter); ok && a.IsAbsent() {
+			continue
+		}
+
 		// If the field can marshal itself into a BSON type, or it's a struct has no
 		// exported fields, like time.Time, we shouldn't recurse into its fields.
 		if _, ok := field.Interface().(bson.ValueMarshaler); !ok {
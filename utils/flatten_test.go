@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/mbretter/go-mongodb/v2/types"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
@@ -119,6 +120,13 @@ func (c customBSONTypeLeaf) MarshalBSONValue() (byte, []byte, error) {
 	return byte(typ), v, err
 }
 
+type optionalRoot struct {
+	A types.Optional[string] `bson:"a"`
+	B types.Optional[int]    `bson:"b"`
+	C types.Optional[string] `bson:"c"`
+	D types.Null[string]     `bson:"d"`
+}
+
 func TestFlatten(t *testing.T) {
 	type args struct {
 		v interface{}
@@ -190,6 +198,11 @@ func TestFlatten(t *testing.T) {
 			args: args{rootWithExportedField{"az", 5, 10}},
 			want: map[string]interface{}{"a": "az", "b": 5},
 		},
+		{
+			name: "optional fields",
+			args: args{optionalRoot{A: types.OptionalOf("abc"), B: types.OptionalNull[int]()}},
+			want: map[string]interface{}{"a": types.OptionalOf("abc"), "b": types.OptionalNull[int](), "d": types.Null[string]{}},
+		},
 		{
 			name: "nested struct has unexported field",
 			args: args{mixedExportedUnexportedRoot{mixedExportedUnexportedLeaf{"abc", "bc"}}},
@@ -210,3 +223,27 @@ func TestFlatten(t *testing.T) {
 		})
 	}
 }
+
+func TestFlattenUpdate(t *testing.T) {
+	update, err := FlattenUpdate(optionalRoot{A: types.OptionalOf(""), B: types.OptionalNull[int](), D: types.NullOf("x")})
+	if err != nil {
+		t.Fatalf("FlattenUpdate() error = %v", err)
+	}
+
+	want := bson.D{
+		{"$set", bson.M{"a": types.OptionalOf(""), "d": types.NullOf("x")}},
+		{"$unset", bson.M{"b": ""}},
+	}
+	if !reflect.DeepEqual(update, want) {
+		t.Errorf("FlattenUpdate() got = %v, want %v", update, want)
+	}
+
+	update, _ = FlattenUpdate(optionalRoot{})
+	if !reflect.DeepEqual(update, bson.D{{"$unset", bson.M{"d": ""}}}) {
+		t.Errorf("FlattenUpdate() got = %v", update)
+	}
+
+	if _, err := FlattenUpdate(23); err == nil {
+		t.Error("FlattenUpdate() expected error")
+	}
+}
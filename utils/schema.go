@@ -0,0 +1,246 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/mbretter/go-mongodb/v2/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var (
+	tTime        = reflect.TypeOf(time.Time{})
+	tObjectId    = reflect.TypeOf(types.ObjectId(""))
+	tUUID        = reflect.TypeOf(types.UUID(""))
+	tBinary      = reflect.TypeOf(types.Binary{})
+	tNullString  = reflect.TypeOf(types.NullString(""))
+	tNullInt32   = reflect.TypeOf(types.NullInt32(0))
+	tNullInt64   = reflect.TypeOf(types.NullInt64(0))
+	tNullFloat32 = reflect.TypeOf(types.NullFloat32(0))
+	tNullFloat64 = reflect.TypeOf(types.NullFloat64(0))
+	tBsonOid     = reflect.TypeOf(bson.ObjectID{})
+	tBsonDate    = reflect.TypeOf(bson.DateTime(0))
+	tBsonDecimal = reflect.TypeOf(bson.Decimal128{})
+	tBsonBinary  = reflect.TypeOf(bson.Binary{})
+	tBsonM       = reflect.TypeOf(bson.M{})
+	tBsonD       = reflect.TypeOf(bson.D{})
+
+	tValueMarshaler = reflect.TypeOf((*bson.ValueMarshaler)(nil)).Elem()
+)
+
+// JsonSchema generates a MongoDB $jsonSchema document from the struct v according to its BSON tags.
+// It can be used as validator by wrapping it into bson.M{"$jsonSchema": schema}.
+//
+// Fields are required, unless they are pointers or tagged with omitempty. Pointers are nullable,
+// the package datatypes (ObjectId, UUID, Binary, NullString and the null numbers) are nullable too,
+// because they are stored as null if empty.
+//
+// Fields with types the schema can not be derived from, like interfaces or types implementing
+// bson.ValueMarshaler, are added without any type restriction. Recursive structs are described
+// down to the first repetition, which is added as object without properties.
+//
+//	type User struct {
+//	  Id       types.ObjectId   `bson:"_id"`
+//	  Username string           `bson:"username"`
+//	  Email    types.NullString `bson:"email,omitempty"`
+//	}
+//
+//	JsonSchema(User{})
+//	// Returns:
+//	// bson.M{
+//	//   "bsonType": "object",
+//	//   "required": []string{"_id", "username"},
+//	//   "properties": bson.M{
+//	//     "_id":      bson.M{"bsonType": bson.A{"objectId", "null"}},
+//	//     "username": bson.M{"bsonType": "string"},
+//	//     "email":    bson.M{"bsonType": bson.A{"string", "null"}},
+//	//   },
+//	// }
+func JsonSchema(v interface{}) (bson.M, error) {
+	typ := reflect.TypeOf(v)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, errors.New("v must be a struct or a pointer to a struct")
+	}
+
+	return structSchema(typ, false, map[reflect.Type]bool{})
+}
+
+// structSchema builds the object schema of struct type t, visiting holds the structs being described.
+func structSchema(t reflect.Type, nullable bool, visiting map[reflect.Type]bool) (bson.M, error) {
+	if visiting[t] {
+		return bson.M{"bsonType": bsonTypes(nullable, "object")}, nil
+	}
+
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	properties := bson.M{}
+	required := make([]string, 0)
+
+	if err := schemaFields(t, properties, &required, visiting); err != nil {
+		return nil, err
+	}
+
+	schema := bson.M{
+		"bsonType":   bsonTypes(nullable, "object"),
+		"properties": properties,
+	}
+
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema, nil
+}
+
+// schemaFields adds the schema of each field of struct type t to properties, inlined structs are merged.
+func schemaFields(t reflect.Type, properties bson.M, required *[]string, visiting map[reflect.Type]bool) error {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		tags, _ := parseStructTags(sf)
+		if tags.Skip {
+			continue
+		}
+
+		if tags.Inline {
+			ft := sf.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if visiting[ft] {
+					return fmt.Errorf("recursive inline struct %s", ft)
+				}
+
+				visiting[ft] = true
+				err := schemaFields(ft, properties, required, visiting)
+				delete(visiting, ft)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if _, ok := properties[tags.Name]; ok {
+			return fmt.Errorf("duplicated key %s", tags.Name)
+		}
+
+		schema, err := typeSchema(sf.Type, tags.MinSize, visiting)
+		if err != nil {
+			return err
+		}
+
+		properties[tags.Name] = schema
+
+		if !tags.OmitEmpty && sf.Type.Kind() != reflect.Ptr {
+			*required = append(*required, tags.Name)
+		}
+	}
+
+	return nil
+}
+
+// typeSchema returns the schema for the given field type.
+func typeSchema(t reflect.Type, minSize bool, visiting map[reflect.Type]bool) (bson.M, error) {
+	nullable := false
+	for t.Kind() == reflect.Ptr {
+		nullable = true
+		t = t.Elem()
+	}
+
+	switch t {
+	case tObjectId:
+		return bson.M{"bsonType": bsonTypes(true, "objectId")}, nil
+	case tUUID, tBinary:
+		return bson.M{"bsonType": bsonTypes(true, "binData")}, nil
+	case tNullString:
+		return bson.M{"bsonType": bsonTypes(true, "string")}, nil
+	case tNullInt32:
+		return bson.M{"bsonType": bsonTypes(true, "int")}, nil
+	case tNullInt64:
+		return bson.M{"bsonType": bsonTypes(true, "long")}, nil
+	case tNullFloat32, tNullFloat64:
+		return bson.M{"bsonType": bsonTypes(true, "double")}, nil
+	case tTime, tBsonDate:
+		return bson.M{"bsonType": bsonTypes(nullable, "date")}, nil
+	case tBsonOid:
+		return bson.M{"bsonType": bsonTypes(nullable, "objectId")}, nil
+	case tBsonDecimal:
+		return bson.M{"bsonType": bsonTypes(nullable, "decimal")}, nil
+	case tBsonBinary:
+		return bson.M{"bsonType": bsonTypes(nullable, "binData")}, nil
+	case tBsonM, tBsonD:
+		return bson.M{"bsonType": bsonTypes(nullable, "object")}, nil
+	}
+
+	// types marshalling themselves can not be described
+	if t.Implements(tValueMarshaler) || reflect.PointerTo(t).Implements(tValueMarshaler) {
+		return bson.M{}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return bson.M{"bsonType": bsonTypes(nullable, "bool")}, nil
+	case reflect.String:
+		return bson.M{"bsonType": bsonTypes(nullable, "string")}, nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return bson.M{"bsonType": bsonTypes(nullable, "int")}, nil
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		if minSize || t.Kind() == reflect.Int {
+			return bson.M{"bsonType": bsonTypes(nullable, "int", "long")}, nil
+		}
+		return bson.M{"bsonType": bsonTypes(nullable, "long")}, nil
+	case reflect.Float32, reflect.Float64:
+		return bson.M{"bsonType": bsonTypes(nullable, "double")}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return bson.M{"bsonType": bsonTypes(nullable || t.Kind() == reflect.Slice, "binData")}, nil
+		}
+		items, err := typeSchema(t.Elem(), false, visiting)
+		if err != nil {
+			return nil, err
+		}
+		schema := bson.M{"bsonType": bsonTypes(nullable, "array")}
+		if len(items) > 0 {
+			schema["items"] = items
+		}
+		return schema, nil
+	case reflect.Map:
+		return bson.M{"bsonType": bsonTypes(true, "object")}, nil
+	case reflect.Struct:
+		return structSchema(t, nullable, visiting)
+	case reflect.Interface:
+		return bson.M{}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// bsonTypes returns a single bson type as string, multiple types are returned as bson.A.
+func bsonTypes(nullable bool, typs ...string) interface{} {
+	if nullable {
+		typs = append(typs, "null")
+	}
+
+	if len(typs) == 1 {
+		return typs[0]
+	}
+
+	a := make(bson.A, len(typs))
+	for i, t := range typs {
+		a[i] = t
+	}
+
+	return a
+}
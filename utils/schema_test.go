@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mbretter/go-mongodb/v2/types"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type schemaRoot struct {
+	Id       types.ObjectId    `bson:"_id"`
+	Uid      types.UUID        `bson:"uid"`
+	Data     types.Binary      `bson:"data,omitempty"`
+	Name     string            `bson:"name"`
+	Email    types.NullString  `bson:"email,omitempty"`
+	Count    types.NullInt64   `bson:"count"`
+	Small    types.NullInt32   `bson:"small"`
+	Price    types.NullFloat64 `bson:"price"`
+	Active   bool              `bson:"active"`
+	Age      int               `bson:"age"`
+	Big      int64             `bson:"big"`
+	Created  time.Time         `bson:"created"`
+	Deleted  *time.Time        `bson:"deleted"`
+	Tags     []string          `bson:"tags"`
+	Any      interface{}       `bson:"any,omitempty"`
+	Skipped  string            `bson:"-"`
+	Address  schemaAddress     `bson:"address"`
+	Invoice  *schemaAddress    `bson:"invoice"`
+	Embedded schemaEmbedded    `bson:",inline"`
+	internal string
+}
+
+type schemaAddress struct {
+	Street string `bson:"street"`
+	Zip    string `bson:"zip,omitempty"`
+}
+
+type schemaEmbedded struct {
+	Version int32 `bson:"version"`
+}
+
+type schemaDuplicate struct {
+	A string `bson:"a"`
+	B string `bson:"a"`
+}
+
+type schemaUnsupported struct {
+	C chan int `bson:"c"`
+}
+
+type schemaNode struct {
+	Name     string       `bson:"name"`
+	Parent   *schemaNode  `bson:"parent"`
+	Children []schemaNode `bson:"children,omitempty"`
+}
+
+type schemaRecursiveInline struct {
+	Name string                 `bson:"name"`
+	Next *schemaRecursiveInline `bson:",inline"`
+}
+
+func TestJsonSchema(t *testing.T) {
+	address := bson.M{
+		"bsonType": "object",
+		"properties": bson.M{
+			"street": bson.M{"bsonType": "string"},
+			"zip":    bson.M{"bsonType": "string"},
+		},
+		"required": []string{"street"},
+	}
+
+	invoice := bson.M{
+		"bsonType":   bson.A{"object", "null"},
+		"properties": address["properties"],
+		"required":   []string{"street"},
+	}
+
+	expected := bson.M{
+		"bsonType": "object",
+		"properties": bson.M{
+			"_id":     bson.M{"bsonType": bson.A{"objectId", "null"}},
+			"uid":     bson.M{"bsonType": bson.A{"binData", "null"}},
+			"data":    bson.M{"bsonType": bson.A{"binData", "null"}},
+			"name":    bson.M{"bsonType": "string"},
+			"email":   bson.M{"bsonType": bson.A{"string", "null"}},
+			"count":   bson.M{"bsonType": bson.A{"long", "null"}},
+			"small":   bson.M{"bsonType": bson.A{"int", "null"}},
+			"price":   bson.M{"bsonType": bson.A{"double", "null"}},
+			"active":  bson.M{"bsonType": "bool"},
+			"age":     bson.M{"bsonType": bson.A{"int", "long"}},
+			"big":     bson.M{"bsonType": "long"},
+			"created": bson.M{"bsonType": "date"},
+			"deleted": bson.M{"bsonType": bson.A{"date", "null"}},
+			"tags":    bson.M{"bsonType": "array", "items": bson.M{"bsonType": "string"}},
+			"any":     bson.M{},
+			"address": address,
+			"invoice": invoice,
+			"version": bson.M{"bsonType": "int"},
+		},
+		"required": []string{"_id", "uid", "name", "count", "small", "price", "active", "age", "big", "created", "tags",
+			"address", "version"},
+	}
+
+	schema, err := JsonSchema(&schemaRoot{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, expected, schema)
+}
+
+func TestJsonSchema_Recursive(t *testing.T) {
+	expected := bson.M{
+		"bsonType": "object",
+		"properties": bson.M{
+			"name":     bson.M{"bsonType": "string"},
+			"parent":   bson.M{"bsonType": bson.A{"object", "null"}},
+			"children": bson.M{"bsonType": "array", "items": bson.M{"bsonType": "object"}},
+		},
+		"required": []string{"name"},
+	}
+
+	schema, err := JsonSchema(schemaNode{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, expected, schema)
+}
+
+func TestJsonSchema_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+		err  error
+	}{
+		{"non-struct input", 23, errors.New("v must be a struct or a pointer to a struct")},
+		{"nil input", nil, errors.New("v must be a struct or a pointer to a struct")},
+		{"duplicate keys", schemaDuplicate{}, errors.New("duplicated key a")},
+		{"unsupported type", schemaUnsupported{}, errors.New("unsupported type chan int")},
+		{"recursive inline", schemaRecursiveInline{}, errors.New("recursive inline struct utils.schemaRecursiveInline")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schema, err := JsonSchema(test.v)
+
+			assert.Nil(t, schema)
+			assert.Equal(t, test.err, err)
+		})
+	}
+}
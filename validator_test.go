@@ -0,0 +1,61 @@
+package mongodb_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mbretter/go-mongodb/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var testValidator = bson.M{"$jsonSchema": bson.M{"bsonType": "object"}}
+
+func TestSetValidator_CollMod(t *testing.T) {
+	conn := NewConnectorMock(t)
+	conn.EXPECT().RunCommand(bson.D{
+		{"collMod", "Users"},
+		{"validator", testValidator},
+		{"validationLevel", mongodb.ValidationLevelModerate},
+		{"validationAction", mongodb.ValidationActionWarn},
+	}).Return(mongo.NewSingleResultFromDocument(bson.D{{"ok", 1}}, nil, nil)).Once()
+
+	err := mongodb.SetValidatorWith(conn, "Users", testValidator, mongodb.ValidationLevelModerate, mongodb.ValidationActionWarn)
+	assert.Nil(t, err)
+}
+
+func TestSetValidator_CreateCollection(t *testing.T) {
+	conn := NewConnectorMock(t)
+	conn.EXPECT().RunCommand(bson.D{{"collMod", "Users"}, {"validator", testValidator}, {"validationLevel", mongodb.ValidationLevelStrict}}).
+		Return(mongo.NewSingleResultFromDocument(bson.D{}, mongo.CommandError{Code: 26, Name: "NamespaceNotFound"}, nil)).Once()
+	conn.EXPECT().CreateCollection("Users", mock.Anything).
+		RunAndReturn(func(name string, opts ...options.Lister[options.CreateCollectionOptions]) error {
+			args := options.CreateCollectionOptions{}
+			for _, set := range opts[0].List() {
+				_ = set(&args)
+			}
+
+			assert.Equal(t, testValidator, args.Validator)
+			assert.Equal(t, mongodb.ValidationLevelStrict, *args.ValidationLevel)
+			assert.Nil(t, args.ValidationAction)
+			return nil
+		}).Once()
+
+	err := mongodb.SetValidatorWith(conn, "Users", testValidator, mongodb.ValidationLevelStrict, "")
+	assert.Nil(t, err)
+}
+
+func TestSetValidator_Error(t *testing.T) {
+	cmdErr := mongo.CommandError{Code: 13, Name: "Unauthorized"}
+
+	conn := NewConnectorMock(t)
+	conn.EXPECT().RunCommand(bson.D{{"collMod", "Users"}, {"validator", testValidator}}).
+		Return(mongo.NewSingleResultFromDocument(bson.D{}, cmdErr, nil)).Once()
+
+	err := mongodb.SetValidatorWith(conn, "Users", testValidator, "", "")
+	assert.True(t, errors.As(err, &mongo.CommandError{}))
+	assert.ErrorContains(t, err, "Unauthorized")
+}
@@ -0,0 +1,117 @@
+package mongodb
+
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// similarity functions of vector search indexes
+const (
+	SimilarityCosine     = "cosine"
+	SimilarityEuclidean  = "euclidean"
+	SimilarityDotProduct = "dotProduct"
+)
+
+// VectorIndexParams holds the parameters of a vector search index.
+type VectorIndexParams struct {
+	// Name of the index, defaults to "vector_index".
+	Name string
+	// Path of the field holding the vectors.
+	Path string
+	// Dimensions is the number of elements of the vectors.
+	Dimensions int
+	// Similarity is one of the Similarity* functions, defaults to SimilarityCosine.
+	Similarity string
+	// Quantization is "scalar" or "binary" for automatic quantization of float vectors, optional.
+	Quantization string
+	// Filters are the paths of the fields, which can be used in the filter of VectorSearchStage.
+	Filters []string
+}
+
+// VectorSearchIndexModel returns the model of a vector search index to be created using CreateSearchIndex.
+func VectorSearchIndexModel(params VectorIndexParams) mongo.SearchIndexModel {
+	name := params.Name
+	if len(name) == 0 {
+		name = "vector_index"
+	}
+
+	similarity := params.Similarity
+	if len(similarity) == 0 {
+		similarity = SimilarityCosine
+	}
+
+	vector := bson.D{
+		{"type", "vector"},
+		{"path", params.Path},
+		{"numDimensions", params.Dimensions},
+		{"similarity", similarity},
+	}
+	if len(params.Quantization) > 0 {
+		vector = append(vector, bson.E{"quantization", params.Quantization})
+	}
+
+	fields := bson.A{vector}
+	for _, path := range params.Filters {
+		fields = append(fields, bson.D{{"type", "filter"}, {"path", path}})
+	}
+
+	return mongo.SearchIndexModel{
+		Definition: bson.D{{"fields", fields}},
+		Options:    options.SearchIndexes().SetName(name).SetType("vectorSearch"),
+	}
+}
+
+// VectorSearchParams holds the parameters of a $vectorSearch stage.
+type VectorSearchParams struct {
+	// Index is the name of the vector search index, defaults to "vector_index".
+	Index string
+	// Path of the field holding the vectors.
+	Path string
+	// QueryVector is the vector to search for, e.g. a types.Vector.
+	QueryVector interface{}
+	// Limit is the number of documents returned.
+	Limit int
+	// NumCandidates is the number of nearest neighbours considered by an approximate search, defaults to 10*Limit.
+	NumCandidates int
+	// Exact runs an exact nearest neighbour search instead of an approximate search.
+	Exact bool
+	// Filter is an optional query on the filter fields of the index.
+	Filter interface{}
+}
+
+// VectorSearchStage returns a $vectorSearch aggregation stage, which must be the first stage of the pipeline.
+func VectorSearchStage(params VectorSearchParams) bson.D {
+	index := params.Index
+	if len(index) == 0 {
+		index = "vector_index"
+	}
+
+	search := bson.D{
+		{"index", index},
+		{"path", params.Path},
+		{"queryVector", params.QueryVector},
+		{"limit", params.Limit},
+	}
+
+	if params.Exact {
+		search = append(search, bson.E{"exact", true})
+	} else {
+		candidates := params.NumCandidates
+		if candidates <= 0 {
+			candidates = 10 * params.Limit
+		}
+		search = append(search, bson.E{"numCandidates", candidates})
+	}
+
+	if params.Filter != nil {
+		search = append(search, bson.E{"filter", params.Filter})
+	}
+
+	return bson.D{{"$vectorSearch", search}}
+}
+
+// VectorSearchScoreStage returns an $addFields stage setting field to the score of the preceding $vectorSearch stage.
+func VectorSearchScoreStage(field string) bson.D {
+	return bson.D{{"$addFields", bson.D{{field, bson.D{{"$meta", "vectorSearchScore"}}}}}}
+}
@@ -0,0 +1,86 @@
+package mongodb_test
+
+import (
+	"testing"
+
+	"github.com/mbretter/go-mongodb/v2"
+	"github.com/mbretter/go-mongodb/v2/types"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestVectorSearchIndexModel(t *testing.T) {
+	model := mongodb.VectorSearchIndexModel(mongodb.VectorIndexParams{
+		Path:         "embedding",
+		Dimensions:   3,
+		Similarity:   mongodb.SimilarityDotProduct,
+		Quantization: "scalar",
+		Filters:      []string{"tenant", "lang"},
+	})
+
+	assert.Equal(t, bson.D{{"fields", bson.A{
+		bson.D{{"type", "vector"}, {"path", "embedding"}, {"numDimensions", 3}, {"similarity", "dotProduct"}, {"quantization", "scalar"}},
+		bson.D{{"type", "filter"}, {"path", "tenant"}},
+		bson.D{{"type", "filter"}, {"path", "lang"}},
+	}}}, model.Definition)
+
+	opts := options.SearchIndexesOptions{}
+	for _, set := range model.Options.List() {
+		_ = set(&opts)
+	}
+	assert.Equal(t, "vector_index", *opts.Name)
+	assert.Equal(t, "vectorSearch", *opts.Type)
+}
+
+func TestVectorSearchIndexModel_Defaults(t *testing.T) {
+	model := mongodb.VectorSearchIndexModel(mongodb.VectorIndexParams{Name: "emb", Path: "embedding", Dimensions: 1536})
+
+	assert.Equal(t, bson.D{{"fields", bson.A{
+		bson.D{{"type", "vector"}, {"path", "embedding"}, {"numDimensions", 1536}, {"similarity", "cosine"}},
+	}}}, model.Definition)
+}
+
+func TestVectorSearchStage(t *testing.T) {
+	query := types.Vector[float32]{0.1, 0.2, 0.3}
+
+	stage := mongodb.VectorSearchStage(mongodb.VectorSearchParams{
+		Path:        "embedding",
+		QueryVector: query,
+		Limit:       5,
+		Filter:      bson.D{{"tenant", "acme"}},
+	})
+
+	assert.Equal(t, bson.D{{"$vectorSearch", bson.D{
+		{"index", "vector_index"},
+		{"path", "embedding"},
+		{"queryVector", query},
+		{"limit", 5},
+		{"numCandidates", 50},
+		{"filter", bson.D{{"tenant", "acme"}}},
+	}}}, stage)
+}
+
+func TestVectorSearchStage_Exact(t *testing.T) {
+	stage := mongodb.VectorSearchStage(mongodb.VectorSearchParams{
+		Index:         "emb",
+		Path:          "embedding",
+		QueryVector:   []float32{1},
+		Limit:         3,
+		NumCandidates: 100,
+		Exact:         true,
+	})
+
+	assert.Equal(t, bson.D{{"$vectorSearch", bson.D{
+		{"index", "emb"},
+		{"path", "embedding"},
+		{"queryVector", []float32{1}},
+		{"limit", 3},
+		{"exact", true},
+	}}}, stage)
+}
+
+func TestVectorSearchScoreStage(t *testing.T) {
+	assert.Equal(t, bson.D{{"$addFields", bson.D{{"score", bson.D{{"$meta", "vectorSearchScore"}}}}}},
+		mongodb.VectorSearchScoreStage("score"))
+}